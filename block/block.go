@@ -1,15 +1,28 @@
 package block
 
 import (
-	//"github.com/joeqian10/neo-gogogo/rpc/models"
+	"fmt"
+
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/io"
+	"github.com/joeqian10/neo3-gogogo/rpc"
 	"github.com/joeqian10/neo3-gogogo/tx"
 )
 
+const MaxTransactionsPerBlock = 0xffff
+
 type Block struct {
 	Header
 	Transactions []tx.Transaction
 }
 
+func NewBlock() *Block {
+	return &Block{
+		Header:       *NewBlockHeader(),
+		Transactions: []tx.Transaction{},
+	}
+}
+
 func (b *Block) GetSize() int {
 	sz := 0
 	for _, tx := range b.Transactions {
@@ -17,3 +30,87 @@ func (b *Block) GetSize() int {
 	}
 	return b.Header.GetSize() + sz
 }
+
+// IsGenesis returns true if the block is the first block of the chain
+func (b *Block) IsGenesis() bool {
+	return b.index == 0 && b.prevHash.Equals(helper.UInt256Zero)
+}
+
+func (b *Block) Deserialize(br *io.BinaryReader) {
+	b.Header.Deserialize(br)
+	if br.Err != nil {
+		return
+	}
+	if b.index == 0 && !b.prevHash.Equals(helper.UInt256Zero) {
+		br.Err = fmt.Errorf("format error: genesis block must not have a previous hash")
+		return
+	}
+	count := br.ReadVarUIntWithMaxLimit(MaxTransactionsPerBlock)
+	b.Transactions = make([]tx.Transaction, count) // the genesis block has no transactions
+	for i := range b.Transactions {
+		b.Transactions[i] = *tx.NewTransaction()
+		b.Transactions[i].Deserialize(br)
+	}
+	if br.Err != nil {
+		return
+	}
+	if !b.computeMerkleRoot().Equals(b.merkleRoot) {
+		br.Err = fmt.Errorf("format error: merkle root mismatch")
+	}
+}
+
+func (b *Block) Serialize(bw *io.BinaryWriter) {
+	b.Header.Serialize(bw)
+	bw.WriteVarUInt(uint64(len(b.Transactions)))
+	for i := range b.Transactions {
+		b.Transactions[i].Serialize(bw)
+	}
+}
+
+// computeMerkleRoot calculates the merkle root of the transaction hashes, which is zero if there is no transaction
+func (b *Block) computeMerkleRoot() *helper.UInt256 {
+	if len(b.Transactions) == 0 {
+		return helper.UInt256Zero
+	}
+	hashes := make([][]byte, len(b.Transactions))
+	for i := range b.Transactions {
+		hashes[i] = b.Transactions[i].GetHash().ToByteArray()
+	}
+	for len(hashes) > 1 {
+		if len(hashes)%2 == 1 {
+			hashes = append(hashes, hashes[len(hashes)-1])
+		}
+		parents := make([][]byte, len(hashes)/2)
+		for i := range parents {
+			parents[i] = helper.Hash256(append(append([]byte{}, hashes[2*i]...), hashes[2*i+1]...)).ToByteArray()
+		}
+		hashes = parents
+	}
+	return helper.UInt256FromBytes(hashes[0])
+}
+
+// GetGenesisBlock fetches the block at index 0 and parses it
+func GetGenesisBlock(client rpc.IRpcClient) (*Block, error) {
+	response := client.GetBlock("0")
+	if response.HasError() {
+		return nil, fmt.Errorf(response.GetErrorInfo())
+	}
+	if len(response.Result.Tx) != 0 {
+		return nil, fmt.Errorf("genesis block should have no transactions")
+	}
+	header, err := NewBlockHeaderFromRPC(&response.Result.RpcBlockHeader)
+	if err != nil {
+		return nil, err
+	}
+	b := &Block{
+		Header:       *header,
+		Transactions: []tx.Transaction{},
+	}
+	if !b.IsGenesis() {
+		return nil, fmt.Errorf("block %d is not the genesis block", b.index)
+	}
+	if !b.computeMerkleRoot().Equals(b.merkleRoot) {
+		return nil, fmt.Errorf("merkle root mismatch")
+	}
+	return b, nil
+}
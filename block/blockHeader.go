@@ -81,7 +81,6 @@ func NewBlockHeaderFromRPC(header *models.RpcBlockHeader) (*Header, error) {
 		nextConsensus: nextConsensus,
 		Witness:       witness,
 	}
-	fmt.Println(bh.GetHash().String())
 	if !bh.GetHash().Equals(hash) {
 		return nil, fmt.Errorf("wrong block hash")
 	}
@@ -0,0 +1,106 @@
+package block
+
+import (
+	"testing"
+
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/io"
+	"github.com/joeqian10/neo3-gogogo/rpc"
+	"github.com/joeqian10/neo3-gogogo/rpc/models"
+	"github.com/joeqian10/neo3-gogogo/tx"
+	"github.com/stretchr/testify/assert"
+)
+
+var genesisBlock = models.RpcBlock{
+	RpcBlockHeader: models.RpcBlockHeader{
+		Hash:              "0x3fcf333efb1c4749098b698b2faf010a1955f6e49e53c8f1af85968e09bd678a",
+		Size:              106,
+		Version:           0,
+		PreviousBlockHash: "0x0000000000000000000000000000000000000000000000000000000000000000",
+		MerkleRoot:        "0x0000000000000000000000000000000000000000000000000000000000000000",
+		Time:              1468595301000,
+		Index:             0,
+		PrimaryIndex:      0x00,
+		NextConsensus:     "NZs2zXSPuuv9ZF6TDGSWT1RBmE8rfGj7UW",
+		Witnesses: []models.RpcWitness{{
+			Invocation:   "",
+			Verification: "11",
+		}},
+		Confirmations: 5276880,
+		NextBlockHash: "0xd782db8a38b0eea0d7394e0f007c61c71798867578c77c387c08113903946cc9",
+	},
+	Tx: []models.RpcTransaction{},
+}
+
+func TestGetGenesisBlock(t *testing.T) {
+	var clientMock = new(rpc.RpcClientMock)
+	clientMock.On("GetBlock", "0").Return(rpc.GetBlockResponse{Result: genesisBlock})
+
+	b, err := GetGenesisBlock(clientMock)
+	assert.Nil(t, err)
+	assert.True(t, b.IsGenesis())
+	assert.Equal(t, 0, len(b.Transactions))
+	assert.Equal(t, "3fcf333efb1c4749098b698b2faf010a1955f6e49e53c8f1af85968e09bd678a", b.GetHash().String())
+
+	// round trip through the binary format
+	bw := io.NewBufBinaryWriter()
+	b.Serialize(bw.BinaryWriter)
+	assert.Nil(t, bw.Err)
+	b2 := NewBlock()
+	br := io.NewBinaryReaderFromBuf(bw.Bytes())
+	b2.Deserialize(br)
+	assert.Nil(t, br.Err)
+	assert.True(t, b2.IsGenesis())
+	assert.Equal(t, b.GetHash(), b2.GetHash())
+	assert.Equal(t, []byte{0x11}, b2.Witness.VerificationScript)
+}
+
+func TestBlock_Deserialize_InvalidGenesis(t *testing.T) {
+	b := NewBlock()
+	b.SetPrevHash(helper.UInt256FromBytes([]byte{0x01}))
+	bw := io.NewBufBinaryWriter()
+	b.Serialize(bw.BinaryWriter)
+	br := io.NewBinaryReaderFromBuf(bw.Bytes())
+	NewBlock().Deserialize(br)
+	assert.NotNil(t, br.Err)
+
+	// merkle root does not match the transactions
+	b = NewBlock()
+	b.SetIndex(1)
+	b.SetPrevHash(helper.UInt256FromBytes([]byte{0x01}))
+	trx := tx.NewTransaction()
+	trx.SetScript([]byte{0x11})
+	trx.SetSigners([]tx.Signer{{Account: helper.UInt160Zero, Scopes: tx.CalledByEntry}})
+	trx.SetWitnesses([]tx.Witness{{InvocationScript: []byte{}, VerificationScript: []byte{}}})
+	b.Transactions = []tx.Transaction{*trx}
+	bw = io.NewBufBinaryWriter()
+	b.Serialize(bw.BinaryWriter)
+	br = io.NewBinaryReaderFromBuf(bw.Bytes())
+	NewBlock().Deserialize(br)
+	assert.EqualError(t, br.Err, "format error: merkle root mismatch")
+}
+
+func TestGetGenesisBlock_NotGenesis(t *testing.T) {
+	block := genesisBlock
+	block.Tx = []models.RpcTransaction{{}}
+	var clientMock = new(rpc.RpcClientMock)
+	clientMock.On("GetBlock", "0").Return(rpc.GetBlockResponse{Result: block})
+	_, err := GetGenesisBlock(clientMock)
+	assert.NotNil(t, err)
+}
+
+func TestBlock_computeMerkleRoot(t *testing.T) {
+	b := NewBlock()
+	assert.Equal(t, helper.UInt256Zero, b.computeMerkleRoot())
+
+	trx := tx.NewTransaction()
+	trx.SetScript([]byte{0x11})
+	b.Transactions = []tx.Transaction{*trx}
+	assert.Equal(t, trx.GetHash(), b.computeMerkleRoot())
+
+	trx2 := tx.NewTransaction()
+	trx2.SetScript([]byte{0x12})
+	b.Transactions = append(b.Transactions, *trx2)
+	expected := helper.Hash256(append(trx.GetHash().ToByteArray(), trx2.GetHash().ToByteArray()...))
+	assert.Equal(t, expected, b.computeMerkleRoot())
+}
@@ -0,0 +1,83 @@
+package helper
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+const BASE58 = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// Base58Encode ... ref: neo/Cryptography/Base58.cs
+func Base58Encode(input []byte) string {
+	tmp := append([]byte{0}, input...) // input is big-endian
+	x := new(big.Int).SetBytes(tmp)
+	r := new(big.Int)
+	m := big.NewInt(58)
+	zero := big.NewInt(0)
+	encoded := ""
+
+	for x.Cmp(zero) > 0 {
+		x.QuoRem(x, m, r)
+		encoded = string(BASE58[r.Int64()]) + encoded
+	}
+	// leading zeros
+	for i := 0; i < len(input); i++ {
+		if input[i] == 0 {
+			encoded = string(BASE58[0]) + encoded
+		} else {
+			break
+		}
+	}
+	return encoded
+}
+
+// Base58Decode ...
+func Base58Decode(input string) ([]byte, error) {
+	// leading zeros
+	zeros := 0
+	for zeros < len(input) && input[zeros] == BASE58[0] {
+		zeros++
+	}
+	bi := big.NewInt(0)
+	base := big.NewInt(58)
+	for _, c := range input[zeros:] {
+		index := strings.IndexRune(BASE58, c)
+		if index == -1 {
+			return nil, fmt.Errorf(
+				"invalid character '%c' when decoding this base58 string: '%s'", c, input,
+			)
+		}
+		bi.Mul(bi, base)
+		bi.Add(bi, big.NewInt(int64(index)))
+	}
+	ba := bi.Bytes() // ba is big-endian
+	r := make([]byte, zeros+len(ba))
+	copy(r[zeros:], ba)
+	return r, nil
+}
+
+// Base58CheckEncode encodes the version byte and the payload with a 4-byte checksum,
+// it is used by addresses (version 0x35) and WIF (version 0x80)
+func Base58CheckEncode(version byte, payload []byte) string {
+	data := append([]byte{version}, payload...)
+	checksum := Hash256(data).ToByteArray()[:4]
+	return Base58Encode(append(data, checksum...))
+}
+
+// Base58CheckDecode decodes a Base58Check string and verifies its checksum
+func Base58CheckDecode(s string) (version byte, payload []byte, err error) {
+	data, err := Base58Decode(s)
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(data) < 5 {
+		return 0, nil, fmt.Errorf("invalid base58 check string: too short")
+	}
+	checksum := Hash256(data[:len(data)-4]).ToByteArray()[:4]
+	if !bytes.Equal(checksum, data[len(data)-4:]) {
+		return 0, nil, fmt.Errorf("invalid base58 check string: invalid checksum")
+	}
+	return data[0], data[1 : len(data)-4], nil
+}
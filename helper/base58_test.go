@@ -0,0 +1,43 @@
+package helper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBase58CheckEncodeDecode(t *testing.T) {
+	// address
+	scriptHash := HexToBytes("9903b0c3d292988febe5f306a02f654ea2eb1629")
+	address := Base58CheckEncode(DefaultAddressVersion, scriptHash)
+	assert.Equal(t, "NZs2zXSPuuv9ZF6TDGSWT1RBmE8rfGj7UW", address)
+	version, payload, err := Base58CheckDecode(address)
+	assert.Nil(t, err)
+	assert.Equal(t, DefaultAddressVersion, version)
+	assert.Equal(t, scriptHash, payload)
+
+	// wif
+	wif := "KxhEDBQyyEFymvfJD96q8stMbJMbZUb6D1PmXqBWZDU2WvbvVs9o"
+	version, payload, err = Base58CheckDecode(wif)
+	assert.Nil(t, err)
+	assert.Equal(t, byte(0x80), version)
+	assert.Equal(t, "2bfe58ab6d9fd575bdc3a624e4825dd2b375d64ac033fbc46ea79dbab4f69a3e01", BytesToHex(payload))
+	assert.Equal(t, wif, Base58CheckEncode(version, payload))
+
+	// leading zeros are kept
+	encoded := Base58CheckEncode(0x00, []byte{0x00, 0x01})
+	assert.Equal(t, byte('1'), encoded[0])
+	version, payload, err = Base58CheckDecode(encoded)
+	assert.Nil(t, err)
+	assert.Equal(t, byte(0x00), version)
+	assert.Equal(t, []byte{0x00, 0x01}, payload)
+}
+
+func TestBase58CheckDecode_Failures(t *testing.T) {
+	_, _, err := Base58CheckDecode("KxhEDBQyyEFymvfJD96q8stMbJMbZUb6D1PmXqBWZDU2WvbvVs9A") // bad checksum
+	assert.NotNil(t, err)
+	_, _, err = Base58CheckDecode("BASE%*")
+	assert.NotNil(t, err)
+	_, _, err = Base58CheckDecode("THqY")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,22 @@
+package helper
+
+import (
+	"crypto/sha256"
+
+	"golang.org/x/crypto/ripemd160"
+)
+
+// Hash160 calculates RIPEMD-160(SHA-256(b)), which is the script hash of b
+func Hash160(b []byte) *UInt160 {
+	sha := sha256.Sum256(b)
+	ripemd := ripemd160.New()
+	ripemd.Write(sha[:])
+	return UInt160FromBytes(ripemd.Sum(nil))
+}
+
+// Hash256 calculates SHA-256(SHA-256(b)), which is the hash of transactions and blocks
+func Hash256(b []byte) *UInt256 {
+	sha := sha256.Sum256(b)
+	sha = sha256.Sum256(sha[:])
+	return UInt256FromBytes(sha[:])
+}
@@ -0,0 +1,26 @@
+package helper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHash160(t *testing.T) {
+	u := Hash160([]byte("hello world"))
+	assert.Equal(t, "d7d5ee7824ff93f94c3055af9382c86c68b5ca92", BytesToHex(u.ToByteArray()))
+	assert.Equal(t, "92cab5686cc88293af55304cf993ff2478eed5d7", u.String())
+
+	// script hash of a verification script
+	u = Hash160(HexToBytes("2103322f35c7819267e721335948d385fae5be66e7ba8c748ac15467dcca0693692dac"))
+	assert.Equal(t, "71cb588c8291c18fa87fa07ce16c3fd92ab5aa30", u.String())
+}
+
+func TestHash256(t *testing.T) {
+	u := Hash256([]byte("hello world"))
+	assert.Equal(t, "bc62d4b80d9e36da29c16c5d4d9f11731f36052c72401a76c23c0fb5a9b74423", BytesToHex(u.ToByteArray()))
+	assert.Equal(t, "2344b7a9b50f3cc2761a40722c05361f73119f4d5d6cc129da369e0db8d462bc", u.String())
+
+	u = Hash256([]byte{})
+	assert.Equal(t, "5df6e0e2761359d30a8275058e299fcc0381534545f55cf43e41983f5d4c9456", BytesToHex(u.ToByteArray()))
+}
@@ -1,9 +1,15 @@
 package helper
 
 const (
-	Neo3Magic_MainNet     uint32 = 5195086 // 0x4F454Eu
+	// Deprecated: the magic of the preview MainNet, use N3Magic_MainNet
+	Neo3Magic_MainNet uint32 = 5195086 // 0x4F454Eu
+	// Deprecated: the magic of a preview TestNet, use N3Magic_TestNet
 	Neo3Magic_TestNet     uint32 = 1951352142
 	DefaultAddressVersion byte   = 0x35
+
+	// the network magics of the N3 MainNet and the N3 TestNet since 3.0.0
+	N3Magic_MainNet uint32 = 860833102 // 0x334F454E
+	N3Magic_TestNet uint32 = 894710606 // 0x3554334E
 )
 
 type ProtocolSettings struct {
@@ -11,7 +17,9 @@ type ProtocolSettings struct {
 	AddressVersion byte
 }
 
+// DefaultProtocolSettings are the settings of the N3 MainNet, its Magic was the preview Neo3Magic_MainNet
+// before, set the Magic explicitly to sign for another network
 var DefaultProtocolSettings = ProtocolSettings{
-	Magic:          Neo3Magic_MainNet,
+	Magic:          N3Magic_MainNet,
 	AddressVersion: DefaultAddressVersion,
 }
@@ -0,0 +1,66 @@
+package keys
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"runtime"
+	"sync"
+
+	"github.com/joeqian10/neo3-gogogo/crypto"
+)
+
+// SignatureItem is a signature with the public key and the sha256 hash of the signed message
+type SignatureItem struct {
+	PublicKey *crypto.ECPoint
+	Hash      []byte
+	Signature []byte
+}
+
+// VerifySignatureHash returns true if the signature of the sha256 hash is valid for the public key
+func VerifySignatureHash(hash []byte, signature []byte, p *crypto.ECPoint) bool {
+	if p == nil || p.X == nil || p.Y == nil || len(signature) != 64 {
+		return false
+	}
+	r := new(big.Int).SetBytes(signature[0:32])
+	s := new(big.Int).SetBytes(signature[32:64])
+	return ecdsa.Verify(p.ToECDsa(), hash, r, s)
+}
+
+// BatchVerify verifies the signatures with at most workers goroutines, workers <= 0 means
+// the number of CPUs. The result of each item is at the same index of the returned slice
+func BatchVerify(items []SignatureItem, workers int) []bool {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if workers > len(items) {
+		workers = len(items)
+	}
+	results := make([]bool, len(items))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i] = VerifySignatureHash(items[i].Hash, items[i].Signature, items[i].PublicKey)
+			}
+		}()
+	}
+	for i := range items {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	return results
+}
+
+// BatchVerifyAll returns true if all the signatures are valid
+func BatchVerifyAll(items []SignatureItem, workers int) bool {
+	for _, ok := range BatchVerify(items, workers) {
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
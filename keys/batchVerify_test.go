@@ -0,0 +1,34 @@
+package keys
+
+import (
+	"crypto/sha256"
+	"testing"
+
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchVerify(t *testing.T) {
+	items := make([]SignatureItem, 0)
+	for i, testCase := range KeyCases {
+		pair, err := NewKeyPair(helper.HexToBytes(testCase.PrivateKey))
+		assert.Nil(t, err)
+		for j := 0; j < 5; j++ {
+			hash := sha256.Sum256([]byte{byte(i), byte(j)})
+			signature, err := pair.SignHash(hash[:])
+			assert.Nil(t, err)
+			items = append(items, SignatureItem{PublicKey: pair.PublicKey, Hash: hash[:], Signature: signature})
+		}
+	}
+	assert.True(t, BatchVerifyAll(items, 3))
+
+	// the signature of another message
+	invalid := 4
+	items[invalid].Signature = items[invalid+1].Signature
+	results := BatchVerify(items, 3)
+	for i, ok := range results {
+		assert.Equal(t, i != invalid, ok)
+	}
+	assert.False(t, BatchVerifyAll(items, 0))
+	assert.Equal(t, 0, len(BatchVerify(nil, 4)))
+}
@@ -0,0 +1,38 @@
+package keys
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+
+	"github.com/joeqian10/neo3-gogogo/crypto"
+)
+
+// deriveChildLabel separates the derivation from other uses of the master secret
+const deriveChildLabel = "neo3-gogogo child key"
+
+// DeriveChild derives a reproducible key pair from a master secret and an index. It is not BIP-32:
+// the private key is HMAC-SHA256(master, label || index || counter) in big-endian, where the counter
+// starts from 0 and is increased until the result is a valid private key in [1, n-1]. Anyone with
+// the master secret can derive every child, so the master must be kept as secret as a private key
+func DeriveChild(master []byte, index uint32) (*KeyPair, error) {
+	if len(master) < 16 {
+		return nil, fmt.Errorf("master secret is too short: %d", len(master))
+	}
+	n := crypto.P256.Params().N
+	msg := make([]byte, len(deriveChildLabel)+8)
+	copy(msg, deriveChildLabel)
+	binary.BigEndian.PutUint32(msg[len(deriveChildLabel):], index)
+	for counter := uint32(0); ; counter++ {
+		binary.BigEndian.PutUint32(msg[len(deriveChildLabel)+4:], counter)
+		mac := hmac.New(sha256.New, master)
+		mac.Write(msg)
+		privateKey := mac.Sum(nil)
+		d := new(big.Int).SetBytes(privateKey)
+		if d.Sign() > 0 && d.Cmp(n) < 0 {
+			return NewKeyPair(privateKey)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package keys
+
+import (
+	"testing"
+
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeriveChild(t *testing.T) {
+	master := helper.HexToBytes(KeyCases[0].PrivateKey)
+	child1, err := DeriveChild(master, 1)
+	assert.Nil(t, err)
+	again, err := DeriveChild(master, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, child1.PrivateKey, again.PrivateKey)
+	assert.Equal(t, "5fccb35ca78fb95d84c9b4b62cf60a58fb6c28e9baf91a4513d93face494cc04", helper.BytesToHex(child1.PrivateKey))
+	assert.True(t, child1.PublicKey.Equals(again.PublicKey))
+
+	child2, err := DeriveChild(master, 2)
+	assert.Nil(t, err)
+	assert.NotEqual(t, child1.PrivateKey, child2.PrivateKey)
+
+	other, err := DeriveChild(helper.HexToBytes(KeyCases[1].PrivateKey), 1)
+	assert.Nil(t, err)
+	assert.NotEqual(t, child1.PrivateKey, other.PrivateKey)
+
+	_, err = DeriveChild([]byte{0x01}, 1)
+	assert.NotNil(t, err)
+}
@@ -50,16 +50,14 @@ func NewKeyPairFromWIF(wif string) (*KeyPair, error) {
 	if wif == "" {
 		return nil, fmt.Errorf("wif string is empty")
 	}
-	data, err := crypto.Base58CheckDecode(wif)
+	version, payload, err := helper.Base58CheckDecode(wif)
 	if err != nil {
 		return nil, err
 	}
-	length := len(data)
-	if length != 34 || data[0] != 0x80 || data[33] != 0x01 {
+	if len(payload) != 33 || version != 0x80 || payload[32] != 0x01 {
 		return nil, fmt.Errorf("invalid parameter format")
 	}
-	privateKey := data[1:33]
-	return NewKeyPair(privateKey)
+	return NewKeyPair(payload[:32])
 }
 
 func NewKeyPairFromNEP2(nep2 string, passphrase string, version byte, N, R, P int) (*KeyPair, error) {
@@ -136,12 +134,7 @@ func ToECDsa(key []byte) *ecdsa.PrivateKey {
 
 // export wif string
 func (p *KeyPair) Export() string {
-	data := make([]byte, 34)
-	data[0] = 0x80
-	copy(data[1:], p.PrivateKey)
-	data[33] = 0x01
-	wif := crypto.Base58CheckEncode(data)
-	return wif
+	return helper.Base58CheckEncode(0x80, append(append([]byte{}, p.PrivateKey...), 0x01))
 }
 
 // export nep2 key string
@@ -178,9 +171,14 @@ func (p *KeyPair) String() string {
 
 // sign message with KeyPair
 func (p *KeyPair) Sign(message []byte) ([]byte, error) {
-	privateKey := p.ToECDsa()
 	hash := sha256.Sum256(message)
-	r, s, err := ecdsa.Sign(rand.Reader, privateKey, hash[:])
+	return p.SignHash(hash[:])
+}
+
+// SignHash signs the sha256 hash of a message, the signature is the same as Sign on the message
+func (p *KeyPair) SignHash(hash []byte) ([]byte, error) {
+	privateKey := p.ToECDsa()
+	r, s, err := ecdsa.Sign(rand.Reader, privateKey, hash)
 
 	if err != nil {
 		return nil, err
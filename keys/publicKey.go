@@ -17,3 +17,16 @@ func PublicKeyToScriptHash(p *crypto.ECPoint) *helper.UInt160 {
 func PublicKeyToAddress(p *crypto.ECPoint, version byte) string {
 	return crypto.ScriptHashToAddress(PublicKeyToScriptHash(p), version)
 }
+
+// AddressMatchesPublicKey returns true if the address is the standard account of the public key,
+// it returns false for a nil key or an address that is invalid or of another version
+func AddressMatchesPublicKey(address string, p *crypto.ECPoint, version byte) bool {
+	if p == nil {
+		return false
+	}
+	scriptHash, err := crypto.AddressToScriptHash(address, version)
+	if err != nil {
+		return false
+	}
+	return scriptHash.Equals(PublicKeyToScriptHash(p))
+}
@@ -25,3 +25,14 @@ func TestPublicKeyToAddress(t *testing.T) {
 		assert.Equal(t, testCase.Address, address)
 	}
 }
+
+func TestAddressMatchesPublicKey(t *testing.T) {
+	p0, _ := crypto.NewECPointFromString(KeyCases[0].PublicKey)
+	p1, _ := crypto.NewECPointFromString(KeyCases[1].PublicKey)
+	assert.True(t, AddressMatchesPublicKey(KeyCases[0].Address, p0, helper.DefaultAddressVersion))
+	assert.True(t, AddressMatchesPublicKey(KeyCases[1].Address, p1, helper.DefaultAddressVersion))
+	assert.False(t, AddressMatchesPublicKey(KeyCases[0].Address, p1, helper.DefaultAddressVersion))
+	assert.False(t, AddressMatchesPublicKey(KeyCases[0].Address, p0, 0x17))
+	assert.False(t, AddressMatchesPublicKey("invalid", p0, helper.DefaultAddressVersion))
+	assert.False(t, AddressMatchesPublicKey(KeyCases[0].Address, nil, helper.DefaultAddressVersion))
+}
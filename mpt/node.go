@@ -1,6 +1,8 @@
 package mpt
 
 import (
+	"fmt"
+
 	"github.com/joeqian10/neo3-gogogo/crypto"
 	"github.com/joeqian10/neo3-gogogo/helper"
 	"github.com/joeqian10/neo3-gogogo/sc"
@@ -273,7 +275,7 @@ func (n *Node) Deserialize(br *io.BinaryReader)  {
 	case Empty:
 		break
 	default:
-		panic(n.nodeType)
+		br.Err = fmt.Errorf("invalid node type: %d", n.nodeType)
 	}
 }
 
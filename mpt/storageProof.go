@@ -0,0 +1,44 @@
+package mpt
+
+import (
+	"fmt"
+
+	"github.com/joeqian10/neo3-gogogo/crypto"
+	"github.com/joeqian10/neo3-gogogo/helper"
+)
+
+// StorageProof is the proof of a storage key returned by getproof
+type StorageProof struct {
+	Id    int      // the id of the contract
+	Key   []byte   // the key without the contract id
+	Value []byte   // the value of the key, resolved from the path
+	Path  [][]byte // the serialized nodes from the root of the trie to the leaf of the key
+}
+
+// NewStorageProofFromBytes decodes the proof returned by getproof and resolves the value of the key by walking
+// its path. The proof is not checked against any state root, see Verify
+func NewStorageProofFromBytes(proof []byte) (*StorageProof, error) {
+	id, key, path, err := ResolveProof(proof)
+	if err != nil {
+		return nil, err
+	}
+	if len(path) == 0 {
+		return nil, fmt.Errorf("proof has no nodes")
+	}
+	p := &StorageProof{Id: id, Key: key, Path: path}
+	p.Value, err = VerifyProof(p.RootHash(), id, key, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve the value from the path: %v", err)
+	}
+	return p, nil
+}
+
+// RootHash returns the hash of the first node of the path, which is the root of the trie it was made from
+func (p *StorageProof) RootHash() *helper.UInt256 {
+	return helper.UInt256FromBytes(crypto.Hash256(p.Path[0]))
+}
+
+// Verify tells whether the proof is made from the trie of the trusted state root
+func (p *StorageProof) Verify(root *helper.UInt256) bool {
+	return root != nil && p.RootHash().Equals(root)
+}
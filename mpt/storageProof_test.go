@@ -0,0 +1,37 @@
+package mpt
+
+import (
+	"testing"
+
+	"github.com/joeqian10/neo3-gogogo/crypto"
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewStorageProofFromBytes(t *testing.T) {
+	proofStr := "Bfv///8XBiQBAQ8DRzb6Vkdw0r5nxMBp6Z5nvbyXiupMvffwm0v5GdB6jHvyAAQEBAQEBAQEA7l84HFtRI5V11s58vA+8CZ5GArFLkGUYLO98RLaMaYmA5MEnx0upnVI45XTpoUDRvwrlPD59uWy9aIrdS4T0D2cA6Rwv/l3GmrctRzL1me+iTUFdDgooaz+esFHFXJdDANfA2bdshZMp5ox2goVAOMjvoxNIWWOqjJoRPu6ZOw2kdj6A8xovEK1Mp6cAG9z/jfFDrSEM60kuo97MNaVOP/cDZ1wA1nf4WdI+jksYz0EJgzBukK8rEzz8jE2cb2Zx2fytVyQBANC7v2RaLMCRF1XgLpSri12L2IwL9Zcjz5LZiaB5nHKNgQpAQYPDw8PDw8DggFffnsVMyqAfZjg+4gu97N/gKpOsAK8Q27s56tijRlSAAMm26DYxOdf/IjEgkE/u/CoRL6dDnzvs1dxCg/00esMvgPGioeOqQCkDOTfliOnCxYjbY/0XvVUOXkceuDm1W0FzQQEBAQEBAQEBAQEBAQEBJIABAPH1PnX/P8NOgV4KHnogwD7xIsD8KvNhkTcDxgCo7Ec6gPQs1zD4igSJB4M9jTREq+7lQ5PbTH/6d138yUVvtM8bQP9Df1kh7asXrYjZolKhLcQ1NoClQgEzbcJfYkCHXv6DQQEBAOUw9zNl/7FJrWD7rCv0mbOoy6nLlHWiWuyGsA12ohRuAQEBAQEBAQEBAYCBAIAAgA="
+	proofData, err := crypto.Base64Decode(proofStr)
+	assert.Nil(t, err)
+	root, _ := helper.UInt256FromString("0x7bf925dbd33af0e00d392b92313da59369ed86c82494d0e02040b24faac0a3ca")
+
+	proof, err := NewStorageProofFromBytes(proofData)
+	assert.Nil(t, err)
+	assert.Equal(t, -5, proof.Id)
+	assert.Equal(t, []byte{0x17}, proof.Key)
+	assert.Equal(t, "AAI=", crypto.Base64Encode(proof.Value))
+	assert.Equal(t, 6, len(proof.Path))
+	assert.True(t, root.Equals(proof.RootHash()))
+	assert.True(t, proof.Verify(root))
+	assert.False(t, proof.Verify(helper.UInt256Zero))
+	assert.False(t, proof.Verify(nil))
+
+	// a tampered node no longer matches the hash referenced by its parent
+	tampered := make([]byte, len(proofData))
+	copy(tampered, proofData)
+	tampered[len(tampered)-3] ^= 0x01
+	_, err = NewStorageProofFromBytes(tampered)
+	assert.NotNil(t, err)
+
+	_, err = NewStorageProofFromBytes([]byte{0x05})
+	assert.NotNil(t, err)
+}
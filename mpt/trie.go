@@ -3,6 +3,7 @@ package mpt
 import (
 	"bytes"
 	"errors"
+	"fmt"
 	"github.com/joeqian10/neo3-gogogo/blockchain"
 	"github.com/joeqian10/neo3-gogogo/helper"
 	nio "github.com/joeqian10/neo3-gogogo/io"
@@ -101,6 +102,66 @@ func (t *Trie) get(n *Node, path []byte) (*Node, error) {
 	return nil, errors.New("invalid node or path for the trie")
 }
 
+// KeyValue is a key value pair found in the trie
+type KeyValue struct {
+	Key   []byte
+	Value []byte
+}
+
+// Find returns the key value pairs whose keys start with the prefix, in the order of keys. Only the nodes
+// on the way to the prefix and under it are resolved, and an error is returned if any of them is missing in
+// the db, so with a ProofDb the result is complete if and only if no error is returned
+func (t *Trie) Find(prefix []byte) ([]KeyValue, error) {
+	results := []KeyValue{}
+	err := t.find(t.root, []byte{}, ToNibbles(prefix), &results)
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (t *Trie) find(n *Node, path []byte, prefix []byte, results *[]KeyValue) error {
+	switch n.nodeType {
+	case LeafNode:
+		if bytes.HasPrefix(path, prefix) {
+			*results = append(*results, KeyValue{Key: FromNibbles(path), Value: n.Value})
+		}
+	case Empty:
+	case HashNode:
+		nn, err := t.resolve(n.hash)
+		if err != nil {
+			return err
+		}
+		return t.find(nn, path, prefix, results)
+	case BranchNode:
+		// the value of the path itself comes first
+		if err := t.find(&n.Children[BranchChildCount-1], path, prefix, results); err != nil {
+			return err
+		}
+		for i := 0; i < BranchChildCount-1; i++ {
+			p := append(append([]byte{}, path...), byte(i))
+			if !n.Children[i].IsEmpty() && matchesPrefix(p, prefix) {
+				if err := t.find(&n.Children[i], p, prefix, results); err != nil {
+					return err
+				}
+			}
+		}
+	case ExtensionNode:
+		p := append(append([]byte{}, path...), n.Key...)
+		if matchesPrefix(p, prefix) {
+			return t.find(n.Next, p, prefix, results)
+		}
+	default:
+		return errors.New("invalid node for the trie")
+	}
+	return nil
+}
+
+// matchesPrefix tells whether the keys under the path may start with the prefix
+func matchesPrefix(path []byte, prefix []byte) bool {
+	return bytes.HasPrefix(path, prefix) || bytes.HasPrefix(prefix, path)
+}
+
 //VerifyProof directly verify proof
 func VerifyProof(root *helper.UInt256, id int, key []byte, proof [][]byte) ([]byte, error) {
 	sKey := blockchain.StorageKey{
@@ -123,6 +184,70 @@ func VerifyProof(root *helper.UInt256, id int, key []byte, proof [][]byte) ([]by
 	return resolveValue(value)
 }
 
+// VerifyStateProof verifies the proof returned by getproof against a trusted state root locally, instead of
+// trusting verifyproof of the node. The storage key is made of the contract id, which can be found by
+// getcontractstate of the contract hash, and the key. ok is false if the proof is for another key or cannot
+// prove the key under the root, err is only returned if the proof cannot be decoded
+func VerifyStateProof(rootHash *helper.UInt256, proof []byte, contractId int, key []byte) (value []byte, ok bool, err error) {
+	id, k, nodes, err := ResolveProof(proof)
+	if err != nil {
+		return nil, false, err
+	}
+	if id != contractId || !bytes.Equal(k, key) {
+		return nil, false, nil
+	}
+	value, err = VerifyProof(rootHash, id, k, nodes)
+	if err != nil {
+		return nil, false, nil
+	}
+	return value, true, nil
+}
+
+// FindStates returns the storage of the contract whose keys start with the prefix under the root, walking the
+// trie made of the nodes in the proofs of getproof. The keys are returned without the contract id, and the
+// values are resolved from storage items. An error is returned if the proofs do not cover every key under the
+// prefix, which means a key was left out by the node they come from
+func FindStates(root *helper.UInt256, contractId int, prefix []byte, proofs [][]byte) ([]KeyValue, error) {
+	sKey := blockchain.StorageKey{
+		Id:  contractId,
+		Key: prefix,
+	}
+	vkey, err := nio.ToArray(&sKey)
+	if err != nil {
+		return nil, err
+	}
+	nodes := [][]byte{}
+	for _, proof := range proofs {
+		id, _, p, err := ResolveProof(proof)
+		if err != nil {
+			return nil, err
+		}
+		if id != contractId {
+			return nil, fmt.Errorf("proof is for contract %d, not %d", id, contractId)
+		}
+		nodes = append(nodes, p...)
+	}
+	trie, err := NewTrie(root, NewProofDb(nodes))
+	if err != nil {
+		return nil, err
+	}
+	kvs, err := trie.Find(vkey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find states under the prefix: %v", err)
+	}
+	for i := range kvs {
+		_, kvs[i].Key, err = resolveKey(kvs[i].Key)
+		if err != nil {
+			return nil, err
+		}
+		kvs[i].Value, err = resolveValue(kvs[i].Value)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return kvs, nil
+}
+
 //ResolveProof get key and proofs from proofdata
 func ResolveProof(proofBytes []byte) (id int, key []byte, proof [][]byte, err error) {
 	br := nio.NewBinaryReaderFromBuf(proofBytes)
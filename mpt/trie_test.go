@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/io"
 )
 
 func TestVerifyProof(t *testing.T) {
@@ -23,3 +24,139 @@ func TestVerifyProof(t *testing.T) {
 
 	assert.Equal(t, "AAI=", crypto.Base64Encode(value))
 }
+
+func TestVerifyStateProof(t *testing.T) {
+	proofStr := "Bfv///8XBiQBAQ8DRzb6Vkdw0r5nxMBp6Z5nvbyXiupMvffwm0v5GdB6jHvyAAQEBAQEBAQEA7l84HFtRI5V11s58vA+8CZ5GArFLkGUYLO98RLaMaYmA5MEnx0upnVI45XTpoUDRvwrlPD59uWy9aIrdS4T0D2cA6Rwv/l3GmrctRzL1me+iTUFdDgooaz+esFHFXJdDANfA2bdshZMp5ox2goVAOMjvoxNIWWOqjJoRPu6ZOw2kdj6A8xovEK1Mp6cAG9z/jfFDrSEM60kuo97MNaVOP/cDZ1wA1nf4WdI+jksYz0EJgzBukK8rEzz8jE2cb2Zx2fytVyQBANC7v2RaLMCRF1XgLpSri12L2IwL9Zcjz5LZiaB5nHKNgQpAQYPDw8PDw8DggFffnsVMyqAfZjg+4gu97N/gKpOsAK8Q27s56tijRlSAAMm26DYxOdf/IjEgkE/u/CoRL6dDnzvs1dxCg/00esMvgPGioeOqQCkDOTfliOnCxYjbY/0XvVUOXkceuDm1W0FzQQEBAQEBAQEBAQEBAQEBJIABAPH1PnX/P8NOgV4KHnogwD7xIsD8KvNhkTcDxgCo7Ec6gPQs1zD4igSJB4M9jTREq+7lQ5PbTH/6d138yUVvtM8bQP9Df1kh7asXrYjZolKhLcQ1NoClQgEzbcJfYkCHXv6DQQEBAOUw9zNl/7FJrWD7rCv0mbOoy6nLlHWiWuyGsA12ohRuAQEBAQEBAQEBAYCBAIAAgA="
+	proof, err := crypto.Base64Decode(proofStr)
+	assert.Nil(t, err)
+	root, _ := helper.UInt256FromString("0x7bf925dbd33af0e00d392b92313da59369ed86c82494d0e02040b24faac0a3ca")
+
+	// key 0x17 of NeoToken, whose id is -5
+	value, ok, err := VerifyStateProof(root, proof, -5, []byte{0x17})
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "AAI=", crypto.Base64Encode(value))
+
+	// the proof is for another key
+	_, ok, err = VerifyStateProof(root, proof, -6, []byte{0x17})
+	assert.Nil(t, err)
+	assert.False(t, ok)
+	_, ok, err = VerifyStateProof(root, proof, -5, []byte{0x18})
+	assert.Nil(t, err)
+	assert.False(t, ok)
+
+	// another root
+	_, ok, err = VerifyStateProof(helper.UInt256Zero, proof, -5, []byte{0x17})
+	assert.Nil(t, err)
+	assert.False(t, ok)
+
+	// a tampered node no longer matches the hash referenced by its parent
+	tampered := make([]byte, len(proof))
+	copy(tampered, proof)
+	tampered[len(tampered)-3] ^= 0x01
+	_, ok, _ = VerifyStateProof(root, tampered, -5, []byte{0x17})
+	assert.False(t, ok)
+
+	_, ok, err = VerifyStateProof(root, []byte{0x05}, -5, []byte{0x17})
+	assert.NotNil(t, err)
+	assert.False(t, ok)
+}
+
+func TestDecodeNode_InvalidType(t *testing.T) {
+	_, err := decodeNode([]byte{0x09})
+	assert.NotNil(t, err)
+}
+
+// newStorageLeaf returns a leaf node holding the value as a storage item
+func newStorageLeaf(value string) *Node {
+	bbw := io.NewBufBinaryWriter()
+	bbw.WriteVarBytes([]byte(value))
+	return NewLeafNode(bbw.Bytes())
+}
+
+// newProof serializes the proof of the storage key made of the nodes as getproof does
+func newProof(storageKey []byte, nodes ...*Node) []byte {
+	bbw := io.NewBufBinaryWriter()
+	bbw.WriteVarBytes(storageKey)
+	bbw.WriteVarUInt(uint64(len(nodes)))
+	for _, n := range nodes {
+		bbw.WriteVarBytes(n.ToArrayWithoutReference())
+	}
+	return bbw.Bytes()
+}
+
+// newStorageTrie builds a small trie holding 0x0101: a, 0x0102: b and 0x02: c of contract 1,
+// and 0x01: d of contract 2, and returns its root and the proofs of the keys
+func newStorageTrie() (*helper.UInt256, map[string][]byte) {
+	a, b, c, d := newStorageLeaf("a"), newStorageLeaf("b"), newStorageLeaf("c"), newStorageLeaf("d")
+	b3 := NewBranchNode()
+	b3.Children[1] = *a
+	b3.Children[2] = *b
+	e3 := NewExtensionNode([]byte{0}, b3)
+	b2 := NewBranchNode()
+	b2.Children[1] = *e3
+	b2.Children[2] = *c
+	e2 := NewExtensionNode([]byte{0, 0, 0, 0, 0, 0, 0}, b2)
+	ed := NewExtensionNode([]byte{0, 0, 0, 0, 0, 0, 0, 1}, d)
+	b1 := NewBranchNode()
+	b1.Children[1] = *e2
+	b1.Children[2] = *ed
+	root := NewExtensionNode([]byte{0}, b1)
+
+	proofs := map[string][]byte{
+		"a": newProof([]byte{1, 0, 0, 0, 1, 1}, root, b1, e2, b2, e3, b3, a),
+		"b": newProof([]byte{1, 0, 0, 0, 1, 2}, root, b1, e2, b2, e3, b3, b),
+		"c": newProof([]byte{1, 0, 0, 0, 2}, root, b1, e2, b2, c),
+		"d": newProof([]byte{2, 0, 0, 0, 1}, root, b1, ed, d),
+	}
+	return root.GetHash(), proofs
+}
+
+func TestTrie_Find(t *testing.T) {
+	root, proofs := newStorageTrie()
+	_, _, nodes, err := ResolveProof(proofs["c"])
+	assert.Nil(t, err)
+	trie, err := NewTrie(root, NewProofDb(nodes))
+	assert.Nil(t, err)
+
+	// only the way to c is known
+	kvs, err := trie.Find([]byte{1, 0, 0, 0, 2})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(kvs))
+	assert.Equal(t, []byte{1, 0, 0, 0, 2}, kvs[0].Key)
+	_, err = trie.Find([]byte{1, 0, 0, 0})
+	assert.NotNil(t, err)
+}
+
+func TestFindStates(t *testing.T) {
+	root, proofs := newStorageTrie()
+
+	kvs, err := FindStates(root, 1, []byte{}, [][]byte{proofs["c"], proofs["b"], proofs["a"]})
+	assert.Nil(t, err)
+	assert.Equal(t, []KeyValue{
+		{Key: []byte{1, 1}, Value: []byte("a")},
+		{Key: []byte{1, 2}, Value: []byte("b")},
+		{Key: []byte{2}, Value: []byte("c")},
+	}, kvs)
+
+	kvs, err = FindStates(root, 1, []byte{1}, [][]byte{proofs["a"], proofs["b"]})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(kvs))
+
+	kvs, err = FindStates(root, 2, []byte{}, [][]byte{proofs["d"]})
+	assert.Nil(t, err)
+	assert.Equal(t, []KeyValue{{Key: []byte{1}, Value: []byte("d")}}, kvs)
+
+	// b is left out
+	_, err = FindStates(root, 1, []byte{1}, [][]byte{proofs["a"]})
+	assert.NotNil(t, err)
+	// c is left out
+	_, err = FindStates(root, 1, []byte{}, [][]byte{proofs["a"], proofs["b"]})
+	assert.NotNil(t, err)
+	// the proof of another contract
+	_, err = FindStates(root, 1, []byte{}, [][]byte{proofs["d"]})
+	assert.NotNil(t, err)
+	// another root
+	_, err = FindStates(helper.UInt256Zero, 1, []byte{}, [][]byte{proofs["a"], proofs["b"], proofs["c"]})
+	assert.NotNil(t, err)
+}
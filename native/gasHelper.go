@@ -0,0 +1,26 @@
+package native
+
+import (
+	"math/big"
+
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/sc"
+	"github.com/joeqian10/neo3-gogogo/tx"
+)
+
+// MakeBurnGasScript makes the script burning the amount of GAS in the smallest unit
+// with System.Runtime.BurnGas, the amount is charged as system fee
+func MakeBurnGasScript(amount *big.Int) ([]byte, error) {
+	sb := sc.NewScriptBuilder()
+	sb.EmitBurnGas(amount)
+	return sb.ToArray()
+}
+
+// MakeRefuelScript makes the script calling GAS.refuel, which burns the amount of GAS from
+// account and adds it to the gas limit of the running transaction
+func MakeRefuelScript(account *helper.UInt160, amount *big.Int) ([]byte, error) {
+	return sc.MakeScript(tx.GasToken, "refuel", []interface{}{
+		sc.ContractParameter{Type: sc.Hash160, Value: account},
+		amount,
+	})
+}
@@ -0,0 +1,37 @@
+package native
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/sc"
+	"github.com/joeqian10/neo3-gogogo/tx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMakeBurnGasScript(t *testing.T) {
+	amount := big.NewInt(100000000)
+	script, err := MakeBurnGasScript(amount)
+	assert.Nil(t, err)
+	expected := append([]byte{byte(sc.PUSHINT32)}, helper.UInt32ToBytes(100000000)...)
+	expected = append(expected, byte(sc.SYSCALL))
+	expected = append(expected, helper.UInt32ToBytes(uint32(sc.System_Runtime_BurnGas.ToInteropMethodHash()))...)
+	assert.Equal(t, expected, script)
+	assert.Equal(t, uint(0xbc8c5ac3), sc.System_Runtime_BurnGas.ToInteropMethodHash())
+
+	_, err = MakeBurnGasScript(big.NewInt(0))
+	assert.NotNil(t, err)
+}
+
+func TestMakeRefuelScript(t *testing.T) {
+	account, _ := helper.UInt160FromString("0x2916eba24e652fa006f3e5eb8f9892d2c3b00399")
+	script, err := MakeRefuelScript(account, big.NewInt(100000000))
+	assert.Nil(t, err)
+
+	sb := sc.NewScriptBuilder()
+	sb.EmitDynamicCall(tx.GasToken, "refuel", []interface{}{account.ToByteArray(), big.NewInt(100000000)})
+	expected, err := sb.ToArray()
+	assert.Nil(t, err)
+	assert.Equal(t, expected, script)
+}
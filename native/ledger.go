@@ -0,0 +1,41 @@
+package native
+
+import (
+	"fmt"
+
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/sc"
+)
+
+const LedgerContractId = "0xda65b600f7124ce6c79950c1772a36403104f2be"
+
+var LedgerContract, _ = helper.UInt160FromString(LedgerContractId)
+
+// MakeGetBlockByHashScript makes the script calling LedgerContract.getBlock with the block hash
+func MakeGetBlockByHashScript(hash *helper.UInt256) ([]byte, error) {
+	if hash == nil {
+		return nil, fmt.Errorf("block hash is nil")
+	}
+	return sc.MakeScript(LedgerContract, "getBlock", []interface{}{hash})
+}
+
+// MakeGetBlockByIndexScript makes the script calling LedgerContract.getBlock with the block index
+func MakeGetBlockByIndexScript(index uint32) ([]byte, error) {
+	return sc.MakeScript(LedgerContract, "getBlock", []interface{}{index})
+}
+
+// MakeGetTransactionScript makes the script calling LedgerContract.getTransaction
+func MakeGetTransactionScript(hash *helper.UInt256) ([]byte, error) {
+	if hash == nil {
+		return nil, fmt.Errorf("transaction hash is nil")
+	}
+	return sc.MakeScript(LedgerContract, "getTransaction", []interface{}{hash})
+}
+
+// MakeGetTransactionHeightScript makes the script calling LedgerContract.getTransactionHeight
+func MakeGetTransactionHeightScript(hash *helper.UInt256) ([]byte, error) {
+	if hash == nil {
+		return nil, fmt.Errorf("transaction hash is nil")
+	}
+	return sc.MakeScript(LedgerContract, "getTransactionHeight", []interface{}{hash})
+}
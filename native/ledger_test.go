@@ -0,0 +1,50 @@
+package native
+
+import (
+	"testing"
+
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/sc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLedgerContract(t *testing.T) {
+	assert.True(t, LedgerContract.Equals(sc.GetContractHash(helper.UInt160Zero, 0, "LedgerContract")))
+}
+
+// assertLedgerCall checks the script calls the method of LedgerContract with the single argument
+func assertLedgerCall(t *testing.T, script []byte, method string, arg sc.Instruction) {
+	instructions, err := sc.Disassemble(script)
+	assert.Nil(t, err)
+	assert.Equal(t, 7, len(instructions))
+	assert.Equal(t, arg.OpCode, instructions[0].OpCode)
+	assert.Equal(t, arg.Operand, instructions[0].Operand)
+	assert.Equal(t, sc.PUSH1, instructions[1].OpCode)
+	assert.Equal(t, sc.PACK, instructions[2].OpCode)
+	assert.Equal(t, []byte(method), instructions[4].Operand)
+	assert.Equal(t, LedgerContract.ToByteArray(), instructions[5].Operand)
+}
+
+func TestMakeLedgerScripts(t *testing.T) {
+	hash, _ := helper.UInt256FromString("0x8e8e8b80e20cb1aa55b0e9a3e6bd3e7e5f46e2d6b2c2dc5f24bd1b5ecb5d9a3a")
+	pushHash := sc.Instruction{OpCode: sc.PUSHDATA1, Operand: hash.ToByteArray()}
+
+	script, err := MakeGetBlockByHashScript(hash)
+	assert.Nil(t, err)
+	assertLedgerCall(t, script, "getBlock", pushHash)
+
+	script, err = MakeGetBlockByIndexScript(1000)
+	assert.Nil(t, err)
+	assertLedgerCall(t, script, "getBlock", sc.Instruction{OpCode: sc.PUSHINT16, Operand: []byte{0xe8, 0x03}})
+
+	script, err = MakeGetTransactionScript(hash)
+	assert.Nil(t, err)
+	assertLedgerCall(t, script, "getTransaction", pushHash)
+
+	script, err = MakeGetTransactionHeightScript(hash)
+	assert.Nil(t, err)
+	assertLedgerCall(t, script, "getTransactionHeight", pushHash)
+
+	_, err = MakeGetTransactionScript(nil)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,31 @@
+package native
+
+import (
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/sc"
+	"github.com/joeqian10/neo3-gogogo/tx"
+)
+
+const StdLibId = "0xacce6fd80d44e1796aa0c2c625e9e4e0ce39efc0"
+const CryptoLibId = "0x726cb6e0cd8628a1350a611384688911ab75f51b"
+const PolicyContractId = "0xcc5e4edd9f5f8dba8bb65734541df7a1c081c67b"
+const OracleContractId = "0xfe924b7cfe89ddd271abaf7210a80a7e11178758"
+
+var StdLib, _ = helper.UInt160FromString(StdLibId)
+var CryptoLib, _ = helper.UInt160FromString(CryptoLibId)
+var PolicyContract, _ = helper.UInt160FromString(PolicyContractId)
+var OracleContract, _ = helper.UInt160FromString(OracleContractId)
+
+// NativeContracts maps the manifest names of the native contracts to their script hashes, which are
+// the same on all N3 networks since they only depend on the names
+var NativeContracts = map[string]*helper.UInt160{
+	"ContractManagement": sc.ContractManagement,
+	"StdLib":             StdLib,
+	"CryptoLib":          CryptoLib,
+	"LedgerContract":     LedgerContract,
+	"NeoToken":           tx.NeoToken,
+	"GasToken":           tx.GasToken,
+	"PolicyContract":     PolicyContract,
+	"RoleManagement":     RoleManagement,
+	"OracleContract":     OracleContract,
+}
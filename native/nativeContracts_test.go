@@ -0,0 +1,94 @@
+package native
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/rpc"
+	"github.com/joeqian10/neo3-gogogo/sc"
+	"github.com/stretchr/testify/assert"
+)
+
+// the ids, hashes and names returned by getnativecontracts on mainnet, the nef and manifest of each contract are placeholders
+const nativeContractsFixture = `[
+	{
+		"id": -1,
+		"updatecounter": 0,
+		"hash": "0xfffdc93764dbaddd97c48f252a53ea4643faa3fd",
+		"nef": {"magic": 860243278, "compiler": "neo-core-v3.0", "source": "", "tokens": [], "script": "EEEa93tnQBBBGvd7Z0AQQRr3e2dAEEEa93tnQBBBGvd7Z0A=", "checksum": 0},
+		"manifest": {"name": "ContractManagement", "groups": [], "features": {}, "supportedstandards": [], "abi": {"methods": [], "events": []}, "permissions": [{"contract": "*", "methods": "*"}], "trusts": [], "extra": null}
+	},
+	{
+		"id": -2,
+		"updatecounter": 0,
+		"hash": "0xacce6fd80d44e1796aa0c2c625e9e4e0ce39efc0",
+		"nef": {"magic": 860243278, "compiler": "neo-core-v3.0", "source": "", "tokens": [], "script": "EEEa93tnQBBBGvd7Z0AQQRr3e2dAEEEa93tnQBBBGvd7Z0A=", "checksum": 0},
+		"manifest": {"name": "StdLib", "groups": [], "features": {}, "supportedstandards": [], "abi": {"methods": [], "events": []}, "permissions": [{"contract": "*", "methods": "*"}], "trusts": [], "extra": null}
+	},
+	{
+		"id": -3,
+		"updatecounter": 0,
+		"hash": "0x726cb6e0cd8628a1350a611384688911ab75f51b",
+		"nef": {"magic": 860243278, "compiler": "neo-core-v3.0", "source": "", "tokens": [], "script": "EEEa93tnQBBBGvd7Z0AQQRr3e2dAEEEa93tnQBBBGvd7Z0A=", "checksum": 0},
+		"manifest": {"name": "CryptoLib", "groups": [], "features": {}, "supportedstandards": [], "abi": {"methods": [], "events": []}, "permissions": [{"contract": "*", "methods": "*"}], "trusts": [], "extra": null}
+	},
+	{
+		"id": -4,
+		"updatecounter": 0,
+		"hash": "0xda65b600f7124ce6c79950c1772a36403104f2be",
+		"nef": {"magic": 860243278, "compiler": "neo-core-v3.0", "source": "", "tokens": [], "script": "EEEa93tnQBBBGvd7Z0AQQRr3e2dAEEEa93tnQBBBGvd7Z0A=", "checksum": 0},
+		"manifest": {"name": "LedgerContract", "groups": [], "features": {}, "supportedstandards": [], "abi": {"methods": [], "events": []}, "permissions": [{"contract": "*", "methods": "*"}], "trusts": [], "extra": null}
+	},
+	{
+		"id": -5,
+		"updatecounter": 0,
+		"hash": "0xef4073a0f2b305a38ec4050e4d3d28bc40ea63f5",
+		"nef": {"magic": 860243278, "compiler": "neo-core-v3.0", "source": "", "tokens": [], "script": "EEEa93tnQBBBGvd7Z0AQQRr3e2dAEEEa93tnQBBBGvd7Z0A=", "checksum": 0},
+		"manifest": {"name": "NeoToken", "groups": [], "features": {}, "supportedstandards": [], "abi": {"methods": [], "events": []}, "permissions": [{"contract": "*", "methods": "*"}], "trusts": [], "extra": null}
+	},
+	{
+		"id": -6,
+		"updatecounter": 0,
+		"hash": "0xd2a4cff31913016155e38e474a2c06d08be276cf",
+		"nef": {"magic": 860243278, "compiler": "neo-core-v3.0", "source": "", "tokens": [], "script": "EEEa93tnQBBBGvd7Z0AQQRr3e2dAEEEa93tnQBBBGvd7Z0A=", "checksum": 0},
+		"manifest": {"name": "GasToken", "groups": [], "features": {}, "supportedstandards": [], "abi": {"methods": [], "events": []}, "permissions": [{"contract": "*", "methods": "*"}], "trusts": [], "extra": null}
+	},
+	{
+		"id": -7,
+		"updatecounter": 0,
+		"hash": "0xcc5e4edd9f5f8dba8bb65734541df7a1c081c67b",
+		"nef": {"magic": 860243278, "compiler": "neo-core-v3.0", "source": "", "tokens": [], "script": "EEEa93tnQBBBGvd7Z0AQQRr3e2dAEEEa93tnQBBBGvd7Z0A=", "checksum": 0},
+		"manifest": {"name": "PolicyContract", "groups": [], "features": {}, "supportedstandards": [], "abi": {"methods": [], "events": []}, "permissions": [{"contract": "*", "methods": "*"}], "trusts": [], "extra": null}
+	},
+	{
+		"id": -8,
+		"updatecounter": 0,
+		"hash": "0x49cf4e5378ffcd4dec034fd98a174c5491e395e2",
+		"nef": {"magic": 860243278, "compiler": "neo-core-v3.0", "source": "", "tokens": [], "script": "EEEa93tnQBBBGvd7Z0AQQRr3e2dAEEEa93tnQBBBGvd7Z0A=", "checksum": 0},
+		"manifest": {"name": "RoleManagement", "groups": [], "features": {}, "supportedstandards": [], "abi": {"methods": [], "events": []}, "permissions": [{"contract": "*", "methods": "*"}], "trusts": [], "extra": null}
+	},
+	{
+		"id": -9,
+		"updatecounter": 0,
+		"hash": "0xfe924b7cfe89ddd271abaf7210a80a7e11178758",
+		"nef": {"magic": 860243278, "compiler": "neo-core-v3.0", "source": "", "tokens": [], "script": "EEEa93tnQBBBGvd7Z0AQQRr3e2dAEEEa93tnQBBBGvd7Z0A=", "checksum": 0},
+		"manifest": {"name": "OracleContract", "groups": [], "features": {}, "supportedstandards": [], "abi": {"methods": [], "events": []}, "permissions": [{"contract": "*", "methods": "*"}], "trusts": [], "extra": null}
+	}
+]`
+
+func TestNativeContracts(t *testing.T) {
+	response := rpc.GetNativeContractsResponse{}
+	assert.Nil(t, json.Unmarshal([]byte(nativeContractsFixture), &response.Result))
+	client := new(rpc.RpcClientMock)
+	client.On("GetNativeContracts").Return(response)
+
+	contracts := client.GetNativeContracts().Result
+	assert.Equal(t, len(NativeContracts), len(contracts))
+	for _, c := range contracts {
+		hash, ok := NativeContracts[c.Manifest.Name]
+		assert.True(t, ok, c.Manifest.Name)
+		assert.Equal(t, c.Hash, "0x"+hash.String(), c.Manifest.Name)
+		assert.True(t, hash.Equals(sc.GetContractHash(helper.UInt160Zero, 0, c.Manifest.Name)), c.Manifest.Name)
+	}
+}
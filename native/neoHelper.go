@@ -0,0 +1,139 @@
+package native
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/joeqian10/neo3-gogogo/crypto"
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/rpc"
+	"github.com/joeqian10/neo3-gogogo/rpc/models"
+	"github.com/joeqian10/neo3-gogogo/sc"
+	"github.com/joeqian10/neo3-gogogo/tx"
+	"github.com/joeqian10/neo3-gogogo/vm"
+)
+
+// NeoAccountState is the state of an account in the NeoToken contract
+type NeoAccountState struct {
+	Balance        *big.Int
+	BalanceHeight  uint32
+	VoteTo         *crypto.ECPoint // nil if the account has not voted
+	LastGasPerVote *big.Int        // nil if the node does not return it
+}
+
+// NeoHelper wraps the governance methods of the NeoToken native contract
+type NeoHelper struct {
+	Client rpc.IRpcClient
+}
+
+func NewNeoHelper(client rpc.IRpcClient) *NeoHelper {
+	if client == nil {
+		return nil
+	}
+	return &NeoHelper{Client: client}
+}
+
+// MakeGetAccountStateScript makes the script calling NEO.getAccountState
+func MakeGetAccountStateScript(account *helper.UInt160) ([]byte, error) {
+	return sc.MakeScript(tx.NeoToken, "getAccountState", []interface{}{
+		sc.ContractParameter{Type: sc.Hash160, Value: account},
+	})
+}
+
+// EmitGetCommittee emits the call of NEO.getCommittee, which leaves the array of the committee public keys on the stack
+func EmitGetCommittee(sb *sc.ScriptBuilder) *sc.ScriptBuilder {
+	return sb.EmitDynamicCall(tx.NeoToken, "getCommittee", nil)
+}
+
+// MakeGetCommitteeScript makes the script calling NEO.getCommittee
+func MakeGetCommitteeScript() ([]byte, error) {
+	sb := sc.NewScriptBuilder()
+	return EmitGetCommittee(&sb).ToArray()
+}
+
+// GetCommittee returns the public keys of the committee members
+func (n *NeoHelper) GetCommittee() ([]crypto.ECPoint, error) {
+	script, err := MakeGetCommitteeScript()
+	if err != nil {
+		return nil, err
+	}
+	response := n.Client.InvokeScript(crypto.Base64Encode(script), nil)
+	stack, err := rpc.PopInvokeStack(response)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePublicKeys(stack)
+}
+
+// GetAccountState returns the NEO balance and the vote of the account, nil if the account has no state
+func (n *NeoHelper) GetAccountState(account *helper.UInt160) (*NeoAccountState, error) {
+	script, err := MakeGetAccountStateScript(account)
+	if err != nil {
+		return nil, err
+	}
+	response := n.Client.InvokeScript(crypto.Base64Encode(script), nil)
+	stack, err := rpc.PopInvokeStack(response)
+	if err != nil {
+		return nil, err
+	}
+	return ParseNeoAccountState(stack)
+}
+
+// ParseNeoAccountState decodes the struct returned by NEO.getAccountState
+func ParseNeoAccountState(stack *models.InvokeStack) (*NeoAccountState, error) {
+	if stack.Type == vm.Any.String() {
+		return nil, nil
+	}
+	if stack.Type != vm.Struct.String() && stack.Type != vm.Array.String() {
+		return nil, fmt.Errorf("unexpected stack item type: %s", stack.Type)
+	}
+	stack.Convert()
+	items, ok := stack.Value.([]models.InvokeStack)
+	if !ok || len(items) < 3 {
+		return nil, fmt.Errorf("invalid account state")
+	}
+	balance, err := parseInteger(items[0])
+	if err != nil {
+		return nil, err
+	}
+	height, err := parseInteger(items[1])
+	if err != nil {
+		return nil, err
+	}
+	state := &NeoAccountState{
+		Balance:       balance,
+		BalanceHeight: uint32(height.Uint64()),
+	}
+	if items[2].Type != vm.Any.String() {
+		b, err := parseBytes(items[2])
+		if err != nil {
+			return nil, err
+		}
+		state.VoteTo, err = crypto.NewECPointFromBytes(b)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(items) > 3 {
+		state.LastGasPerVote, err = parseInteger(items[3])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return state, nil
+}
+
+func parseInteger(item models.InvokeStack) (*big.Int, error) {
+	return item.GetBigInteger()
+}
+
+func parseBytes(item models.InvokeStack) ([]byte, error) {
+	if item.Type != vm.ByteString.String() && item.Type != vm.Buffer.String() {
+		return nil, fmt.Errorf("unexpected stack item type: %s", item.Type)
+	}
+	s, ok := item.Value.(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid byte string value")
+	}
+	return crypto.Base64Decode(s)
+}
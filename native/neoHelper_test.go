@@ -0,0 +1,137 @@
+package native
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/rpc"
+	"github.com/joeqian10/neo3-gogogo/rpc/models"
+	"github.com/joeqian10/neo3-gogogo/sc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNewNeoHelper(t *testing.T) {
+	assert.Nil(t, NewNeoHelper(nil))
+	assert.NotNil(t, NewNeoHelper(rpc.NewClient("http://seed1.ngd.network:20332")))
+}
+
+func TestMakeGetAccountStateScript(t *testing.T) {
+	script, err := MakeGetAccountStateScript(helper.UInt160Zero)
+	assert.Nil(t, err)
+	assert.Equal(t, "0c14000000000000000000000000000000000000000011c01f0c0f6765744163636f756e7453746174650c14f563ea40bc283d4d0e05c48ea305b3f2a07340ef41627d5b52", helper.BytesToHex(script))
+}
+
+func TestNeoHelper_GetAccountState(t *testing.T) {
+	var clientMock = new(rpc.RpcClientMock)
+	var nh = NeoHelper{Client: clientMock}
+	var result models.InvokeResult
+	err := json.Unmarshal([]byte(`{
+		"script": "DBQAAAAAAAAAAAAAAAAAAAAAAAAAABHAHwwPZ2V0QWNjb3VudFN0YXRlDBT1Y+pAvCg9TQ4FxI6jBbPyoHNA70FifVtS",
+		"state": "HALT",
+		"gasconsumed": "1198350",
+		"exception": null,
+		"stack": [
+			{
+				"type": "Struct",
+				"value": [
+					{
+						"type": "Integer",
+						"value": "100"
+					},
+					{
+						"type": "Integer",
+						"value": "2412"
+					},
+					{
+						"type": "ByteString",
+						"value": "A7en+TMZnyjMHEjSKiHHisOZLPf86wOKnGcP5VREQmYZ"
+					},
+					{
+						"type": "Integer",
+						"value": "3062680"
+					}
+				]
+			}
+		]
+	}`), &result)
+	assert.Nil(t, err)
+	clientMock.On("InvokeScript", mock.Anything, mock.Anything).Return(rpc.InvokeResultResponse{
+		RpcResponse: rpc.RpcResponse{JsonRpc: "2.0", ID: 1},
+		Result:      result,
+	})
+
+	state, err := nh.GetAccountState(helper.UInt160Zero)
+	assert.Nil(t, err)
+	assert.Equal(t, big.NewInt(100), state.Balance)
+	assert.Equal(t, uint32(2412), state.BalanceHeight)
+	assert.Equal(t, "03b7a7f933199f28cc1c48d22a21c78ac3992cf7fceb038a9c670fe55444426619", helper.BytesToHex(state.VoteTo.EncodePoint(true)))
+	assert.Equal(t, big.NewInt(3062680), state.LastGasPerVote)
+}
+
+func TestParseNeoAccountState(t *testing.T) {
+	state, err := ParseNeoAccountState(&models.InvokeStack{Type: "Any"})
+	assert.Nil(t, err)
+	assert.Nil(t, state)
+
+	var stack models.InvokeStack
+	err = json.Unmarshal([]byte(`{
+		"type": "Struct",
+		"value": [
+			{"type": "Integer", "value": "5"},
+			{"type": "Integer", "value": "10"},
+			{"type": "Any"}
+		]
+	}`), &stack)
+	assert.Nil(t, err)
+	state, err = ParseNeoAccountState(&stack)
+	assert.Nil(t, err)
+	assert.Equal(t, big.NewInt(5), state.Balance)
+	assert.Nil(t, state.VoteTo)
+	assert.Nil(t, state.LastGasPerVote)
+}
+
+func TestMakeGetCommitteeScript(t *testing.T) {
+	script, err := MakeGetCommitteeScript()
+	assert.Nil(t, err)
+	expected := "c21f0c0c676574436f6d6d69747465650c14f563ea40bc283d4d0e05c48ea305b3f2a07340ef41627d5b52"
+	assert.Equal(t, expected, helper.BytesToHex(script))
+
+	// composed after another call
+	sb := sc.NewScriptBuilder()
+	sb.EmitPushInteger(1)
+	b, err := EmitGetCommittee(&sb).ToArray()
+	assert.Nil(t, err)
+	assert.Equal(t, "11"+expected, helper.BytesToHex(b))
+}
+
+func TestNeoHelper_GetCommittee(t *testing.T) {
+	var result models.InvokeResult
+	err := json.Unmarshal([]byte(`{
+		"script": "whkMDGdldENvbW1pdHRlZQwU9WPqQLwoPU0OBcSOowWz8qBzQO9BYn1bUg==",
+		"state": "HALT",
+		"gasconsumed": "2007570",
+		"stack": [{
+			"type": "Array",
+			"value": [
+				{"type": "ByteString", "value": "A7IJ/U9TpxcOpERODLCmu2pTwr0BaSaYnPhfmw+6F6cM"},
+				{"type": "ByteString", "value": "A7en+TMZnyjMHEjSKiHHisOZLPf86wOKnGcP5VREQmYZ"}
+			]
+		}]
+	}`), &result)
+	assert.Nil(t, err)
+
+	var clientMock = new(rpc.RpcClientMock)
+	var nh = NewNeoHelper(clientMock)
+	clientMock.On("InvokeScript", mock.Anything, mock.Anything).Return(rpc.InvokeResultResponse{
+		RpcResponse: rpc.RpcResponse{JsonRpc: "2.0", ID: 1},
+		Result:      result,
+	})
+	committee, err := nh.GetCommittee()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(committee))
+	assert.Equal(t, "03b209fd4f53a7170ea4444e0cb0a6bb6a53c2bd016926989cf85f9b0fba17a70c", helper.BytesToHex(committee[0].EncodePoint(true)))
+	assert.Equal(t, "03b7a7f933199f28cc1c48d22a21c78ac3992cf7fceb038a9c670fe55444426619", helper.BytesToHex(committee[1].EncodePoint(true)))
+}
@@ -0,0 +1,117 @@
+package native
+
+import (
+	"fmt"
+
+	"github.com/joeqian10/neo3-gogogo/crypto"
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/rpc"
+	"github.com/joeqian10/neo3-gogogo/rpc/models"
+	"github.com/joeqian10/neo3-gogogo/sc"
+	"github.com/joeqian10/neo3-gogogo/vm"
+)
+
+const RoleManagementId = "0x49cf4e5378ffcd4dec034fd98a174c5491e395e2"
+
+var RoleManagement, _ = helper.UInt160FromString(RoleManagementId)
+
+// Role is the role of the nodes designated in the RoleManagement native contract
+type Role byte
+
+const (
+	StateValidator    Role = 4
+	Oracle            Role = 8
+	NeoFSAlphabetNode Role = 16
+	P2PNotary         Role = 32
+)
+
+func (r Role) String() string {
+	switch r {
+	case StateValidator:
+		return "StateValidator"
+	case Oracle:
+		return "Oracle"
+	case NeoFSAlphabetNode:
+		return "NeoFSAlphabetNode"
+	case P2PNotary:
+		return "P2PNotary"
+	default:
+		return "Not Defined"
+	}
+}
+
+// RoleManagementHelper wraps the methods of the RoleManagement native contract
+type RoleManagementHelper struct {
+	Client rpc.IRpcClient
+}
+
+func NewRoleManagementHelper(client rpc.IRpcClient) *RoleManagementHelper {
+	if client == nil {
+		return nil
+	}
+	return &RoleManagementHelper{Client: client}
+}
+
+// MakeDesignateAsRoleScript makes the script calling RoleManagement.designateAsRole,
+// which needs the witness of the committee
+func MakeDesignateAsRoleScript(role Role, publicKeys []crypto.ECPoint) ([]byte, error) {
+	if len(publicKeys) == 0 {
+		return nil, fmt.Errorf("no public keys to designate")
+	}
+	keys := make([]sc.ContractParameter, len(publicKeys))
+	for i := range publicKeys {
+		keys[i] = sc.ContractParameter{Type: sc.PublicKey, Value: publicKeys[i].EncodePoint(true)}
+	}
+	return sc.MakeScript(RoleManagement, "designateAsRole", []interface{}{
+		sc.ContractParameter{Type: sc.Integer, Value: int64(role)},
+		sc.ContractParameter{Type: sc.Array, Value: keys},
+	})
+}
+
+// MakeGetDesignatedByRoleScript makes the script calling RoleManagement.getDesignatedByRole
+func MakeGetDesignatedByRoleScript(role Role, index uint32) ([]byte, error) {
+	return sc.MakeScript(RoleManagement, "getDesignatedByRole", []interface{}{
+		sc.ContractParameter{Type: sc.Integer, Value: int64(role)},
+		sc.ContractParameter{Type: sc.Integer, Value: int64(index)},
+	})
+}
+
+// GetDesignatedByRole returns the public keys designated to the role at the block index
+func (r *RoleManagementHelper) GetDesignatedByRole(role Role, index uint32) ([]crypto.ECPoint, error) {
+	script, err := MakeGetDesignatedByRoleScript(role, index)
+	if err != nil {
+		return nil, err
+	}
+	response := r.Client.InvokeScript(crypto.Base64Encode(script), nil)
+	stack, err := rpc.PopInvokeStack(response)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePublicKeys(stack)
+}
+
+// ParsePublicKeys decodes the array of public keys returned by RoleManagement.getDesignatedByRole
+// and NEO.getCommittee
+func ParsePublicKeys(stack *models.InvokeStack) ([]crypto.ECPoint, error) {
+	if stack.Type != vm.Array.String() && stack.Type != vm.Struct.String() {
+		return nil, fmt.Errorf("unexpected stack item type: %s", stack.Type)
+	}
+	stack.Convert()
+	items, ok := stack.Value.([]models.InvokeStack)
+	if !ok {
+		return nil, fmt.Errorf("invalid public key array")
+	}
+	result := make([]crypto.ECPoint, len(items))
+	for i, item := range items {
+		b, err := parseBytes(item)
+		if err != nil {
+			return nil, err
+		}
+		p, err := crypto.NewECPointFromBytes(b)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = *p
+	}
+	return result, nil
+}
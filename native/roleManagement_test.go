@@ -0,0 +1,82 @@
+package native
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/joeqian10/neo3-gogogo/crypto"
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/rpc"
+	"github.com/joeqian10/neo3-gogogo/rpc/models"
+	"github.com/joeqian10/neo3-gogogo/sc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRoleManagement(t *testing.T) {
+	assert.True(t, RoleManagement.Equals(sc.GetContractHash(helper.UInt160Zero, 0, "RoleManagement")))
+	assert.Equal(t, "Oracle", Oracle.String())
+	assert.Equal(t, "StateValidator", StateValidator.String())
+}
+
+func TestMakeDesignateAsRoleScript(t *testing.T) {
+	p1, _ := crypto.NewECPointFromString("03b209fd4f53a7170ea4444e0cb0a6bb6a53c2bd016926989cf85f9b0fba17a70c")
+	p2, _ := crypto.NewECPointFromString("03b7a7f933199f28cc1c48d22a21c78ac3992cf7fceb038a9c670fe55444426619")
+	script, err := MakeDesignateAsRoleScript(Oracle, []crypto.ECPoint{*p1, *p2})
+	assert.Nil(t, err)
+
+	// [role, [p1, p2]] is pushed in reverse order
+	sb := sc.NewScriptBuilder()
+	sb.EmitPushBytes(p2.EncodePoint(true))
+	sb.EmitPushBytes(p1.EncodePoint(true))
+	sb.EmitPushInteger(2)
+	sb.Emit(sc.PACK)
+	sb.EmitPushInteger(8)
+	sb.EmitPushInteger(2)
+	sb.Emit(sc.PACK)
+	sb.EmitPushObject(sc.All)
+	sb.EmitPushString("designateAsRole")
+	sb.EmitPushSerializable(RoleManagement)
+	sb.EmitSysCall(sc.System_Contract_Call.ToInteropMethodHash())
+	expected, _ := sb.ToArray()
+	assert.Equal(t, expected, script)
+
+	_, err = MakeDesignateAsRoleScript(Oracle, nil)
+	assert.NotNil(t, err)
+}
+
+func TestMakeGetDesignatedByRoleScript(t *testing.T) {
+	script, err := MakeGetDesignatedByRoleScript(StateValidator, 100)
+	assert.Nil(t, err)
+	assert.True(t, bytes.Contains(script, []byte("getDesignatedByRole")))
+	// index 100, role 4
+	assert.Equal(t, []byte{byte(sc.PUSHINT8), 100, byte(sc.PUSH4), byte(sc.PUSH2), byte(sc.PACK)}, script[:5])
+	called, err := sc.GetCalledContracts(script)
+	assert.Nil(t, err)
+	assert.Equal(t, []helper.UInt160{*RoleManagement}, called)
+}
+
+func TestRoleManagementHelper_GetDesignatedByRole(t *testing.T) {
+	var clientMock = new(rpc.RpcClientMock)
+	var rh = NewRoleManagementHelper(clientMock)
+	clientMock.On("InvokeScript", mock.Anything, mock.Anything).Return(rpc.InvokeResultResponse{
+		RpcResponse: rpc.RpcResponse{JsonRpc: "2.0", ID: 1},
+		Result: models.InvokeResult{
+			State:       "HALT",
+			GasConsumed: "2028330",
+			Stack: []models.InvokeStack{{
+				Type: "Array",
+				Value: []interface{}{
+					map[string]interface{}{"type": "ByteString", "value": "A7IJ/U9TpxcOpERODLCmu2pTwr0BaSaYnPhfmw+6F6cM"},
+					map[string]interface{}{"type": "ByteString", "value": "A7en+TMZnyjMHEjSKiHHisOZLPf86wOKnGcP5VREQmYZ"},
+				},
+			}},
+		},
+	})
+
+	keys, err := rh.GetDesignatedByRole(Oracle, 100)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(keys))
+	assert.Equal(t, "03b209fd4f53a7170ea4444e0cb0a6bb6a53c2bd016926989cf85f9b0fba17a70c", helper.BytesToHex(keys[0].EncodePoint(true)))
+	assert.Equal(t, "03b7a7f933199f28cc1c48d22a21c78ac3992cf7fceb038a9c670fe55444426619", helper.BytesToHex(keys[1].EncodePoint(true)))
+}
@@ -0,0 +1,61 @@
+package nep17
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/sc"
+	"github.com/joeqian10/neo3-gogogo/tx"
+)
+
+// TokenAmount is an amount of a nep17 token in its smallest unit
+type TokenAmount struct {
+	Token  *helper.UInt160
+	Amount *big.Int
+}
+
+// MakeMultiTransferScript makes one script transferring every token amount from the sender to the
+// destination, each transfer is asserted. The returned signer of the sender only allows the tokens
+// to use the witness, or is CalledByEntry when there are more tokens than a signer can allow
+func MakeMultiTransferScript(from *helper.UInt160, to *helper.UInt160, amounts []TokenAmount) ([]byte, *tx.Signer, error) {
+	if len(amounts) == 0 {
+		return nil, nil, fmt.Errorf("no token amounts to transfer")
+	}
+	sb := sc.NewScriptBuilder()
+	tokens := make([]helper.UInt160, 0)
+	for _, a := range amounts {
+		if a.Token == nil || a.Amount == nil || a.Amount.Sign() <= 0 {
+			return nil, nil, fmt.Errorf("invalid token amount")
+		}
+		sb.EmitDynamicCall(a.Token, "transfer", []interface{}{
+			sc.ContractParameter{Type: sc.Hash160, Value: from},
+			sc.ContractParameter{Type: sc.Hash160, Value: to},
+			sc.ContractParameter{Type: sc.Integer, Value: a.Amount},
+			sc.Null,
+		})
+		sb.EmitAssert("")
+		if !containsToken(tokens, a.Token) {
+			tokens = append(tokens, *a.Token)
+		}
+	}
+	script, err := sb.ToArray()
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(tokens) > tx.MaxSubitems {
+		return script, tx.NewSigner(from, tx.CalledByEntry), nil
+	}
+	signer := tx.NewSigner(from, tx.CustomContracts)
+	signer.AllowedContracts = tokens
+	return script, signer, nil
+}
+
+func containsToken(tokens []helper.UInt160, token *helper.UInt160) bool {
+	for i := range tokens {
+		if tokens[i].Equals(token) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,48 @@
+package nep17
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/sc"
+	"github.com/joeqian10/neo3-gogogo/tx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMakeMultiTransferScript(t *testing.T) {
+	from, _ := helper.UInt160FromString("0x2916eba24e652fa006f3e5eb8f9892d2c3b00399")
+	to, _ := helper.UInt160FromString("0xedae9b97c72dbec43a7201b6388c24bfd86c71ae")
+	amounts := []TokenAmount{
+		{Token: tx.NeoToken, Amount: big.NewInt(10)},
+		{Token: tx.GasToken, Amount: big.NewInt(100000000)},
+		{Token: tx.GasToken, Amount: big.NewInt(1)},
+	}
+	script, signer, err := MakeMultiTransferScript(from, to, amounts)
+	assert.Nil(t, err)
+
+	called, err := sc.GetCalledContracts(script)
+	assert.Nil(t, err)
+	assert.Equal(t, []helper.UInt160{*tx.NeoToken, *tx.GasToken}, called)
+	assert.Equal(t, 3, bytes.Count(script, []byte("transfer")))
+
+	single, err := sc.MakeScript(tx.NeoToken, "transfer", []interface{}{
+		sc.ContractParameter{Type: sc.Hash160, Value: from},
+		sc.ContractParameter{Type: sc.Hash160, Value: to},
+		sc.ContractParameter{Type: sc.Integer, Value: big.NewInt(10)},
+		sc.Null,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, append(single, byte(sc.ASSERT)), script[:len(single)+1])
+
+	assert.Equal(t, from, signer.Account)
+	assert.Equal(t, tx.CustomContracts, signer.Scopes)
+	assert.Equal(t, []helper.UInt160{*tx.NeoToken, *tx.GasToken}, signer.AllowedContracts)
+	assert.Nil(t, signer.ValidateAllowedContracts(script))
+
+	_, _, err = MakeMultiTransferScript(from, to, nil)
+	assert.NotNil(t, err)
+	_, _, err = MakeMultiTransferScript(from, to, []TokenAmount{{Token: tx.GasToken, Amount: big.NewInt(0)}})
+	assert.NotNil(t, err)
+}
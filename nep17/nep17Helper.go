@@ -1,10 +1,13 @@
 package nep17
 
 import (
+	"fmt"
 	"math/big"
+	"sync"
 
 	"github.com/joeqian10/neo3-gogogo/helper"
 	"github.com/joeqian10/neo3-gogogo/rpc"
+	"github.com/joeqian10/neo3-gogogo/rpc/models"
 	"github.com/joeqian10/neo3-gogogo/sc"
 )
 
@@ -12,6 +15,9 @@ import (
 type Nep17Helper struct {
 	ScriptHash *helper.UInt160 // scriptHash of nep17 token
 	Client     rpc.IRpcClient
+
+	mu       sync.Mutex
+	decimals *int // cached, the decimals of a token never change
 }
 
 func NewNep17Helper(scriptHash *helper.UInt160, client rpc.IRpcClient) *Nep17Helper {
@@ -24,82 +30,93 @@ func NewNep17Helper(scriptHash *helper.UInt160, client rpc.IRpcClient) *Nep17Hel
 	}
 }
 
-func (n *Nep17Helper) Symbol() (string, error) {
-	sb := sc.NewScriptBuilder()
-	sb.EmitDynamicCall(n.ScriptHash, "symbol", nil)
-	script, err := sb.ToArray()
-	if err != nil {
-		return "", err
+// invoke calls the safe method of the token by invokefunction and returns the first stack item
+func (n *Nep17Helper) invoke(method string, args []models.RpcContractParameter) (*sc.ContractParameter, error) {
+	if args == nil {
+		args = []models.RpcContractParameter{}
 	}
-	response := n.Client.InvokeScript(helper.BytesToHex(script), nil)
+	response := n.Client.InvokeFunction("0x"+n.ScriptHash.String(), method, args, nil)
 	stack, err := rpc.PopInvokeStack(response)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	p, err := stack.ToParameter()
+	return stack.ToParameter()
+}
+
+// invokeInteger calls the method and returns the integer result
+func (n *Nep17Helper) invokeInteger(method string, args []models.RpcContractParameter) (*big.Int, error) {
+	p, err := n.invoke(method, args)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+	value, ok := p.Value.(*big.Int)
+	if !ok {
+		return nil, fmt.Errorf("%s returned a non integer result", method)
 	}
-	return  string(p.Value.([]byte)), nil
+	return value, nil
 }
 
-func (n *Nep17Helper) Decimals() (int, error) {
-	sb := sc.NewScriptBuilder()
-	sb.EmitDynamicCall(n.ScriptHash, "decimals", nil)
-	script, err := sb.ToArray()
+func (n *Nep17Helper) Symbol() (string, error) {
+	p, err := n.invoke("symbol", nil)
 	if err != nil {
-		return 0, err
+		return "", err
 	}
-	response := n.Client.InvokeScript(helper.BytesToHex(script), nil)
-	stack, err := rpc.PopInvokeStack(response)
-	if err != nil {
-		return 0, err
+	value, ok := p.Value.([]byte)
+	if !ok {
+		return "", fmt.Errorf("symbol returned a non string result")
+	}
+	return string(value), nil
+}
+
+// Decimals returns the decimals of the token, it is only queried from the node on the first call
+func (n *Nep17Helper) Decimals() (int, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.decimals != nil {
+		return *n.decimals, nil
 	}
-	p, err := stack.ToParameter()
+	value, err := n.invokeInteger("decimals", nil)
 	if err != nil {
 		return 0, err
 	}
-	return int(p.Value.(*big.Int).Int64()), nil
+	decimals := int(value.Int64())
+	n.decimals = &decimals
+	return decimals, nil
 }
 
 func (n *Nep17Helper) TotalSupply() (*big.Int, error) {
-	sb := sc.NewScriptBuilder()
-	sb.EmitDynamicCall(n.ScriptHash, "totalSupply", nil)
-	script, err := sb.ToArray()
-	if err != nil {
-		return nil, err
-	}
-	response := n.Client.InvokeScript(helper.BytesToHex(script), nil)
-	stack, err := rpc.PopInvokeStack(response)
-	if err != nil {
-		return nil, err
-	}
-	p, err := stack.ToParameter()
-	if err != nil {
-		return nil, err
-	}
-	return p.Value.(*big.Int), nil
+	return n.invokeInteger("totalSupply", nil)
 }
 
 func (n *Nep17Helper) BalanceOf(account *helper.UInt160) (*big.Int, error) {
-	sb := sc.NewScriptBuilder()
-	param := sc.ContractParameter{
-		Type:  sc.Hash160,
-		Value: account,
+	if account == nil {
+		return nil, fmt.Errorf("account is nil")
 	}
-	sb.EmitDynamicCall(n.ScriptHash, "balanceOf", []interface{}{param})
-	script, err := sb.ToArray()
-	if err != nil {
-		return nil, err
+	return n.invokeInteger("balanceOf", []models.RpcContractParameter{
+		models.NewRpcContractParameter("Hash160", "0x"+account.String()),
+	})
+}
+
+// CreateTransferScript makes the script transferring the amount in the smallest unit of the token from the
+// sender to the receiver with the data, which is passed to onNEP17Payment of a receiving contract and can be
+// nil. The result of the transfer is asserted, so the transaction faults if the transfer fails
+func (n *Nep17Helper) CreateTransferScript(from, to *helper.UInt160, amount *big.Int, data interface{}) ([]byte, error) {
+	if from == nil || to == nil {
+		return nil, fmt.Errorf("sender or receiver is nil")
 	}
-	response := n.Client.InvokeScript(helper.BytesToHex(script), nil)
-	stack, err := rpc.PopInvokeStack(response)
-	if err != nil {
-		return nil, err
+	if amount == nil || amount.Sign() < 0 {
+		return nil, fmt.Errorf("amount must not be negative")
 	}
-	p, err := stack.ToParameter()
-	if err != nil {
-		return nil, err
+	if data == nil {
+		data = sc.Null
 	}
-	return p.Value.(*big.Int), nil
+	sb := sc.NewScriptBuilder()
+	sb.EmitDynamicCall(n.ScriptHash, "transfer", []interface{}{
+		sc.ContractParameter{Type: sc.Hash160, Value: from},
+		sc.ContractParameter{Type: sc.Hash160, Value: to},
+		sc.ContractParameter{Type: sc.Integer, Value: amount},
+		data,
+	})
+	sb.EmitAssert("")
+	return sb.ToArray()
 }
@@ -4,6 +4,7 @@ import (
 	"github.com/joeqian10/neo3-gogogo/helper"
 	"github.com/joeqian10/neo3-gogogo/rpc"
 	"github.com/joeqian10/neo3-gogogo/rpc/models"
+	"github.com/joeqian10/neo3-gogogo/sc"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"math/big"
@@ -21,7 +22,7 @@ func TestNep17Helper_Symbol(t *testing.T) {
 		ScriptHash: helper.NewUInt160(),
 		Client:     clientMock,
 	}
-	clientMock.On("InvokeScript", mock.Anything, mock.Anything).Return(rpc.InvokeResultResponse{
+	clientMock.On("InvokeFunction", "0x0000000000000000000000000000000000000000", "symbol", mock.Anything, mock.Anything).Return(rpc.InvokeResultResponse{
 		RpcResponse: rpc.RpcResponse{
 			JsonRpc: "2.0",
 			ID:      1,
@@ -56,7 +57,7 @@ func TestNep17Helper_Decimals(t *testing.T) {
 		ScriptHash: helper.NewUInt160(),
 		Client:     clientMock,
 	}
-	clientMock.On("InvokeScript", mock.Anything, mock.Anything).Return(rpc.InvokeResultResponse{
+	clientMock.On("InvokeFunction", "0x0000000000000000000000000000000000000000", "decimals", mock.Anything, mock.Anything).Return(rpc.InvokeResultResponse{
 		RpcResponse: rpc.RpcResponse{
 			JsonRpc: "2.0",
 			ID:      1,
@@ -92,7 +93,7 @@ func TestNep17Helper_TotalSupply(t *testing.T) {
 		ScriptHash: helper.NewUInt160(),
 		Client:     clientMock,
 	}
-	clientMock.On("InvokeScript", mock.Anything, mock.Anything).Return(rpc.InvokeResultResponse{
+	clientMock.On("InvokeFunction", "0x0000000000000000000000000000000000000000", "totalSupply", mock.Anything, mock.Anything).Return(rpc.InvokeResultResponse{
 		RpcResponse: rpc.RpcResponse{
 			JsonRpc: "2.0",
 			ID:      1,
@@ -127,7 +128,7 @@ func TestNep17Helper_BalanceOf(t *testing.T) {
 		ScriptHash: helper.NewUInt160(),
 		Client:     clientMock,
 	}
-	clientMock.On("InvokeScript", mock.Anything, mock.Anything).Return(rpc.InvokeResultResponse{
+	clientMock.On("InvokeFunction", "0x0000000000000000000000000000000000000000", "balanceOf", mock.Anything, mock.Anything).Return(rpc.InvokeResultResponse{
 		RpcResponse: rpc.RpcResponse{
 			JsonRpc: "2.0",
 			ID:      1,
@@ -155,3 +156,79 @@ func TestNep17Helper_BalanceOf(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, big.NewInt(8913620128), b)
 }
+
+func TestNep17Helper_GAS(t *testing.T) {
+	gas, _ := helper.UInt160FromString("0xd2a4cff31913016155e38e474a2c06d08be276cf")
+	account, _ := helper.UInt160FromString("0x2916eba24e652fa006f3e5eb8f9892d2c3b00399")
+	var clientMock = new(rpc.RpcClientMock)
+	halt := func(stack models.InvokeStack) rpc.InvokeResultResponse {
+		return rpc.InvokeResultResponse{
+			RpcResponse: rpc.RpcResponse{JsonRpc: "2.0", ID: 1},
+			Result:      models.InvokeResult{State: "HALT", GasConsumed: "984060", Stack: []models.InvokeStack{stack}},
+		}
+	}
+	clientMock.On("InvokeFunction", "0xd2a4cff31913016155e38e474a2c06d08be276cf", "symbol", []models.RpcContractParameter{}, mock.Anything).
+		Return(halt(models.InvokeStack{Type: "ByteString", Value: "R0FT"}))
+	clientMock.On("InvokeFunction", "0xd2a4cff31913016155e38e474a2c06d08be276cf", "decimals", []models.RpcContractParameter{}, mock.Anything).
+		Return(halt(models.InvokeStack{Type: "Integer", Value: "8"}))
+	clientMock.On("InvokeFunction", "0xd2a4cff31913016155e38e474a2c06d08be276cf", "totalSupply", []models.RpcContractParameter{}, mock.Anything).
+		Return(halt(models.InvokeStack{Type: "Integer", Value: "5224734405426813"}))
+	clientMock.On("InvokeFunction", "0xd2a4cff31913016155e38e474a2c06d08be276cf", "balanceOf",
+		[]models.RpcContractParameter{{Type: "Hash160", Value: "0x2916eba24e652fa006f3e5eb8f9892d2c3b00399"}}, mock.Anything).
+		Return(halt(models.InvokeStack{Type: "Integer", Value: "9995000000050"}))
+	nh := NewNep17Helper(gas, clientMock)
+
+	symbol, err := nh.Symbol()
+	assert.Nil(t, err)
+	assert.Equal(t, "GAS", symbol)
+
+	// decimals are only queried once
+	for i := 0; i < 2; i++ {
+		decimals, err := nh.Decimals()
+		assert.Nil(t, err)
+		assert.Equal(t, 8, decimals)
+	}
+	clientMock.AssertNumberOfCalls(t, "InvokeFunction", 2)
+
+	totalSupply, err := nh.TotalSupply()
+	assert.Nil(t, err)
+	assert.Equal(t, "5224734405426813", totalSupply.String())
+
+	balance, err := nh.BalanceOf(account)
+	assert.Nil(t, err)
+	assert.Equal(t, big.NewInt(9995000000050), balance)
+	_, err = nh.BalanceOf(nil)
+	assert.NotNil(t, err)
+}
+
+func TestNep17Helper_CreateTransferScript(t *testing.T) {
+	gas, _ := helper.UInt160FromString("0xd2a4cff31913016155e38e474a2c06d08be276cf")
+	from, _ := helper.UInt160FromString("0x2916eba24e652fa006f3e5eb8f9892d2c3b00399")
+	to, _ := helper.UInt160FromString("0x9bde8f209c88dd0e7ca3bf0af0f476cdd8207789")
+	nh := NewNep17Helper(gas, new(rpc.RpcClientMock))
+
+	script, err := nh.CreateTransferScript(from, to, big.NewInt(100000000), nil)
+	assert.Nil(t, err)
+	expected, err := sc.MakeScript(gas, "transfer", []interface{}{
+		sc.ContractParameter{Type: sc.Hash160, Value: from},
+		sc.ContractParameter{Type: sc.Hash160, Value: to},
+		sc.ContractParameter{Type: sc.Integer, Value: big.NewInt(100000000)},
+		sc.Null,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, append(expected, byte(sc.ASSERT)), script)
+
+	// same as a multi transfer of one token
+	multi, _, err := MakeMultiTransferScript(from, to, []TokenAmount{{Token: gas, Amount: big.NewInt(100000000)}})
+	assert.Nil(t, err)
+	assert.Equal(t, multi, script)
+
+	script, err = nh.CreateTransferScript(from, to, big.NewInt(1), "memo")
+	assert.Nil(t, err)
+	assert.NotEqual(t, multi, script)
+
+	_, err = nh.CreateTransferScript(nil, to, big.NewInt(1), nil)
+	assert.NotNil(t, err)
+	_, err = nh.CreateTransferScript(from, to, big.NewInt(-1), nil)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,121 @@
+package nep17
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/rpc"
+)
+
+// TokenInfo is the metadata of a nep17 token
+type TokenInfo struct {
+	ScriptHash *helper.UInt160
+	Symbol     string
+	Decimals   int
+}
+
+// TokenInfoCache caches the metadata of nep17 tokens, symbol and decimals of a token never change
+type TokenInfoCache struct {
+	Client rpc.IRpcClient
+	mu     sync.Mutex
+	infos  map[helper.UInt160]TokenInfo
+}
+
+func NewTokenInfoCache(client rpc.IRpcClient) *TokenInfoCache {
+	if client == nil {
+		return nil
+	}
+	return &TokenInfoCache{
+		Client: client,
+		infos:  make(map[helper.UInt160]TokenInfo),
+	}
+}
+
+// Get returns the metadata of the token, it is only queried from the node on the first call
+func (c *TokenInfoCache) Get(scriptHash *helper.UInt160) (*TokenInfo, error) {
+	c.mu.Lock()
+	info, ok := c.infos[*scriptHash]
+	c.mu.Unlock()
+	if ok {
+		return &info, nil
+	}
+	n := NewNep17Helper(scriptHash, c.Client)
+	symbol, err := n.Symbol()
+	if err != nil {
+		return nil, err
+	}
+	decimals, err := n.Decimals()
+	if err != nil {
+		return nil, err
+	}
+	info = TokenInfo{
+		ScriptHash: scriptHash,
+		Symbol:     symbol,
+		Decimals:   decimals,
+	}
+	c.mu.Lock()
+	c.infos[*scriptHash] = info
+	c.mu.Unlock()
+	return &info, nil
+}
+
+// TokenBalance is the balance of one nep17 token held by an address
+type TokenBalance struct {
+	TokenInfo
+	Amount           *big.Int
+	LastUpdatedBlock int
+}
+
+// FormattedAmount returns the amount with the decimal point placed according to the token decimals
+func (b *TokenBalance) FormattedAmount() string {
+	return FormatAmount(b.Amount, b.Decimals)
+}
+
+// GetTokenBalances returns all nep17 balances of the address with the token metadata,
+// the metadata is read from the cache
+func GetTokenBalances(cache *TokenInfoCache, address string) ([]TokenBalance, error) {
+	response := cache.Client.GetNep17Balances(address)
+	if response.HasError() {
+		return nil, fmt.Errorf(response.GetErrorInfo())
+	}
+	result := make([]TokenBalance, len(response.Result.Balances))
+	for i, b := range response.Result.Balances {
+		scriptHash, err := helper.UInt160FromString(b.AssetHash)
+		if err != nil {
+			return nil, err
+		}
+		info, err := cache.Get(scriptHash)
+		if err != nil {
+			return nil, err
+		}
+		amount, err := b.GetAmount()
+		if err != nil {
+			return nil, err
+		}
+		result[i] = TokenBalance{
+			TokenInfo:        *info,
+			Amount:           amount,
+			LastUpdatedBlock: b.LastUpdatedBlock,
+		}
+	}
+	return result, nil
+}
+
+// FormatAmount formats an integer amount of a token with decimals, e.g. 150000000 with 8 decimals is "1.5"
+func FormatAmount(amount *big.Int, decimals int) string {
+	s := new(big.Int).Abs(amount).String()
+	if decimals > 0 {
+		if len(s) <= decimals {
+			s = strings.Repeat("0", decimals-len(s)+1) + s
+		}
+		s = s[:len(s)-decimals] + "." + s[len(s)-decimals:]
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+	if amount.Sign() < 0 {
+		s = "-" + s
+	}
+	return s
+}
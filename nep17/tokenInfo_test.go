@@ -0,0 +1,68 @@
+package nep17
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/rpc"
+	"github.com/joeqian10/neo3-gogogo/rpc/models"
+	"github.com/joeqian10/neo3-gogogo/tx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func mockTokenInfo(clientMock *rpc.RpcClientMock, scriptHash *helper.UInt160, method string, stack models.InvokeStack) {
+	clientMock.On("InvokeFunction", "0x"+scriptHash.String(), method, mock.Anything, mock.Anything).Return(rpc.InvokeResultResponse{
+		RpcResponse: rpc.RpcResponse{JsonRpc: "2.0", ID: 1},
+		Result: models.InvokeResult{
+			State:       "HALT",
+			GasConsumed: "1007390",
+			Stack:       []models.InvokeStack{stack},
+		},
+	})
+}
+
+func TestGetTokenBalances(t *testing.T) {
+	var clientMock = new(rpc.RpcClientMock)
+	clientMock.On("GetNep17Balances", "NVVwFw6XyhtRCFQ8SpUTMdPyYt4Vd9A1XQ").Return(rpc.GetNep17BalancesResponse{
+		RpcResponse: rpc.RpcResponse{JsonRpc: "2.0", ID: 1},
+		Result: models.RpcNep17Balances{
+			Balances: []models.RpcNep17Balance{
+				{AssetHash: tx.NeoTokenId, Amount: "100", LastUpdatedBlock: 14},
+				{AssetHash: tx.GasTokenId, Amount: "9995000000050", LastUpdatedBlock: 17145},
+			},
+			Address: "NVVwFw6XyhtRCFQ8SpUTMdPyYt4Vd9A1XQ",
+		},
+	})
+	mockTokenInfo(clientMock, tx.NeoToken, "symbol", models.InvokeStack{Type: "ByteString", Value: "TkVP"})
+	mockTokenInfo(clientMock, tx.NeoToken, "decimals", models.InvokeStack{Type: "Integer", Value: "0"})
+	mockTokenInfo(clientMock, tx.GasToken, "symbol", models.InvokeStack{Type: "ByteString", Value: "R0FT"})
+	mockTokenInfo(clientMock, tx.GasToken, "decimals", models.InvokeStack{Type: "Integer", Value: "8"})
+
+	cache := NewTokenInfoCache(clientMock)
+	balances, err := GetTokenBalances(cache, "NVVwFw6XyhtRCFQ8SpUTMdPyYt4Vd9A1XQ")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(balances))
+	assert.Equal(t, "NEO", balances[0].Symbol)
+	assert.Equal(t, 0, balances[0].Decimals)
+	assert.Equal(t, "100", balances[0].FormattedAmount())
+	assert.Equal(t, "GAS", balances[1].Symbol)
+	assert.Equal(t, big.NewInt(9995000000050), balances[1].Amount)
+	assert.Equal(t, "99950.0000005", balances[1].FormattedAmount())
+	assert.Equal(t, 17145, balances[1].LastUpdatedBlock)
+
+	// the token metadata is cached
+	_, err = GetTokenBalances(cache, "NVVwFw6XyhtRCFQ8SpUTMdPyYt4Vd9A1XQ")
+	assert.Nil(t, err)
+	clientMock.AssertNumberOfCalls(t, "InvokeFunction", 4)
+	clientMock.AssertNumberOfCalls(t, "GetNep17Balances", 2)
+}
+
+func TestFormatAmount(t *testing.T) {
+	assert.Equal(t, "1.5", FormatAmount(big.NewInt(150000000), 8))
+	assert.Equal(t, "0.00000001", FormatAmount(big.NewInt(1), 8))
+	assert.Equal(t, "-0.1", FormatAmount(big.NewInt(-10), 2))
+	assert.Equal(t, "0", FormatAmount(big.NewInt(0), 8))
+	assert.Equal(t, "12", FormatAmount(big.NewInt(12), 0))
+}
@@ -0,0 +1,112 @@
+package nep17
+
+import (
+	"math/big"
+
+	"github.com/joeqian10/neo3-gogogo/crypto"
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/rpc/models"
+	"github.com/joeqian10/neo3-gogogo/vm"
+)
+
+// TransferRecord is a nep17 Transfer notification in an application log
+type TransferRecord struct {
+	TxHash   string
+	Contract *helper.UInt160
+	Event    string
+	From     *helper.UInt160 // nil when minted
+	To       *helper.UInt160 // nil when burned
+	Amount   *big.Int
+}
+
+// TransferRecordCSVHeader is the header row of the csv records
+var TransferRecordCSVHeader = []string{"txhash", "contract", "event", "from", "to", "amount"}
+
+// CSVRecord returns the record as a csv row, mint and burn have an empty from or to
+func (r *TransferRecord) CSVRecord(addressVersion byte) []string {
+	from, to := "", ""
+	if r.From != nil {
+		from = crypto.ScriptHashToAddress(r.From, addressVersion)
+	}
+	if r.To != nil {
+		to = crypto.ScriptHashToAddress(r.To, addressVersion)
+	}
+	return []string{r.TxHash, "0x" + r.Contract.String(), r.Event, from, to, r.Amount.String()}
+}
+
+// DecodeTransferRecords flattens all the nep17 Transfer notifications of the halted executions in the log,
+// notifications named Transfer but not in the nep17 format (e.g. nep11) are skipped
+func DecodeTransferRecords(log *models.RpcApplicationLog) []TransferRecord {
+	records := make([]TransferRecord, 0)
+	for _, execution := range log.Executions {
+		if execution.VMState != "HALT" {
+			continue
+		}
+		for _, notification := range execution.Notifications {
+			if notification.EventName != "Transfer" {
+				continue
+			}
+			record, ok := decodeTransfer(notification)
+			if !ok {
+				continue
+			}
+			record.TxHash = log.TxId
+			records = append(records, *record)
+		}
+	}
+	return records
+}
+
+func decodeTransfer(notification models.RpcNotification) (*TransferRecord, bool) {
+	contract, err := helper.UInt160FromString(notification.Contract)
+	if err != nil {
+		return nil, false
+	}
+	state := notification.State // copy, Convert should not change the log
+	state.Convert()
+	items, ok := state.Value.([]models.InvokeStack)
+	if !ok || len(items) != 3 {
+		return nil, false
+	}
+	from, ok := decodeAccount(items[0])
+	if !ok {
+		return nil, false
+	}
+	to, ok := decodeAccount(items[1])
+	if !ok {
+		return nil, false
+	}
+	if items[2].Type != vm.Integer.String() {
+		return nil, false
+	}
+	amount, err := items[2].ToParameter()
+	if err != nil {
+		return nil, false
+	}
+	return &TransferRecord{
+		Contract: contract,
+		Event:    notification.EventName,
+		From:     from,
+		To:       to,
+		Amount:   amount.Value.(*big.Int),
+	}, true
+}
+
+// decodeAccount decodes the from or to of a transfer, Any is decoded to nil
+func decodeAccount(item models.InvokeStack) (*helper.UInt160, bool) {
+	if item.Type == vm.Any.String() {
+		return nil, true
+	}
+	if item.Type != vm.ByteString.String() {
+		return nil, false
+	}
+	p, err := item.ToParameter()
+	if err != nil {
+		return nil, false
+	}
+	b := p.Value.([]byte)
+	if len(b) != helper.UINT160SIZE {
+		return nil, false
+	}
+	return helper.UInt160FromBytes(b), true
+}
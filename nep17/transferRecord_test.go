@@ -0,0 +1,96 @@
+package nep17
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/rpc/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeTransferRecords(t *testing.T) {
+	log := models.RpcApplicationLog{}
+	err := json.Unmarshal([]byte(`{
+		"txid": "0x3d39da5b227e3f02f5210b24690a0523162788668e490363c6a39813bb162e51",
+		"executions": [
+			{
+				"trigger": "Application",
+				"vmstate": "HALT",
+				"gasconsumed": "9977780",
+				"stack": [],
+				"notifications": [
+					{
+						"contract": "0xd2a4cff31913016155e38e474a2c06d08be276cf",
+						"eventname": "Transfer",
+						"state": {
+							"type": "Array",
+							"value": [
+								{"type": "ByteString", "value": "mQOww9KSmI/r5fMGoC9lTqLrFik="},
+								{"type": "ByteString", "value": "z3bii9AGLEpHjuNVYQETGfPPpNI="},
+								{"type": "Integer", "value": "100000000"}
+							]
+						}
+					},
+					{
+						"contract": "0xef4073a0f2b305a38ec4050e4d3d28bc40ea63f5",
+						"eventname": "Transfer",
+						"state": {
+							"type": "Array",
+							"value": [
+								{"type": "Any"},
+								{"type": "ByteString", "value": "mQOww9KSmI/r5fMGoC9lTqLrFik="},
+								{"type": "Integer", "value": "10"}
+							]
+						}
+					},
+					{
+						"contract": "0xef4073a0f2b305a38ec4050e4d3d28bc40ea63f5",
+						"eventname": "Vote",
+						"state": {"type": "Array", "value": []}
+					},
+					{
+						"contract": "0x8c23f196d8a1bfd103a9dcb1f9ccf0c611377d3b",
+						"eventname": "Transfer",
+						"state": {
+							"type": "Array",
+							"value": [
+								{"type": "Any"},
+								{"type": "ByteString", "value": "mQOww9KSmI/r5fMGoC9lTqLrFik="},
+								{"type": "Integer", "value": "1"},
+								{"type": "ByteString", "value": "dG9rZW4x"}
+							]
+						}
+					}
+				]
+			}
+		]
+	}`), &log)
+	assert.Nil(t, err)
+
+	records := DecodeTransferRecords(&log)
+	assert.Equal(t, 2, len(records))
+	account, _ := helper.UInt160FromString("0x2916eba24e652fa006f3e5eb8f9892d2c3b00399")
+	gas, _ := helper.UInt160FromString("0xd2a4cff31913016155e38e474a2c06d08be276cf")
+	assert.Equal(t, gas, records[0].Contract)
+	assert.Equal(t, account, records[0].From)
+	assert.Equal(t, gas, records[0].To)
+	assert.Equal(t, big.NewInt(100000000), records[0].Amount)
+	assert.Equal(t, log.TxId, records[0].TxHash)
+	assert.Nil(t, records[1].From) // minted
+	assert.Equal(t, account, records[1].To)
+
+	sb := new(strings.Builder)
+	w := csv.NewWriter(sb)
+	_ = w.Write(TransferRecordCSVHeader)
+	for _, r := range records {
+		_ = w.Write(r.CSVRecord(helper.DefaultAddressVersion))
+	}
+	w.Flush()
+	lines := strings.Split(strings.TrimSpace(sb.String()), "\n")
+	assert.Equal(t, 3, len(lines))
+	assert.Equal(t, "0x3d39da5b227e3f02f5210b24690a0523162788668e490363c6a39813bb162e51,0xef4073a0f2b305a38ec4050e4d3d28bc40ea63f5,Transfer,,NZs2zXSPuuv9ZF6TDGSWT1RBmE8rfGj7UW,10", lines[2])
+}
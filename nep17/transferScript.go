@@ -0,0 +1,64 @@
+package nep17
+
+import (
+	"encoding/binary"
+	"math/big"
+
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/sc"
+)
+
+// ScriptTransfer is a nep17 transfer called in a script
+type ScriptTransfer struct {
+	Token  *helper.UInt160
+	From   *helper.UInt160
+	To     *helper.UInt160
+	Amount *big.Int
+}
+
+// ParseTransferScript finds the nep17 transfers called in the script without running it. It is best effort: only
+// the calls made by EmitDynamicCall are recognized, i.e. the arguments data, amount, to and from pushed in reverse
+// and packed, then the call flags, "transfer" and the token hash before System.Contract.Call, with the accounts
+// pushed as hashes and the amount as an integer. ok is false if no transfer is found
+func ParseTransferScript(script []byte) (transfers []ScriptTransfer, ok bool) {
+	instructions, err := sc.Disassemble(script)
+	if err != nil {
+		return nil, false
+	}
+	contractCall := uint32(sc.System_Contract_Call.ToInteropMethodHash())
+	for i, ins := range instructions {
+		if ins.OpCode != sc.SYSCALL || binary.LittleEndian.Uint32(ins.Operand) != contractCall || i < 8 {
+			continue
+		}
+		// amount, to, from, PUSH4, PACK, flags, method, hash, SYSCALL
+		token, ok := pushedHash(instructions[i-1])
+		if !ok || instructions[i-2].OpCode != sc.PUSHDATA1 || string(instructions[i-2].Operand) != "transfer" {
+			continue
+		}
+		if _, ok := instructions[i-3].GetInteger(); !ok || instructions[i-4].OpCode != sc.PACK || instructions[i-5].OpCode != sc.PUSH4 {
+			continue
+		}
+		from, ok := pushedHash(instructions[i-6])
+		if !ok {
+			continue
+		}
+		to, ok := pushedHash(instructions[i-7])
+		if !ok {
+			continue
+		}
+		amount, ok := instructions[i-8].GetInteger()
+		if !ok {
+			continue
+		}
+		transfers = append(transfers, ScriptTransfer{Token: token, From: from, To: to, Amount: amount})
+	}
+	return transfers, len(transfers) > 0
+}
+
+// pushedHash returns the script hash pushed by the instruction
+func pushedHash(ins sc.Instruction) (*helper.UInt160, bool) {
+	if ins.OpCode != sc.PUSHDATA1 || len(ins.Operand) != helper.UINT160SIZE {
+		return nil, false
+	}
+	return helper.UInt160FromBytes(ins.Operand), true
+}
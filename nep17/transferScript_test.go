@@ -0,0 +1,50 @@
+package nep17
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/rpc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTransferScript(t *testing.T) {
+	neo, _ := helper.UInt160FromString("0xef4073a0f2b305a38ec4050e4d3d28bc40ea63f5")
+	gas, _ := helper.UInt160FromString("0xd2a4cff31913016155e38e474a2c06d08be276cf")
+	from, _ := helper.UInt160FromString("0x2916eba24e652fa006f3e5eb8f9892d2c3b00399")
+	to, _ := helper.UInt160FromString("0x9bde8f209c88dd0e7ca3bf0af0f476cdd8207789")
+
+	// transfer 1 NEO with null data, then ASSERT
+	script := helper.HexToBytes("0b110c14897720d8cd76f4f00abfa37c0edd889c208fde9b0c149903b0c3d292988febe5f306a02f654ea2eb162914c01f0c087472616e736665720c14f563ea40bc283d4d0e05c48ea305b3f2a07340ef41627d5b5239")
+	transfers, ok := ParseTransferScript(script)
+	assert.True(t, ok)
+	assert.Equal(t, 1, len(transfers))
+	assert.True(t, neo.Equals(transfers[0].Token))
+	assert.True(t, from.Equals(transfers[0].From))
+	assert.True(t, to.Equals(transfers[0].To))
+	assert.Equal(t, big.NewInt(1), transfers[0].Amount)
+
+	// two tokens with data
+	nh := NewNep17Helper(gas, new(rpc.RpcClientMock))
+	script, err := nh.CreateTransferScript(from, to, big.NewInt(150000000), "memo")
+	assert.Nil(t, err)
+	multi, _, err := MakeMultiTransferScript(to, from, []TokenAmount{{Token: neo, Amount: big.NewInt(100000)}})
+	assert.Nil(t, err)
+	transfers, ok = ParseTransferScript(append(script, multi...))
+	assert.True(t, ok)
+	assert.Equal(t, 2, len(transfers))
+	assert.True(t, gas.Equals(transfers[0].Token))
+	assert.Equal(t, big.NewInt(150000000), transfers[0].Amount)
+	assert.True(t, neo.Equals(transfers[1].Token))
+	assert.True(t, to.Equals(transfers[1].From))
+	assert.Equal(t, big.NewInt(100000), transfers[1].Amount)
+
+	// balanceOf is not a transfer
+	script = helper.HexToBytes("0c146925aa554712439a9c613ba114efa3fac23ddbca11c00c0962616c616e63654f660c143b7d3711c6f0ccf9b1dca903d1bfa1d896f1238c41627d5b52")
+	_, ok = ParseTransferScript(script)
+	assert.False(t, ok)
+
+	_, ok = ParseTransferScript([]byte{0xff})
+	assert.False(t, ok)
+}
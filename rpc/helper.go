@@ -1,8 +1,20 @@
 package rpc
 
 import (
+	"encoding/hex"
 	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/joeqian10/neo3-gogogo/crypto"
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/keys"
+	"github.com/joeqian10/neo3-gogogo/mpt"
 	"github.com/joeqian10/neo3-gogogo/rpc/models"
+	"github.com/joeqian10/neo3-gogogo/sc"
+	"github.com/joeqian10/neo3-gogogo/tx"
 )
 
 func PopInvokeStack(response InvokeResultResponse) (*models.InvokeStack, error) {
@@ -23,3 +35,328 @@ func PopInvokeStack(response InvokeResultResponse) (*models.InvokeStack, error)
 	stack.Convert()
 	return &stack, nil
 }
+
+// DefaultMaxSystemFee is the default MaxBlockSystemFee of the node, a transaction with a higher system fee
+// is rejected
+const DefaultMaxSystemFee int64 = 150000_00000000
+
+// AttachSystemFeeFromDryRun runs the script of the builder by invokescript with the signers,
+// and sets the system fee of the builder to the gas consumed if the engine halts
+func AttachSystemFeeFromDryRun(client IRpcClient, builder *tx.TransactionBuilder, signers []tx.Signer) (int64, error) {
+	sysfee, err := dryRun(client, builder.GetScript(), signers)
+	if err != nil {
+		return 0, err
+	}
+	builder.SetSystemFee(sysfee)
+	return sysfee, nil
+}
+
+// CheckSystemFee runs the script by invokescript with the signers and returns the gas consumed, or an error if
+// the engine faults or the gas consumed is above maxSystemFee, so a transaction that would be rejected is not
+// sent. The limit is configured by the node and not exposed by RPC, DefaultMaxSystemFee is used if
+// maxSystemFee is not positive. A script running out of the gas limit of invokescript faults
+func CheckSystemFee(client IRpcClient, script []byte, signers []tx.Signer, maxSystemFee int64) (int64, error) {
+	if maxSystemFee <= 0 {
+		maxSystemFee = DefaultMaxSystemFee
+	}
+	sysfee, err := dryRun(client, script, signers)
+	if err != nil {
+		return 0, err
+	}
+	if sysfee > maxSystemFee {
+		return sysfee, fmt.Errorf("system fee %d is above the max %d", sysfee, maxSystemFee)
+	}
+	return sysfee, nil
+}
+
+// dryRun runs the script by invokescript with the signers and returns the gas consumed if the engine halts
+func dryRun(client IRpcClient, script []byte, signers []tx.Signer) (int64, error) {
+	if len(script) == 0 {
+		return 0, fmt.Errorf("script is empty")
+	}
+	response := client.InvokeScript(crypto.Base64Encode(script), models.CreateRpcSigners(signers))
+	if response.HasError() {
+		return 0, fmt.Errorf(response.GetErrorInfo())
+	}
+	if response.Result.GetState() != "HALT" {
+		msg := "engine faulted"
+		if len(response.Result.Exception) != 0 {
+			msg += ", exception: " + response.Result.Exception
+		}
+		return 0, fmt.Errorf(msg)
+	}
+	return response.Result.GetGasConsumed()
+}
+
+// QuickSend builds a transaction running the script signed by the account of the wif with the scope, and
+// returns it serialized in hex, convert it to base64 before passing it to SendRawTransaction. The network
+// magic and the valid until block come from getversion and getblockcount, the system fee from a dry run of
+// the script and the network fee from calculatenetworkfee
+func QuickSend(client IRpcClient, scriptHex string, wif string, scope tx.WitnessScope) (string, error) {
+	pair, err := keys.NewKeyPairFromWIF(wif)
+	if err != nil {
+		return "", err
+	}
+	script, err := hex.DecodeString(scriptHex)
+	if err != nil {
+		return "", err
+	}
+	version := client.GetVersion()
+	if version.HasError() {
+		return "", fmt.Errorf(version.GetErrorInfo())
+	}
+	count := client.GetBlockCount()
+	if count.HasError() {
+		return "", fmt.Errorf(count.GetErrorInfo())
+	}
+	increment := version.Result.Protocol.MaxValidUntilBlockIncrement
+	if increment == 0 {
+		increment = tx.MaxValidUntilBlockIncrement
+	}
+	signers := []tx.Signer{*tx.NewSigner(keys.PublicKeyToScriptHash(pair.PublicKey), scope)}
+	builder := tx.NewTransactionBuilder().
+		SetScript(script).
+		SetSigners(signers).
+		SetValidUntilBlock(uint32(count.Result) - 1 + increment)
+	if _, err = AttachSystemFeeFromDryRun(client, builder, signers); err != nil {
+		return "", err
+	}
+
+	// the node finds the verification script of a standard account in the witness
+	trx, err := builder.Build()
+	if err != nil {
+		return "", err
+	}
+	verificationScript, err := sc.CreateSignatureRedeemScript(pair.PublicKey)
+	if err != nil {
+		return "", err
+	}
+	trx.SetWitnesses([]tx.Witness{{InvocationScript: []byte{}, VerificationScript: verificationScript}})
+	fee := client.CalculateNetworkFee(crypto.Base64Encode(trx.ToByteArray()))
+	if fee.HasError() {
+		return "", fmt.Errorf(fee.GetErrorInfo())
+	}
+	netfee, err := strconv.ParseInt(fee.Result.NetworkFee, 10, 64)
+	if err != nil {
+		return "", err
+	}
+	builder.SetNetworkFee(netfee)
+	return tx.BuildSignSerialize(builder, pair.PrivateKey, version.Result.Protocol.Network)
+}
+
+// IsContractAddress decodes the address and returns its script hash, and whether a contract is
+// deployed at it by getcontractstate. A contract without onNEP17Payment can not receive nep17 tokens
+func IsContractAddress(client IRpcClient, address string, addressVersion byte) (bool, *helper.UInt160, error) {
+	scriptHash, err := crypto.AddressToScriptHash(address, addressVersion)
+	if err != nil {
+		return false, nil, err
+	}
+	response := client.GetContractState("0x" + scriptHash.String())
+	if response.NetError != nil {
+		return false, nil, response.NetError
+	}
+	if response.HasError() {
+		if response.Error.Code == -100 || strings.Contains(response.Error.Message, "Unknown contract") {
+			return false, scriptHash, nil
+		}
+		return false, nil, fmt.Errorf(response.GetErrorInfo())
+	}
+	return true, scriptHash, nil
+}
+
+// Nep17TransfersMaxResults is the max count of sent or received transfers getnep17transfers returns, it is
+// MaxResults in the config of TokensTracker, 1000 by default. Set it to the value of the node
+var Nep17TransfersMaxResults = 1000
+
+// GetNep17TransfersInRange gets all nep17 transfers of the address between the timestamps in milliseconds.
+// The node returns a limited count of sent and of received transfers from the start time, so each list is
+// paged on its own from the latest timestamp it received. A page holding only the timestamp it starts from
+// is stepped past, unless it may be cut by the limit of the node, i.e. more transfers share one timestamp
+// than a page can hold, then an error is returned. Sent and received transfers are merged and sorted by time
+func GetNep17TransfersInRange(client IRpcClient, address string, startTime int, endTime int) ([]models.RpcNep17TransferEntry, error) {
+	if startTime > endTime {
+		return nil, fmt.Errorf("start time %d is after end time %d", startTime, endTime)
+	}
+	type transferKey struct {
+		txHash      string
+		blockIndex  int
+		notifyIndex int
+		sent        bool
+	}
+	// both lists come in one response, so a page is shared when the cursors meet
+	pages := make(map[int]models.RpcNep17Transfers)
+	getPage := func(start int) (models.RpcNep17Transfers, error) {
+		if page, ok := pages[start]; ok {
+			return page, nil
+		}
+		s, e := start, endTime
+		response := client.GetNep17Transfers(address, &s, &e)
+		if response.HasError() {
+			return models.RpcNep17Transfers{}, fmt.Errorf(response.GetErrorInfo())
+		}
+		pages[start] = response.Result
+		return response.Result, nil
+	}
+	seen := make(map[transferKey]bool)
+	result := make([]models.RpcNep17TransferEntry, 0)
+	for _, sent := range []bool{true, false} {
+		start, lastLen, lastMixed := startTime, 0, false
+		for start <= endTime {
+			page, err := getPage(start)
+			if err != nil {
+				return nil, err
+			}
+			transfers := page.Received
+			if sent {
+				transfers = page.Sent
+			}
+			if len(transfers) == 0 {
+				break
+			}
+			next, first := start, transfers[0].Timestamp
+			for _, t := range transfers {
+				if t.Timestamp > next {
+					next = t.Timestamp
+				}
+				if t.Timestamp < first {
+					first = t.Timestamp
+				}
+				key := transferKey{t.TxHash, t.BlockIndex, t.TransferNotifyIndex, sent}
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+				result = append(result, models.RpcNep17TransferEntry{RpcNep17Transfer: t, Sent: sent})
+			}
+			if next == start {
+				// the page holds only this timestamp, it is cut if it is full. A last page which also had earlier
+				// timestamps holds fewer transfers at this one, so this page is not longer than it unless both are full
+				if len(transfers) >= Nep17TransfersMaxResults || (lastMixed && len(transfers) >= lastLen) {
+					return nil, fmt.Errorf("more transfers at timestamp %d than the node returns in a page", start)
+				}
+				next = start + 1
+			}
+			start, lastLen, lastMixed = next, len(transfers), first < next
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Timestamp != result[j].Timestamp {
+			return result[i].Timestamp < result[j].Timestamp
+		}
+		if result[i].BlockIndex != result[j].BlockIndex {
+			return result[i].BlockIndex < result[j].BlockIndex
+		}
+		return result[i].TransferNotifyIndex < result[j].TransferNotifyIndex
+	})
+	return result, nil
+}
+
+// GetNep17BalanceChanges returns the net nep17 balance changes of the address from block fromHeight to block toHeight,
+// i.e. the transfers in blocks after fromHeight up to and including toHeight, keyed by asset hash.
+// The transfers are fetched by the time range of the two block headers
+func GetNep17BalanceChanges(client IRpcClient, address string, fromHeight int, toHeight int) (map[string]*big.Int, error) {
+	if fromHeight > toHeight {
+		return nil, fmt.Errorf("from height %d is above to height %d", fromHeight, toHeight)
+	}
+	from := client.GetBlockHeader(strconv.Itoa(fromHeight))
+	if from.HasError() {
+		return nil, fmt.Errorf(from.GetErrorInfo())
+	}
+	to := client.GetBlockHeader(strconv.Itoa(toHeight))
+	if to.HasError() {
+		return nil, fmt.Errorf(to.GetErrorInfo())
+	}
+	transfers, err := GetNep17TransfersInRange(client, address, from.Result.Time, to.Result.Time)
+	if err != nil {
+		return nil, err
+	}
+	return models.SumNep17Transfers(transfers, fromHeight, toHeight)
+}
+
+// FindVerifiedStates enumerates the storage of the contract whose keys start with the prefix, at the state root
+// of the block height, without trusting the node. The keys are listed by findstates, each of them is proven by
+// getproof, and the trie made of the proofs is walked locally under the prefix by mpt.FindStates, so the walk
+// fails if the node leaves a key out. The state root should be validated, see getstateheight. An empty storage
+// under the prefix can not be proven this way and returns an error
+func FindVerifiedStates(client IRpcClient, blockHeight uint32, contractHash string, prefix []byte) ([]mpt.KeyValue, error) {
+	stateRoot := client.GetStateRoot(blockHeight)
+	if stateRoot.HasError() {
+		return nil, fmt.Errorf(stateRoot.GetErrorInfo())
+	}
+	root, err := helper.UInt256FromString(stateRoot.Result.RootHash)
+	if err != nil {
+		return nil, err
+	}
+	contractState := client.GetContractState(contractHash)
+	if contractState.HasError() {
+		return nil, fmt.Errorf(contractState.GetErrorInfo())
+	}
+	rootHash := stateRoot.Result.RootHash
+	prefixInBase64 := crypto.Base64Encode(prefix)
+	storeKeys := []string{}
+	from := ""
+	for {
+		found := client.FindStates(rootHash, contractHash, prefixInBase64, from, 0)
+		if found.HasError() {
+			return nil, fmt.Errorf(found.GetErrorInfo())
+		}
+		for _, state := range found.Result.Results {
+			storeKeys = append(storeKeys, state.Key)
+		}
+		if !found.Result.Truncated {
+			break
+		}
+		if len(found.Result.Results) == 0 {
+			return nil, fmt.Errorf("findstates is truncated without results")
+		}
+		from = found.Result.Results[len(found.Result.Results)-1].Key
+	}
+	proofs := make([][]byte, len(storeKeys))
+	for i, key := range storeKeys {
+		proof := client.GetProof(rootHash, contractHash, key)
+		if proof.HasError() {
+			return nil, fmt.Errorf(proof.GetErrorInfo())
+		}
+		proofs[i], err = crypto.Base64Decode(proof.Result)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return mpt.FindStates(root, contractState.Result.Id, prefix, proofs)
+}
+
+// GetRequiredWitnesses returns the accounts whose witness is checked when running the script of the result,
+// found by sc.GetCheckedWitnesses. If the result has diagnostics, the checks by contracts not invoked are dropped
+func GetRequiredWitnesses(result *models.InvokeResult) ([]helper.UInt160, error) {
+	script, err := crypto.Base64Decode(result.Script)
+	if err != nil {
+		return nil, err
+	}
+	checks, err := sc.GetCheckedWitnesses(script)
+	if err != nil {
+		return nil, err
+	}
+	var invoked map[helper.UInt160]bool
+	if result.Diagnostics != nil {
+		invoked = make(map[helper.UInt160]bool)
+		for _, h := range result.Diagnostics.GetInvokedContracts() {
+			hash, err := helper.UInt160FromString(h)
+			if err != nil {
+				return nil, err
+			}
+			invoked[*hash] = true
+		}
+	}
+	accounts := make([]helper.UInt160, 0)
+	seen := make(map[helper.UInt160]bool)
+	for _, check := range checks {
+		if invoked != nil && check.Contract != nil && !invoked[*check.Contract] {
+			continue
+		}
+		if !seen[check.Account] {
+			seen[check.Account] = true
+			accounts = append(accounts, check.Account)
+		}
+	}
+	return accounts, nil
+}
@@ -2,12 +2,19 @@ package rpc
 
 import (
 	"bytes"
+	"github.com/joeqian10/neo3-gogogo/crypto"
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/io"
+	"github.com/joeqian10/neo3-gogogo/keys"
+	"github.com/joeqian10/neo3-gogogo/mpt"
 	"github.com/joeqian10/neo3-gogogo/rpc/models"
+	"github.com/joeqian10/neo3-gogogo/tx"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"strconv"
 	"testing"
 )
 
@@ -79,3 +86,424 @@ func TestInvokeStack_Convert(t *testing.T) {
 	assert.False(t, response.HasError())
 }
 
+func TestAttachSystemFeeFromDryRun(t *testing.T) {
+	script := helper.HexToBytes("0c146925aa554712439a9c613ba114efa3fac23ddbca11c00c0962616c616e63654f660c143b7d3711c6f0ccf9b1dca903d1bfa1d896f1238c41627d5b52")
+	account, _ := helper.UInt160FromString("0x2916eba24e652fa006f3e5eb8f9892d2c3b00399")
+	signers := []tx.Signer{*tx.NewSigner(account, tx.CalledByEntry)}
+	builder := tx.NewTransactionBuilder().SetScript(script).SetSigners(signers)
+
+	client := new(RpcClientMock)
+	client.On("InvokeScript", crypto.Base64Encode(script), models.CreateRpcSigners(signers)).Return(InvokeResultResponse{
+		Result: models.InvokeResult{
+			Script:      crypto.Base64Encode(script),
+			State:       "HALT",
+			GasConsumed: "2007570",
+			Stack:       []models.InvokeStack{{Type: "Integer", Value: "8913620128"}},
+		},
+	})
+	sysfee, err := AttachSystemFeeFromDryRun(client, builder, signers)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2007570), sysfee)
+	assert.Equal(t, int64(2007570), builder.GetSystemFee())
+	trx, err := builder.Build()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2007570), trx.GetSystemFee())
+
+	client = new(RpcClientMock)
+	client.On("InvokeScript", mock.Anything, mock.Anything).Return(InvokeResultResponse{
+		Result: models.InvokeResult{State: "FAULT", GasConsumed: "1000", Exception: "insufficient funds"},
+	})
+	builder = tx.NewTransactionBuilder().SetScript(script)
+	_, err = AttachSystemFeeFromDryRun(client, builder, signers)
+	assert.EqualError(t, err, "engine faulted, exception: insufficient funds")
+	assert.Equal(t, int64(0), builder.GetSystemFee())
+}
+
+func TestCheckSystemFee(t *testing.T) {
+	script := helper.HexToBytes("0c146925aa554712439a9c613ba114efa3fac23ddbca11c00c0962616c616e63654f660c143b7d3711c6f0ccf9b1dca903d1bfa1d896f1238c41627d5b52")
+	account, _ := helper.UInt160FromString("0x2916eba24e652fa006f3e5eb8f9892d2c3b00399")
+	signers := []tx.Signer{*tx.NewSigner(account, tx.CalledByEntry)}
+
+	client := new(RpcClientMock)
+	client.On("InvokeScript", crypto.Base64Encode(script), models.CreateRpcSigners(signers)).Return(InvokeResultResponse{
+		Result: models.InvokeResult{State: "HALT", GasConsumed: "2007570"},
+	})
+	sysfee, err := CheckSystemFee(client, script, signers, 2007570)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2007570), sysfee)
+	sysfee, err = CheckSystemFee(client, script, signers, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2007570), sysfee)
+
+	// above the configured max
+	sysfee, err = CheckSystemFee(client, script, signers, 2000000)
+	assert.EqualError(t, err, "system fee 2007570 is above the max 2000000")
+	assert.Equal(t, int64(2007570), sysfee)
+
+	_, err = CheckSystemFee(client, nil, signers, 0)
+	assert.NotNil(t, err)
+}
+
+func TestQuickSend(t *testing.T) {
+	script := "0c146925aa554712439a9c613ba114efa3fac23ddbca11c00c0962616c616e63654f660c143b7d3711c6f0ccf9b1dca903d1bfa1d896f1238c41627d5b52"
+	pair, err := keys.NewKeyPairFromWIF(keys.KeyCases[0].Wif)
+	assert.Nil(t, err)
+	account := keys.PublicKeyToScriptHash(pair.PublicKey)
+
+	client := new(RpcClientMock)
+	client.On("GetVersion").Return(GetVersionResponse{
+		Result: models.RpcVersion{Protocol: models.RpcProtocol{Network: helper.N3Magic_TestNet, MaxValidUntilBlockIncrement: 5760}},
+	})
+	client.On("GetBlockCount").Return(GetBlockCountResponse{Result: 1000})
+	client.On("InvokeScript", mock.Anything, mock.Anything).Return(InvokeResultResponse{
+		Result: models.InvokeResult{State: "HALT", GasConsumed: "2007570"},
+	})
+	client.On("CalculateNetworkFee", mock.Anything).Return(CalculateNetworkFeeResponse{
+		Result: models.RpcNetworkFee{NetworkFee: "1217520"},
+	})
+
+	raw, err := QuickSend(client, script, keys.KeyCases[0].Wif, tx.CalledByEntry)
+	assert.Nil(t, err)
+	trx := tx.NewTransaction()
+	br := io.NewBinaryReaderFromBuf(helper.HexToBytes(raw))
+	trx.Deserialize(br)
+	assert.Nil(t, br.Err)
+	assert.Equal(t, script, helper.BytesToHex(trx.GetScript()))
+	assert.Equal(t, int64(2007570), trx.GetSystemFee())
+	assert.Equal(t, int64(1217520), trx.GetNetworkFee())
+	assert.Equal(t, uint32(999+5760), trx.GetValidUntilBlock())
+	assert.Equal(t, account, trx.GetSigners()[0].Account)
+	assert.Equal(t, tx.CalledByEntry, trx.GetSigners()[0].Scopes)
+	assert.True(t, tx.VerifySignatureWitness(tx.GetSignData(trx, helper.N3Magic_TestNet), &trx.GetWitnesses()[0]))
+
+	_, err = QuickSend(client, script, "invalid", tx.CalledByEntry)
+	assert.NotNil(t, err)
+	_, err = QuickSend(client, "0c1", keys.KeyCases[0].Wif, tx.CalledByEntry)
+	assert.NotNil(t, err)
+}
+
+func TestIsContractAddress(t *testing.T) {
+	gas, _ := helper.UInt160FromString("0xd2a4cff31913016155e38e474a2c06d08be276cf")
+	client := new(RpcClientMock)
+	client.On("GetContractState", "0x2916eba24e652fa006f3e5eb8f9892d2c3b00399").Return(GetContractStateResponse{
+		ErrorResponse: ErrorResponse{Error: RpcError{Code: -100, Message: "Unknown contract"}},
+	})
+	client.On("GetContractState", "0xd2a4cff31913016155e38e474a2c06d08be276cf").Return(GetContractStateResponse{
+		Result: models.RpcContractState{Id: -6, Hash: "0xd2a4cff31913016155e38e474a2c06d08be276cf"},
+	})
+
+	// wallet address
+	isContract, scriptHash, err := IsContractAddress(client, "NZs2zXSPuuv9ZF6TDGSWT1RBmE8rfGj7UW", helper.DefaultAddressVersion)
+	assert.Nil(t, err)
+	assert.False(t, isContract)
+	assert.Equal(t, "2916eba24e652fa006f3e5eb8f9892d2c3b00399", scriptHash.String())
+
+	// contract address
+	isContract, scriptHash, err = IsContractAddress(client, crypto.ScriptHashToAddress(gas, helper.DefaultAddressVersion), helper.DefaultAddressVersion)
+	assert.Nil(t, err)
+	assert.True(t, isContract)
+	assert.True(t, gas.Equals(scriptHash))
+
+	_, _, err = IsContractAddress(client, "invalid address", helper.DefaultAddressVersion)
+	assert.NotNil(t, err)
+}
+
+// nep17TransfersNode pages getnep17transfers like the node, at most limit sent and limit received transfers
+// from the start time, ordered by time
+type nep17TransfersNode struct {
+	RpcClientMock
+	limit    int
+	sent     []models.RpcNep17Transfer
+	received []models.RpcNep17Transfer
+	calls    int
+}
+
+func (n *nep17TransfersNode) page(transfers []models.RpcNep17Transfer, start int, end int) []models.RpcNep17Transfer {
+	result := []models.RpcNep17Transfer{}
+	for _, t := range transfers {
+		if t.Timestamp >= start && t.Timestamp <= end && len(result) < n.limit {
+			result = append(result, t)
+		}
+	}
+	return result
+}
+
+func (n *nep17TransfersNode) GetNep17Transfers(address string, startTime *int, endTime *int) GetNep17TransfersResponse {
+	n.calls++
+	return GetNep17TransfersResponse{Result: models.RpcNep17Transfers{
+		Sent:     n.page(n.sent, *startTime, *endTime),
+		Received: n.page(n.received, *startTime, *endTime),
+		Address:  address,
+	}}
+}
+
+func newNep17Transfer(timestamp int, amount string, blockIndex int, txHash string) models.RpcNep17Transfer {
+	return models.RpcNep17Transfer{
+		Timestamp:       timestamp,
+		AssetHash:       "0x9bde8f209c88dd0e7ca3bf0af0f476cdd8207789",
+		TransferAddress: "NZs2zXSPuuv9ZF6TDGSWT1RBmE8rfGj7UW",
+		Amount:          amount,
+		BlockIndex:      blockIndex,
+		TxHash:          txHash,
+	}
+}
+
+func TestGetNep17TransfersInRange(t *testing.T) {
+	sent := newNep17Transfer(1578471997998, "10000000", 72, "0xc28763714d06e80f28b431d0a24495f41961b7d2746fc4cdaec0607adf0d6749")
+	received1 := newNep17Transfer(1578471121898, "10000000", 14, "0xfc4b8454601e3df8c9ed03765f7860fce4ae2aa3d52e0f4790fd89f208ed051b")
+	received2 := newNep17Transfer(1578471999000, "90000000", 73, "0xadc751e8fc4e7514cf2fcd623ad78a565985b5701b04961445b3d4794015e19a")
+	address := "NVVwFw6XyhtRCFQ8SpUTMdPyYt4Vd9A1XQ"
+	start, end := 1578471000000, 1578472000000
+
+	client := &nep17TransfersNode{limit: 1, sent: []models.RpcNep17Transfer{sent}, received: []models.RpcNep17Transfer{received1, received2}}
+	transfers, err := GetNep17TransfersInRange(client, address, start, end)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(transfers))
+	assert.Equal(t, received1.TxHash, transfers[0].TxHash)
+	assert.False(t, transfers[0].Sent)
+	assert.Equal(t, sent.TxHash, transfers[1].TxHash)
+	assert.True(t, transfers[1].Sent)
+	assert.Equal(t, received2.TxHash, transfers[2].TxHash)
+
+	_, err = GetNep17TransfersInRange(client, address, end, start)
+	assert.NotNil(t, err)
+}
+
+func TestGetNep17TransfersInRange_SentCutBeforeReceived(t *testing.T) {
+	address := "NVVwFw6XyhtRCFQ8SpUTMdPyYt4Vd9A1XQ"
+	start, end := 1578471000000, 1578472000000
+	sent := []models.RpcNep17Transfer{
+		newNep17Transfer(start+1, "1", 1, "0x01"),
+		newNep17Transfer(start+2, "2", 2, "0x02"),
+		newNep17Transfer(start+3, "3", 3, "0x03"),
+	}
+	received := []models.RpcNep17Transfer{
+		newNep17Transfer(start+1, "4", 1, "0x04"),
+		newNep17Transfer(start+9, "5", 9, "0x05"),
+	}
+
+	// the first page cuts sent at start+2 and received ends at start+9, the sent one at start+3 must still be read
+	client := &nep17TransfersNode{limit: 2, sent: sent, received: received}
+	transfers, err := GetNep17TransfersInRange(client, address, start, end)
+	assert.Nil(t, err)
+	assert.Equal(t, 5, len(transfers))
+	hashes := []string{}
+	for _, transfer := range transfers {
+		hashes = append(hashes, transfer.TxHash)
+	}
+	assert.Equal(t, []string{"0x01", "0x04", "0x02", "0x03", "0x05"}, hashes)
+}
+
+func TestGetNep17TransfersInRange_PageOfOneTimestamp(t *testing.T) {
+	address := "NVVwFw6XyhtRCFQ8SpUTMdPyYt4Vd9A1XQ"
+	start, end := 1578471000000, 1578472000000
+
+	// a page of one timestamp as long as the last page, which had earlier ones, is cut
+	client := &nep17TransfersNode{limit: 2, sent: []models.RpcNep17Transfer{
+		newNep17Transfer(start+1, "1", 1, "0x01"),
+		newNep17Transfer(start+2, "2", 2, "0x02"),
+		newNep17Transfer(start+2, "3", 2, "0x03"),
+		newNep17Transfer(start+2, "4", 2, "0x04"),
+	}}
+	_, err := GetNep17TransfersInRange(client, address, start, end)
+	assert.NotNil(t, err)
+
+	// a full page of one timestamp is cut
+	client = &nep17TransfersNode{limit: 2, sent: []models.RpcNep17Transfer{
+		newNep17Transfer(start+1, "1", 1, "0x01"),
+		newNep17Transfer(start+1, "2", 1, "0x02"),
+		newNep17Transfer(start+1, "3", 1, "0x03"),
+	}}
+	maxResults := Nep17TransfersMaxResults
+	Nep17TransfersMaxResults = 2
+	_, err = GetNep17TransfersInRange(client, address, start, end)
+	Nep17TransfersMaxResults = maxResults
+	assert.NotNil(t, err)
+
+	// a page of one timestamp which is not full is stepped past
+	client = &nep17TransfersNode{limit: 3, sent: []models.RpcNep17Transfer{
+		newNep17Transfer(start+1, "1", 1, "0x01"),
+		newNep17Transfer(start+1, "2", 1, "0x02"),
+	}}
+	transfers, err := GetNep17TransfersInRange(client, address, start, end)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(transfers))
+	assert.Equal(t, 3, client.calls)
+}
+
+func TestGetNep17BalanceChanges(t *testing.T) {
+	neo, gas := "0xef4073a0f2b305a38ec4050e4d3d28bc40ea63f5", "0xd2a4cff31913016155e38e474a2c06d08be276cf"
+	before := models.RpcNep17Transfer{
+		Timestamp:       1578471121898,
+		AssetHash:       neo,
+		TransferAddress: "NZs2zXSPuuv9ZF6TDGSWT1RBmE8rfGj7UW",
+		Amount:          "100",
+		BlockIndex:      14,
+		TxHash:          "0xfc4b8454601e3df8c9ed03765f7860fce4ae2aa3d52e0f4790fd89f208ed051b",
+	}
+	sent := models.RpcNep17Transfer{
+		Timestamp:       1578471997998,
+		AssetHash:       neo,
+		TransferAddress: "NZs2zXSPuuv9ZF6TDGSWT1RBmE8rfGj7UW",
+		Amount:          "30",
+		BlockIndex:      72,
+		TxHash:          "0xc28763714d06e80f28b431d0a24495f41961b7d2746fc4cdaec0607adf0d6749",
+	}
+	fee := models.RpcNep17Transfer{
+		Timestamp:           1578471997998,
+		AssetHash:           gas,
+		Amount:              "9977780",
+		BlockIndex:          72,
+		TransferNotifyIndex: 1,
+		TxHash:              "0xc28763714d06e80f28b431d0a24495f41961b7d2746fc4cdaec0607adf0d6749",
+	}
+	received := models.RpcNep17Transfer{
+		Timestamp:       1578471999000,
+		AssetHash:       neo,
+		TransferAddress: "NZs2zXSPuuv9ZF6TDGSWT1RBmE8rfGj7UW",
+		Amount:          "50",
+		BlockIndex:      73,
+		TxHash:          "0xadc751e8fc4e7514cf2fcd623ad78a565985b5701b04961445b3d4794015e19a",
+	}
+	address := "NVVwFw6XyhtRCFQ8SpUTMdPyYt4Vd9A1XQ"
+	start, end := before.Timestamp, received.Timestamp
+
+	client := &nep17TransfersNode{limit: 2, sent: []models.RpcNep17Transfer{sent, fee}, received: []models.RpcNep17Transfer{before, received}}
+	client.On("GetBlockHeader", "14").Return(GetBlockHeaderResponse{Result: models.RpcBlockHeader{Index: 14, Time: start}})
+	client.On("GetBlockHeader", "73").Return(GetBlockHeaderResponse{Result: models.RpcBlockHeader{Index: 73, Time: end}})
+
+	changes, err := GetNep17BalanceChanges(client, address, 14, 73)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(changes))
+	assert.Equal(t, "20", changes[neo].String())
+	assert.Equal(t, "-9977780", changes[gas].String())
+
+	_, err = GetNep17BalanceChanges(client, address, 73, 14)
+	assert.NotNil(t, err)
+}
+
+func TestGetNep17BalanceChanges_SentCut(t *testing.T) {
+	neo, gas := "0xef4073a0f2b305a38ec4050e4d3d28bc40ea63f5", "0xd2a4cff31913016155e38e474a2c06d08be276cf"
+	newTransfer := func(asset string, timestamp int, amount string, blockIndex int, notifyIndex int) models.RpcNep17Transfer {
+		transfer := newNep17Transfer(timestamp, amount, blockIndex, "0x"+strconv.Itoa(blockIndex))
+		transfer.AssetHash, transfer.TransferNotifyIndex = asset, notifyIndex
+		return transfer
+	}
+	start, end := 1578471121898, 1578471999000
+	sent := []models.RpcNep17Transfer{
+		newTransfer(neo, 1578471500000, "10", 50, 0),
+		newTransfer(neo, 1578471600000, "10", 60, 0),
+		newTransfer(neo, 1578471997998, "30", 72, 0),
+		newTransfer(gas, 1578471997998, "9977780", 72, 1),
+	}
+	received := []models.RpcNep17Transfer{
+		newTransfer(neo, start, "100", 14, 0),
+		newTransfer(neo, end, "90", 73, 0),
+	}
+	address := "NVVwFw6XyhtRCFQ8SpUTMdPyYt4Vd9A1XQ"
+
+	// the first sent page ends before the fee while the received one reaches the last block
+	client := &nep17TransfersNode{limit: 3, sent: sent, received: received}
+	client.On("GetBlockHeader", "14").Return(GetBlockHeaderResponse{Result: models.RpcBlockHeader{Index: 14, Time: start}})
+	client.On("GetBlockHeader", "73").Return(GetBlockHeaderResponse{Result: models.RpcBlockHeader{Index: 73, Time: end}})
+
+	changes, err := GetNep17BalanceChanges(client, address, 14, 73)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(changes))
+	assert.Equal(t, "40", changes[neo].String())
+	assert.Equal(t, "-9977780", changes[gas].String())
+}
+
+func TestGetRequiredWitnesses(t *testing.T) {
+	var client = new(HttpClientMock)
+	var rpcClient = RpcClient{Endpoint: new(url.URL), httpClient: client}
+	// GAS.transfer from 0x2916eba24e652fa006f3e5eb8f9892d2c3b00399 and NEO.transfer from 0xedae9b97c72dbec43a7201b6388c24bfd86c71ae,
+	// the NEO transfer is not reached since the GAS transfer fails
+	client.On("Do", mock.Anything).Return(&http.Response{
+		Body: ioutil.NopCloser(bytes.NewReader([]byte(`{
+			"jsonrpc": "2.0",
+			"id": 1,
+			"result": {
+				"script": "CwIA4fUFDBSucWzYvySMOLYBcjrEvi3Hl5uu7QwUmQOww9KSmI/r5fMGoC9lTqLrFikUwB8MCHRyYW5zZmVyDBTPduKL0AYsSkeO41VhARMZ88+k0kFifVtSOQsRDBSZA7DD0pKYj+vl8wagL2VOousWKQwUrnFs2L8kjDi2AXI6xL4tx5ebru0UwB8MCHRyYW5zZmVyDBT1Y+pAvCg9TQ4FxI6jBbPyoHNA70FifVtSOQ==",
+				"state": "FAULT",
+				"gasconsumed": "1007390",
+				"exception": "ASSERT is executed with false result.",
+				"notifications": [],
+				"diagnostics": {
+					"invokedcontracts": {
+						"hash": "0x5f6e8fd3cd8a9e1e0ab3a9f3d1d2b1a48ae9c73e",
+						"call": [
+							{
+								"hash": "0xd2a4cff31913016155e38e474a2c06d08be276cf"
+							}
+						]
+					},
+					"storagechanges": []
+				},
+				"stack": []
+			}
+		}`))),
+	}, nil)
+
+	response := rpcClient.InvokeScriptWithDiagnostics("", nil)
+	assert.False(t, response.HasError())
+	assert.NotNil(t, response.Result.Diagnostics)
+	assert.Equal(t, []string{"0xd2a4cff31913016155e38e474a2c06d08be276cf"}, response.Result.Diagnostics.GetInvokedContracts())
+
+	accounts, err := GetRequiredWitnesses(&response.Result)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(accounts))
+	assert.Equal(t, "2916eba24e652fa006f3e5eb8f9892d2c3b00399", accounts[0].String())
+
+	// without diagnostics every transfer in the script counts
+	response.Result.Diagnostics = nil
+	accounts, err = GetRequiredWitnesses(&response.Result)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(accounts))
+	assert.Equal(t, "edae9b97c72dbec43a7201b6388c24bfd86c71ae", accounts[1].String())
+}
+
+func TestFindVerifiedStates(t *testing.T) {
+	// a trie holding 0x0101: a, 0x0102: b and 0x02: c of contract 1, and 0x01: d of contract 2
+	rootHash := "0x06331d552fd28a83e7d2ce393273920ebe07c0690f1c518e006ad2a02202e324"
+	contractHash := "0x2916eba24e652fa006f3e5eb8f9892d2c3b00399"
+	proofs := map[string]string{
+		"AQE=": "BgEAAAABAQckAQEAAye5vwEpaEFxwbOCAgUjI7rzZduqLaImfTOBUaPhgANsUgAEA3NYbM/ACSDOQpgN0dnjnRFnDN5a0pMYT5obHu+u9RcTA6/4smisFPtid6j6KmK15D84pHDnYgUpHflRgjT+z+GmBAQEBAQEBAQEBAQEBAQqAQcAAAAAAAAAA96oMLxJ39GNyYs0/KQK0NgPSGSA/1qcGSuG5IAWNrqIUgAEAzhqlGIsI+2UbClLNUxNjZteaKv7G8XiLe80RT8r8TntA4YAZj6ENf5zBUBRWj/WgzgrvXNnmvrhkoNvuOFt8H3nBAQEBAQEBAQEBAQEBAQkAQEAA9m2W6Pjen1+JY1dOJOTZ175kvJ45Yd1OXL4VjEX2nziUgAEA3kJ20rz2mMNpbOAE7GMZ97dDrfsVDUU4DRD88v4QNiuA/NiM4+1BqAHgyOCqjmynCNoMB3vk7Ty2gk4TOtczgzxBAQEBAQEBAQEBAQEBAQEAgIBYQ==",
+		"AQI=": "BgEAAAABAgckAQEAAye5vwEpaEFxwbOCAgUjI7rzZduqLaImfTOBUaPhgANsUgAEA3NYbM/ACSDOQpgN0dnjnRFnDN5a0pMYT5obHu+u9RcTA6/4smisFPtid6j6KmK15D84pHDnYgUpHflRgjT+z+GmBAQEBAQEBAQEBAQEBAQqAQcAAAAAAAAAA96oMLxJ39GNyYs0/KQK0NgPSGSA/1qcGSuG5IAWNrqIUgAEAzhqlGIsI+2UbClLNUxNjZteaKv7G8XiLe80RT8r8TntA4YAZj6ENf5zBUBRWj/WgzgrvXNnmvrhkoNvuOFt8H3nBAQEBAQEBAQEBAQEBAQkAQEAA9m2W6Pjen1+JY1dOJOTZ175kvJ45Yd1OXL4VjEX2nziUgAEA3kJ20rz2mMNpbOAE7GMZ97dDrfsVDUU4DRD88v4QNiuA/NiM4+1BqAHgyOCqjmynCNoMB3vk7Ty2gk4TOtczgzxBAQEBAQEBAQEBAQEBAQEAgIBYg==",
+		"Ag==": "BQEAAAACBSQBAQADJ7m/ASloQXHBs4ICBSMjuvNl26otoiZ9M4FRo+GAA2xSAAQDc1hsz8AJIM5CmA3R2eOdEWcM3lrSkxhPmhse7671FxMDr/iyaKwU+2J3qPoqYrXkPzikcOdiBSkd+VGCNP7P4aYEBAQEBAQEBAQEBAQEBCoBBwAAAAAAAAAD3qgwvEnf0Y3JizT8pArQ2A9IZID/WpwZK4bkgBY2uohSAAQDOGqUYiwj7ZRsKUs1TE2Nm15oq/sbxeIt7zRFPyvxOe0DhgBmPoQ1/nMFQFFaP9aDOCu9c2ea+uGSg2+44W3wfecEBAQEBAQEBAQEBAQEBAQCAgFj",
+	}
+	newClient := func() *RpcClientMock {
+		client := new(RpcClientMock)
+		client.On("GetStateRoot", uint32(100)).Return(GetStateRootResponse{Result: mpt.StateRoot{Index: 100, RootHash: rootHash}})
+		client.On("GetContractState", contractHash).Return(GetContractStateResponse{Result: models.RpcContractState{Id: 1, Hash: contractHash}})
+		for key, proof := range proofs {
+			client.On("GetProof", rootHash, contractHash, key).Return(GetProofResponse{Result: proof})
+		}
+		return client
+	}
+
+	// the results come in two pages
+	client := newClient()
+	client.On("FindStates", rootHash, contractHash, "", "", 0).Return(FindStatesResponse{Result: models.RpcFoundStates{
+		Truncated: true,
+		Results:   []models.RpcFoundState{{Key: "AQE=", Value: "YQ=="}, {Key: "AQI=", Value: "Yg=="}},
+	}})
+	client.On("FindStates", rootHash, contractHash, "", "AQI=", 0).Return(FindStatesResponse{Result: models.RpcFoundStates{
+		Results: []models.RpcFoundState{{Key: "Ag==", Value: "Yw=="}},
+	}})
+	states, err := FindVerifiedStates(client, 100, contractHash, []byte{})
+	assert.Nil(t, err)
+	assert.Equal(t, []mpt.KeyValue{
+		{Key: []byte{1, 1}, Value: []byte("a")},
+		{Key: []byte{1, 2}, Value: []byte("b")},
+		{Key: []byte{2}, Value: []byte("c")},
+	}, states)
+
+	// the node leaves b out
+	client = newClient()
+	client.On("FindStates", rootHash, contractHash, "AQ==", "", 0).Return(FindStatesResponse{Result: models.RpcFoundStates{
+		Results: []models.RpcFoundState{{Key: "AQE=", Value: "YQ=="}},
+	}})
+	_, err = FindVerifiedStates(client, 100, contractHash, []byte{1})
+	assert.NotNil(t, err)
+}
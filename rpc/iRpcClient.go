@@ -20,6 +20,7 @@ type IRpcClient interface {
 	GetTransactionHeight(hash string) GetTransactionHeightResponse
 	GetNextBlockValidators() GetNextBlockValidatorsResponse
 	GetCommittee() GetCommitteeResponse
+	GetNativeContracts() GetNativeContractsResponse
 
 	// node
 	GetConnectionCount() GetConnectionCountResponse
@@ -36,10 +37,15 @@ type IRpcClient interface {
 	// smart contract
 	InvokeFunction(scriptHash string, function string, args []models.RpcContractParameter, signers []models.RpcSigner) InvokeResultResponse
 	InvokeScript(script string, signers []models.RpcSigner) InvokeResultResponse
+	InvokeScriptWithDiagnostics(script string, signers []models.RpcSigner) InvokeResultResponse
 	GetUnclaimedGas(address string) GetUnclaimedGasResponse
+	TraverseIterator(session, iterator string, count int) TraverseIteratorResponse
+	TerminateSession(session string) TerminateSessionResponse
 
 	// state
+	FindStates(rootHash, contractScriptHash, prefixInBase64, fromInBase64 string, count int) FindStatesResponse
 	GetProof(rootHash, contractScriptHash, storeKey string) GetProofResponse
+	GetState(rootHash, contractScriptHash, storeKey string) GetStateResponse
 	GetStateHeight() GetStateHeightResponse
 	GetStateRoot(blockHeight uint32) GetStateRootResponse
 	VerifyProof(rootHash string, proofInBase64 string) VerifyProofResponse
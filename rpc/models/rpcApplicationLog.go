@@ -1,5 +1,10 @@
 package models
 
+import (
+	"github.com/joeqian10/neo3-gogogo/sc"
+	"github.com/joeqian10/neo3-gogogo/vm"
+)
+
 type RpcApplicationLog struct {
 	TxId       string         `json:"txid"`
 	BlockHash  string         `json:"blockhash"`
@@ -19,3 +24,30 @@ type RpcNotification struct {
 	EventName string      `json:"eventname"`
 	State     InvokeStack `json:"state"`
 }
+
+// GetStateParameter decodes the state of the notification to a contract parameter, the
+// notification is not changed
+func (n *RpcNotification) GetStateParameter() (*sc.ContractParameter, error) {
+	state := n.State
+	return state.ToParameter()
+}
+
+// GetState returns the vm state, "HALT" or "FAULT"
+func (e *RpcExecution) GetState() string {
+	return e.VMState
+}
+
+// GetTrigger parses the trigger of the execution
+func (e *RpcExecution) GetTrigger() (sc.TriggerType, error) {
+	return sc.NewTriggerTypeFromString(e.Trigger)
+}
+
+// GetVMState parses the vm state of the execution
+func (e *RpcExecution) GetVMState() (vm.VMState, error) {
+	return vm.NewVMStateFromString(e.VMState)
+}
+
+// GetGasConsumed returns the gas consumed in the smallest unit
+func (e *RpcExecution) GetGasConsumed() (int64, error) {
+	return parseGasConsumed(e.GasConsumed)
+}
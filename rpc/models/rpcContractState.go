@@ -1,9 +1,12 @@
 package models
 
 import (
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"github.com/joeqian10/neo3-gogogo/helper"
 	"github.com/joeqian10/neo3-gogogo/sc"
+	"strings"
 )
 
 type RpcContractState struct {
@@ -14,6 +17,13 @@ type RpcContractState struct {
 	Manifest      RpcContractManifest `json:"manifest"`
 }
 
+// NativeContractState is a native contract returned by getnativecontracts, UpdateHistory is only
+// returned by nodes before 3.1
+type NativeContractState struct {
+	RpcContractState
+	UpdateHistory []uint32 `json:"updatehistory,omitempty"`
+}
+
 type RpcNefFile struct {
 	Magic    uint             `json:"magic"`
 	Compiler string           `json:"compiler"`
@@ -75,9 +85,104 @@ func (mp *RpcContractParameterDefinition) ToContractParameterType() (sc.Contract
 	return sc.NewContractParameterTypeFromString(mp.Type)
 }
 
+// WildcardPermission matches any contract or any method in a contract permission
+const WildcardPermission = "*"
+
 type RpcContractPermission struct {
-	Contract string   `json:"contract"`
-	Methods  []string `json:"methods"`
+	Contract string   `json:"contract"` // a contract hash, a group public key or "*"
+	Methods  []string `json:"methods"`  // a list of methods or "*"
+}
+
+// UnmarshalJSON accepts the wildcard "*" as methods, which is decoded as []string{"*"}
+func (p *RpcContractPermission) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Contract string          `json:"contract"`
+		Methods  json.RawMessage `json:"methods"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	p.Contract = raw.Contract
+	p.Methods = nil
+	if len(raw.Methods) == 0 || string(raw.Methods) == "null" {
+		return nil
+	}
+	var wildcard string
+	if err := json.Unmarshal(raw.Methods, &wildcard); err == nil {
+		if wildcard != WildcardPermission {
+			return fmt.Errorf("invalid methods in permission: %s", wildcard)
+		}
+		p.Methods = []string{WildcardPermission}
+		return nil
+	}
+	return json.Unmarshal(raw.Methods, &p.Methods)
+}
+
+// IsAllowed checks whether the permission allows calling the method of the target contract,
+// the contract of the permission matches by wildcard, by hash, or by a group of the target
+func (p *RpcContractPermission) IsAllowed(target *RpcContractState, method string) (bool, error) {
+	if target == nil {
+		return false, fmt.Errorf("ContractState is nil")
+	}
+	matched, err := p.matchContract(target)
+	if err != nil || !matched {
+		return false, err
+	}
+	for _, m := range p.Methods {
+		if m == WildcardPermission || m == method {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (p *RpcContractPermission) matchContract(target *RpcContractState) (bool, error) {
+	contract := strings.TrimPrefix(p.Contract, "0x")
+	switch len(contract) {
+	case 1:
+		if p.Contract == WildcardPermission {
+			return true, nil
+		}
+	case 2 * helper.UINT160SIZE:
+		hash, err := helper.UInt160FromString(contract)
+		if err != nil {
+			break
+		}
+		targetHash, err := helper.UInt160FromString(target.Hash)
+		if err != nil {
+			return false, fmt.Errorf("invalid contract hash: %s", target.Hash)
+		}
+		return hash.Equals(targetHash), nil
+	case 66: // compressed public key of a group
+		if _, err := hex.DecodeString(contract); err != nil {
+			break
+		}
+		for _, group := range target.Manifest.Groups {
+			if strings.EqualFold(group.PubKey, contract) {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+	return false, fmt.Errorf("invalid contract in permission: %s", p.Contract)
+}
+
+// CanCall checks whether the permissions in the manifest of the calling contract allow calling
+// the method of the target contract
+func (m *RpcContractManifest) CanCall(target *RpcContractState, method string) (bool, error) {
+	if m == nil {
+		return false, fmt.Errorf("ContractManifest is nil")
+	}
+	for i := range m.Permissions {
+		allowed, err := m.Permissions[i].IsAllowed(target, method)
+		if err != nil {
+			return false, err
+		}
+		if allowed {
+			return true, nil
+		}
+	}
+	return false, nil
 }
 
 func (cs *RpcContractState) ToContract() (*sc.Contract, error) {
@@ -105,3 +210,27 @@ func (cs *RpcContractState) ToContract() (*sc.Contract, error) {
 		ParameterList: types,
 	}, nil
 }
+
+// VerifyHash checks the hash of the contract equals the one computed from the deployer, the nef
+// checksum and the name in manifest. Native contracts are hashed with a zero deployer and checksum
+func (cs *RpcContractState) VerifyHash(deployer *helper.UInt160) error {
+	if cs == nil {
+		return fmt.Errorf("ContractState is nil")
+	}
+	hash, err := helper.UInt160FromString(cs.Hash)
+	if err != nil {
+		return fmt.Errorf("invalid contract hash: %s", cs.Hash)
+	}
+	checkSum := uint32(cs.Nef.CheckSum)
+	if cs.Id < 0 {
+		deployer, checkSum = helper.UInt160Zero, 0
+	}
+	if deployer == nil {
+		return fmt.Errorf("deployer is nil")
+	}
+	computed := sc.GetContractHash(deployer, checkSum, cs.Manifest.Name)
+	if !hash.Equals(computed) {
+		return fmt.Errorf("contract hash mismatch: %s, computed: 0x%s", cs.Hash, computed.String())
+	}
+	return nil
+}
@@ -1,33 +1,277 @@
 package models
 
 import (
+	"encoding/json"
 	"fmt"
 	"github.com/joeqian10/neo3-gogogo/crypto"
+	"github.com/joeqian10/neo3-gogogo/helper"
 	"github.com/joeqian10/neo3-gogogo/sc"
 	"github.com/joeqian10/neo3-gogogo/vm"
 	"math/big"
 	"strconv"
+	"unicode/utf8"
 )
 
 type InvokeResult struct {
-	Script      string        `json:"script"`
-	State       string        `json:"state"`
-	GasConsumed string        `json:"gasconsumed"`
-	Exception   string        `json:"exception"`
-	Stack       []InvokeStack `json:"stack"`
-	Tx          string        `json:"tx"`
+	Script      string          `json:"script"`
+	State       string          `json:"state"`
+	GasConsumed string          `json:"gasconsumed"`
+	Exception   string          `json:"exception"`
+	Stack       []InvokeStack   `json:"stack"`
+	Tx          string          `json:"tx"`
+	Session     string          `json:"session"`
+	Diagnostics *RpcDiagnostics `json:"diagnostics,omitempty"` // only when invoked with diagnostics
+	StackError  string          `json:"-"`                     // set when the node fails to render the stack
 }
 
+type invokeResultAlias InvokeResult
+
+// UnmarshalJSON accepts a string as the stack, which the node returns instead of the items when the stack
+// cannot be rendered, e.g. "error: invalid operation" for a stack too large or with recursive references
+func (r *InvokeResult) UnmarshalJSON(data []byte) error {
+	aux := struct {
+		*invokeResultAlias
+		Stack json.RawMessage `json:"stack"`
+	}{invokeResultAlias: (*invokeResultAlias)(r)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	r.Stack = nil
+	r.StackError = ""
+	if len(aux.Stack) == 0 || string(aux.Stack) == "null" {
+		return nil
+	}
+	var stackError string
+	if err := json.Unmarshal(aux.Stack, &stackError); err == nil {
+		r.StackError = stackError
+		return nil
+	}
+	return json.Unmarshal(aux.Stack, &r.Stack)
+}
+
+// IsTruncated returns true if the stack is incomplete, either the node failed to render it or an
+// iterator was unwrapped only up to the max items of the node
+func (r *InvokeResult) IsTruncated() bool {
+	if len(r.StackError) != 0 {
+		return true
+	}
+	for i := range r.Stack {
+		if r.Stack[i].Truncated {
+			return true
+		}
+	}
+	return false
+}
+
+// RpcDiagnostics is the contracts invoked and the storage changed during an invocation
+type RpcDiagnostics struct {
+	InvokedContracts RpcInvokedContract `json:"invokedcontracts"`
+	StorageChanges   []RpcStorageChange `json:"storagechanges"`
+}
+
+// RpcInvokedContract is a node of the invocation tree, the root is the script itself
+type RpcInvokedContract struct {
+	Hash string               `json:"hash"`
+	Call []RpcInvokedContract `json:"call"`
+}
+
+type RpcStorageChange struct {
+	State string `json:"state"`
+	Key   string `json:"key"`   // base64
+	Value string `json:"value"` // base64
+}
+
+// GetInvokedContracts returns the hashes of all the contracts invoked, excluding the script itself
+func (d *RpcDiagnostics) GetInvokedContracts() []string {
+	result := make([]string, 0)
+	var walk func(c RpcInvokedContract)
+	walk = func(c RpcInvokedContract) {
+		for _, call := range c.Call {
+			result = append(result, call.Hash)
+			walk(call)
+		}
+	}
+	walk(d.InvokedContracts)
+	return result
+}
+
+// StackItem is a stack item in the results of invoke methods
+type StackItem = InvokeStack
+
 type InvokeStack struct {
-	Type  string      `json:"type"`
-	Value interface{} `json:"value"`
+	Type      string      `json:"type"`
+	Value     interface{} `json:"value"`
+	Interface string      `json:"interface,omitempty"`
+	Id        string      `json:"id,omitempty"`
+	Truncated bool        `json:"truncated,omitempty"` // an iterator unwrapped by a node without sessions has more items
+}
+
+// IExecutionResult reads the vm state and the gas consumed uniformly from the results of
+// invokefunction, invokescript and the executions in an application log
+type IExecutionResult interface {
+	GetState() string
+	GetGasConsumed() (int64, error)
+}
+
+// GetState returns the vm state, "HALT" or "FAULT"
+func (r *InvokeResult) GetState() string {
+	return r.State
+}
+
+// GetGasConsumed returns the gas consumed in the smallest unit
+func (r *InvokeResult) GetGasConsumed() (int64, error) {
+	return parseGasConsumed(r.GasConsumed)
+}
+
+func parseGasConsumed(s string) (int64, error) {
+	gas, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid gasconsumed: %s", s)
+	}
+	return gas, nil
+}
+
+// IsIterator returns true if the first stack item is an iterator returned by reference
+func (r *InvokeResult) IsIterator() bool {
+	if len(r.Stack) == 0 {
+		return false
+	}
+	return r.Stack[0].IsIterator()
+}
+
+// GetStackParameters converts the stack items into contract parameters by InvokeStack.ToParameter, so
+// an Integer is read as a *big.Int and a ByteString as the decoded bytes, with arrays, structs and maps
+// converted recursively
+func (r *InvokeResult) GetStackParameters() ([]sc.ContractParameter, error) {
+	if len(r.StackError) != 0 {
+		return nil, fmt.Errorf("stack is not returned: %s", r.StackError)
+	}
+	parameters := make([]sc.ContractParameter, len(r.Stack))
+	for i := range r.Stack {
+		s := r.Stack[i]
+		p, err := s.ToParameter()
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert stack item %d: %v", i, err)
+		}
+		parameters[i] = *p
+	}
+	return parameters, nil
+}
+
+// GetIteratorIds returns the ids of the iterators on the stack, which are traversed in the session by
+// traverseiterator
+func (r *InvokeResult) GetIteratorIds() []string {
+	ids := []string{}
+	for i := range r.Stack {
+		if r.Stack[i].IsIterator() {
+			ids = append(ids, r.Stack[i].Id)
+		}
+	}
+	return ids
+}
+
+// Items returns the items of the first stack item, either read from the inline array,
+// or traversed from the iterator in the session using the traverse func
+func (r *InvokeResult) Items(traverse func(sessionId string, iteratorId string) ([]InvokeStack, error)) ([]InvokeStack, error) {
+	if len(r.Stack) == 0 {
+		return nil, fmt.Errorf("no stack result returned")
+	}
+	s := r.Stack[0]
+	if s.IsIterator() {
+		if len(r.Session) == 0 {
+			return nil, fmt.Errorf("iterator returned without session")
+		}
+		if traverse == nil {
+			return nil, fmt.Errorf("iterator returned but no traverse func provided")
+		}
+		return traverse(r.Session, s.Id)
+	}
+	if s.Type != vm.Array.String() && s.Type != vm.Struct.String() {
+		return nil, fmt.Errorf("stack item type %s is neither an array nor an iterator", s.Type)
+	}
+	s.Convert()
+	items, ok := s.Value.([]InvokeStack)
+	if !ok {
+		return nil, fmt.Errorf("invalid array value")
+	}
+	return items, nil
+}
+
+// Validate checks the nesting depth, the total item count and the item sizes of the stack
+// against the limits, and returns an error if the result would not fit in the VM when decoded
+func (r *InvokeResult) Validate(limits vm.ExecutionEngineLimits) error {
+	count := 0
+	for i := range r.Stack {
+		s := r.Stack[i] // copy, Convert should not change the result
+		if err := s.validate(limits, 1, &count); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *InvokeStack) validate(limits vm.ExecutionEngineLimits, depth int, count *int) error {
+	if depth > limits.MaxNestingDepth {
+		return fmt.Errorf("nesting depth exceeds the limit %d", limits.MaxNestingDepth)
+	}
+	*count++
+	if *count > limits.MaxStackSize {
+		return fmt.Errorf("item count exceeds the max stack size %d", limits.MaxStackSize)
+	}
+	s.Convert()
+	switch s.Type {
+	case vm.Array.String(), vm.Struct.String():
+		items, ok := s.Value.([]InvokeStack)
+		if !ok {
+			return fmt.Errorf("invalid %s value", s.Type)
+		}
+		for i := range items {
+			if err := items[i].validate(limits, depth+1, count); err != nil {
+				return err
+			}
+		}
+	case vm.Map.String():
+		m, ok := s.Value.(map[InvokeStack]InvokeStack)
+		if !ok {
+			return fmt.Errorf("invalid %s value", s.Type)
+		}
+		for k, v := range m {
+			if err := k.validate(limits, depth+1, count); err != nil {
+				return err
+			}
+			if err := v.validate(limits, depth+1, count); err != nil {
+				return err
+			}
+		}
+	case vm.Buffer.String(), vm.ByteString.String():
+		str, ok := s.Value.(string)
+		if !ok {
+			return fmt.Errorf("invalid %s value", s.Type)
+		}
+		b, err := crypto.Base64Decode(str)
+		if err != nil {
+			return err
+		}
+		if len(b) > limits.MaxItemSize {
+			return fmt.Errorf("item size exceeds the max item size %d", limits.MaxItemSize)
+		}
+	}
+	return nil
+}
+
+// IsIterator returns true if the stack item is an InteropInterface holding an iterator
+func (s *InvokeStack) IsIterator() bool {
+	return s.Type == vm.InteropInterface.String() && s.Interface == "IIterator"
 }
 
 // Convert converts interface{} "Value" to string or []InvokeStack or map[InvokeStack]InvokeStack depending on the "Type"
 func (s *InvokeStack) Convert() {
 	switch s.Type {
-	case vm.Array.String():
-		vs := s.Value.([]interface{})
+	case vm.Array.String(), vm.Struct.String():
+		vs, ok := s.Value.([]interface{})
+		if !ok {
+			break // already converted
+		}
 		result := make([]InvokeStack, len(vs))
 		for i, v := range vs {
 			m := v.(map[string]interface{})
@@ -55,7 +299,10 @@ func (s *InvokeStack) Convert() {
 		// else if number in string, nothing to handle
 		break
 	case vm.Map.String():
-		vs := s.Value.([]interface{})
+		vs, ok := s.Value.([]interface{})
+		if !ok {
+			break // already converted
+		}
 		result := make(map[InvokeStack]InvokeStack)
 		for _, v := range vs {
 			m := v.(map[string]interface{})
@@ -76,23 +323,155 @@ func (s *InvokeStack) Convert() {
 		s.Value = result
 		break
 	case vm.Pointer.String():
-		if num, ok := s.Value.(int); ok {
+		// the position is a json number
+		switch num := s.Value.(type) {
+		case int:
 			s.Value = strconv.Itoa(num)
+		case float64:
+			s.Value = strconv.FormatInt(int64(num), 10)
 		}
 		break
 	}
 }
 
+// GetBigInteger returns the value of an Integer stack item, the node renders integers as decimal
+// strings since they can exceed int64, so they are parsed with big.Int without losing precision
+func (s *InvokeStack) GetBigInteger() (*big.Int, error) {
+	if s.Type != vm.Integer.String() {
+		return nil, fmt.Errorf("unexpected stack item type: %s", s.Type)
+	}
+	switch v := s.Value.(type) {
+	case string:
+		i, ok := new(big.Int).SetString(v, 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid integer value: %s", v)
+		}
+		return i, nil
+	case int:
+		return big.NewInt(int64(v)), nil
+	case int64:
+		return big.NewInt(v), nil
+	case *big.Int:
+		if v == nil {
+			return nil, fmt.Errorf("invalid integer value")
+		}
+		return new(big.Int).Set(v), nil
+	default:
+		return nil, fmt.Errorf("invalid integer value: %v", s.Value)
+	}
+}
+
+// ByteStringEncoding is how ToGo renders ByteString and Buffer items
+type ByteStringEncoding byte
+
+const (
+	Base64Encoding ByteStringEncoding = iota // as in the rpc result
+	HexEncoding
+	UTF8Encoding // falls back to hex if the bytes are not valid utf8
+)
+
+// maxSafeInteger is the largest integer float64 holds exactly
+var maxSafeInteger = big.NewInt(1 << 53)
+
+// ToGo converts the stack item to plain Go values for generic serialization:
+// Any and InteropInterface to nil, Boolean to bool, Integer to float64 (or a decimal string if
+// it cannot be held exactly), ByteString and Buffer to string in the encoding, Array and Struct to
+// []interface{}, and Map to map[string]interface{} keyed by the rendered keys
+func (s *InvokeStack) ToGo(encoding ByteStringEncoding) (interface{}, error) {
+	item := *s // copy, Convert should not change s
+	item.Convert()
+	switch item.Type {
+	case vm.Any.String(), vm.InteropInterface.String():
+		return nil, nil
+	case vm.Boolean.String():
+		v, ok := item.Value.(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid %s value", item.Type)
+		}
+		return strconv.ParseBool(v)
+	case vm.Integer.String(), vm.Pointer.String():
+		item.Type = vm.Integer.String() // a pointer is decoded as an integer
+		i, err := item.GetBigInteger()
+		if err != nil {
+			return nil, err
+		}
+		if new(big.Int).Abs(i).Cmp(maxSafeInteger) > 0 {
+			return i.String(), nil
+		}
+		return float64(i.Int64()), nil
+	case vm.ByteString.String(), vm.Buffer.String():
+		v, ok := item.Value.(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid %s value", item.Type)
+		}
+		if encoding == Base64Encoding {
+			return v, nil
+		}
+		b, err := crypto.Base64Decode(v)
+		if err != nil {
+			return nil, err
+		}
+		if encoding == UTF8Encoding && utf8.Valid(b) {
+			return string(b), nil
+		}
+		return helper.BytesToHex(b), nil
+	case vm.Array.String(), vm.Struct.String():
+		items, ok := item.Value.([]InvokeStack)
+		if !ok {
+			return nil, fmt.Errorf("invalid %s value", item.Type)
+		}
+		result := make([]interface{}, len(items))
+		for i := range items {
+			v, err := items[i].ToGo(encoding)
+			if err != nil {
+				return nil, err
+			}
+			result[i] = v
+		}
+		return result, nil
+	case vm.Map.String():
+		m, ok := item.Value.(map[InvokeStack]InvokeStack)
+		if !ok {
+			return nil, fmt.Errorf("invalid %s value", item.Type)
+		}
+		result := make(map[string]interface{}, len(m))
+		for k, v := range m {
+			key, err := k.ToGo(encoding)
+			if err != nil {
+				return nil, err
+			}
+			value, err := v.ToGo(encoding)
+			if err != nil {
+				return nil, err
+			}
+			if f, ok := key.(float64); ok {
+				key = strconv.FormatFloat(f, 'f', -1, 64)
+			}
+			result[fmt.Sprint(key)] = value
+		}
+		return result, nil
+	default:
+		return nil, fmt.Errorf("not supported stack item type: %s", item.Type)
+	}
+}
+
+// ToParameter decodes the stack item tree to a contract parameter, Array and Struct are decoded to
+// Array with []sc.ContractParameter, Map to map[interface{}]interface{} with *sc.ContractParameter keys
+// and sc.ContractParameter values, the same as sc.ContractParameter.UnmarshalJSON
 func (s *InvokeStack) ToParameter() (*sc.ContractParameter, error) {
 	var parameter *sc.ContractParameter = new(sc.ContractParameter)
 	var err error
 	s.Convert()
 	switch s.Type {
-	case vm.Array.String():
+	case vm.Any.String():
+		parameter.Type = sc.Any
+	case vm.InteropInterface.String():
+		parameter.Type = sc.InteropInterface
+	case vm.Array.String(), vm.Struct.String():
 		parameter.Type = sc.Array
 		a := s.Value.([]InvokeStack)
 		r := make([]sc.ContractParameter, len(a))
-		for i, _ := range a {
+		for i := range a {
 			t, err1 := a[i].ToParameter()
 			if err1 != nil {
 				err = err1
@@ -100,6 +479,7 @@ func (s *InvokeStack) ToParameter() (*sc.ContractParameter, error) {
 			}
 			r[i] = *t
 		}
+		parameter.Value = r
 		break
 	case vm.Boolean.String():
 		parameter.Type = sc.Boolean
@@ -111,15 +491,25 @@ func (s *InvokeStack) ToParameter() (*sc.ContractParameter, error) {
 		break
 	case vm.Integer.String():
 		parameter.Type = sc.Integer
-		var b bool
-		parameter.Value, b = new(big.Int).SetString(s.Value.(string), 10)
-		if !b {
-			err = fmt.Errorf("converting vm.Integer to sc.Integer failed")
-		}
+		parameter.Value, err = s.GetBigInteger()
 		break
 	case vm.Map.String():
 		parameter.Type = sc.Map
-		parameter.Value = s.Value // map[InvokeStack]InvokeStack
+		r := make(map[interface{}]interface{})
+		for k, v := range s.Value.(map[InvokeStack]InvokeStack) {
+			key, err1 := k.ToParameter()
+			if err1 != nil {
+				err = err1
+				break
+			}
+			value, err1 := v.ToParameter()
+			if err1 != nil {
+				err = err1
+				break
+			}
+			r[key] = *value
+		}
+		parameter.Value = r
 	case vm.Pointer.String():
 		parameter.Type = sc.Integer
 		var b bool
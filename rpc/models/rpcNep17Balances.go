@@ -1,12 +1,32 @@
 package models
 
+import (
+	"fmt"
+	"math/big"
+)
+
 type RpcNep17Balances struct {
 	Balances []RpcNep17Balance `json:"balance"`
 	Address  string            `json:"address"`
 }
 
+// RpcNep17Balance is one token balance, Name, Symbol and Decimals are only returned by
+// the TokensTracker plugin, Decimals is a stringified integer. Amount is a stringified integer
+// too, since it may exceed uint64, use GetAmount to parse it
 type RpcNep17Balance struct {
 	AssetHash        string `json:"assethash"`
-	Amount           uint64 `json:"amount"`
+	Name             string `json:"name,omitempty"`
+	Symbol           string `json:"symbol,omitempty"`
+	Decimals         string `json:"decimals,omitempty"`
+	Amount           string `json:"amount"`
 	LastUpdatedBlock int    `json:"lastupdatedblock"`
 }
+
+// GetAmount parses Amount, the balance in the smallest unit of the token
+func (b *RpcNep17Balance) GetAmount() (*big.Int, error) {
+	amount, ok := new(big.Int).SetString(b.Amount, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid amount: %s", b.Amount)
+	}
+	return amount, nil
+}
@@ -1,5 +1,10 @@
 package models
 
+import (
+	"fmt"
+	"math/big"
+)
+
 type RpcNep17Transfers struct {
 	Sent     []RpcNep17Transfer `json:"sent"`
 	Received []RpcNep17Transfer `json:"received"`
@@ -15,3 +20,33 @@ type RpcNep17Transfer struct {
 	TransferNotifyIndex int    `json:"transfernotifyindex"`
 	TxHash              string `json:"txhash"`
 }
+
+// RpcNep17TransferEntry is a sent or received transfer of the address
+type RpcNep17TransferEntry struct {
+	RpcNep17Transfer
+	Sent bool
+}
+
+// SumNep17Transfers sums the transfers in blocks after fromHeight up to and including toHeight,
+// received amounts counting positive and sent amounts negative. The net changes are keyed by asset hash
+func SumNep17Transfers(entries []RpcNep17TransferEntry, fromHeight int, toHeight int) (map[string]*big.Int, error) {
+	result := make(map[string]*big.Int)
+	for _, e := range entries {
+		if e.BlockIndex <= fromHeight || e.BlockIndex > toHeight {
+			continue
+		}
+		amount, ok := new(big.Int).SetString(e.Amount, 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid amount %s in transaction %s", e.Amount, e.TxHash)
+		}
+		if e.Sent {
+			amount.Neg(amount)
+		}
+		if delta, ok := result[e.AssetHash]; ok {
+			delta.Add(delta, amount)
+		} else {
+			result[e.AssetHash] = amount
+		}
+	}
+	return result, nil
+}
@@ -1,12 +1,56 @@
 package models
 
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"strconv"
+)
+
 type RpcPeers struct {
 	Unconnected []Peer `json:"unconnected"`
 	Bad         []Peer `json:"bad"`
 	Connected   []Peer `json:"connected"`
 }
 
+// Count returns the number of all peers known by the node
+func (p *RpcPeers) Count() int {
+	return len(p.Unconnected) + len(p.Bad) + len(p.Connected)
+}
+
 type Peer struct {
 	Address string `json:"address"`
 	Port    int    `json:"port"`
 }
+
+// Endpoint returns the peer in "host:port" form
+func (p *Peer) Endpoint() string {
+	return net.JoinHostPort(p.Address, strconv.Itoa(p.Port))
+}
+
+// UnmarshalJSON accepts the port either as a number or as a string
+func (p *Peer) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Address string          `json:"address"`
+		Port    json.RawMessage `json:"port"`
+	}
+	err := json.Unmarshal(data, &raw)
+	if err != nil {
+		return err
+	}
+	p.Address = raw.Address
+	p.Port = 0
+	if len(raw.Port) == 0 || string(raw.Port) == "null" {
+		return nil
+	}
+	var s string
+	if json.Unmarshal(raw.Port, &s) == nil {
+		p.Port, err = strconv.Atoi(s)
+	} else {
+		err = json.Unmarshal(raw.Port, &p.Port)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid peer port: %s", string(raw.Port))
+	}
+	return nil
+}
@@ -4,3 +4,15 @@ type RpcStateHeight struct {
 	LocalRootIndex uint32 `json:"localrootindex"`
 	ValidateRootIndex uint32 `json:"validatedrootindex"`
 }
+
+type RpcFoundStates struct {
+	FirstProof string          `json:"firstProof"`
+	LastProof  string          `json:"lastProof"`
+	Truncated  bool            `json:"truncated"`
+	Results    []RpcFoundState `json:"results"`
+}
+
+type RpcFoundState struct {
+	Key   string `json:"key"`   // base64
+	Value string `json:"value"` // base64
+}
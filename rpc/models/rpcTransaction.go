@@ -1,6 +1,9 @@
 package models
 
 import (
+	"fmt"
+
+	"github.com/joeqian10/neo3-gogogo/crypto"
 	"github.com/joeqian10/neo3-gogogo/tx"
 )
 
@@ -11,9 +14,9 @@ type RpcTransaction struct {
 	Nonce   int    `json:"nonce"`
 	// address
 	Sender          string                    `json:"sender"`
-	SysFee          string                    `json:"sys_fee"`
-	NetFee          string                    `json:"net_fee"`
-	ValidUntilBlock int                       `json:"valid_until_block"`
+	SysFee          string                    `json:"sysfee"`
+	NetFee          string                    `json:"netfee"`
+	ValidUntilBlock int                       `json:"validuntilblock"`
 	Attributes      []RpcTransactionAttribute `json:"attributes"`
 	Signers         []RpcSigner               `json:"signers"`
 	Script          string                    `json:"script"`
@@ -25,8 +28,55 @@ type RpcTransaction struct {
 }
 
 type RpcTransactionAttribute struct {
-	Usage string `json:"usage"`
-	Data  string `json:"data"`
+	Type string `json:"type"`
+	// OracleResponse
+	Id     uint64 `json:"id,omitempty"`
+	Code   string `json:"code,omitempty"`
+	Result string `json:"result,omitempty"`
+}
+
+// ToAttribute converts the json attribute to the concrete attribute type by its "type"
+func (a *RpcTransactionAttribute) ToAttribute() (tx.ITransactionAttribute, error) {
+	t, err := tx.NewTransactionAttributeTypeFromString(a.Type)
+	if err != nil {
+		return nil, err
+	}
+	switch t {
+	case tx.HighPriority:
+		return &tx.HighPriorityAttribute{}, nil
+	case tx.OracleResponse:
+		code, err := tx.NewOracleResponseCodeFromString(a.Code)
+		if err != nil {
+			return nil, err
+		}
+		result, err := crypto.Base64Decode(a.Result)
+		if err != nil {
+			return nil, err
+		}
+		attribute, err := tx.NewOracleResponseAttribute()
+		if err != nil {
+			return nil, err
+		}
+		attribute.Id = a.Id
+		attribute.Code = code
+		attribute.Result = result
+		return attribute, nil
+	default:
+		return nil, fmt.Errorf("not supported transaction attribute type: %s", a.Type)
+	}
+}
+
+// GetAttributes converts all attributes of the transaction to their concrete types
+func (t *RpcTransaction) GetAttributes() ([]tx.ITransactionAttribute, error) {
+	result := make([]tx.ITransactionAttribute, len(t.Attributes))
+	for i := range t.Attributes {
+		a, err := t.Attributes[i].ToAttribute()
+		if err != nil {
+			return nil, err
+		}
+		result[i] = a
+	}
+	return result, nil
 }
 
 type RpcSigner struct {
@@ -1,8 +1,54 @@
 package models
 
+import (
+	"time"
+
+	"github.com/joeqian10/neo3-gogogo/helper"
+)
+
 type RpcVersion struct {
-	TcpPort   int    `json:"tcpPort"`
-	WsPort    int    `json:"wsPort"`
-	Nonce     string `json:"nonce"`
-	UserAgent string `json:"useragent"`
+	TcpPort   int         `json:"tcpPort"`
+	WsPort    int         `json:"wsPort"`
+	Nonce     uint32      `json:"nonce"`
+	UserAgent string      `json:"useragent"`
+	Protocol  RpcProtocol `json:"protocol"`
+}
+
+type RpcProtocol struct {
+	AddressVersion              byte   `json:"addressversion"`
+	Network                     uint32 `json:"network"`
+	ValidatorsCount             int    `json:"validatorscount"`
+	MsPerBlock                  uint32 `json:"msperblock"`
+	MaxTraceableBlocks          uint32 `json:"maxtraceableblocks"`
+	MaxValidUntilBlockIncrement uint32 `json:"maxvaliduntilblockincrement"`
+	MaxTransactionsPerBlock     uint32 `json:"maxtransactionsperblock"`
+	MemoryPoolMaxTransactions   int    `json:"memorypoolmaxtransactions"`
+	InitialGasDistribution      uint64 `json:"initialgasdistribution"`
+}
+
+// BlockTime returns the time between blocks, a transaction sent now is likely to be
+// confirmed in the next block within this time
+func (p *RpcProtocol) BlockTime() time.Duration {
+	return time.Duration(p.MsPerBlock) * time.Millisecond
+}
+
+// EstimateExpiry returns the estimated time until a transaction with validUntilBlock expires,
+// currentHeight is the index of the latest block, which is getblockcount - 1
+func (p *RpcProtocol) EstimateExpiry(currentHeight uint32, validUntilBlock uint32) time.Duration {
+	if validUntilBlock <= currentHeight {
+		return 0
+	}
+	return time.Duration(validUntilBlock-currentHeight) * p.BlockTime()
+}
+
+// NetworkName returns "MainNet" or "TestNet" for the networks with known magics, otherwise "Custom"
+func (p *RpcProtocol) NetworkName() string {
+	switch p.Network {
+	case helper.N3Magic_MainNet:
+		return "MainNet"
+	case helper.N3Magic_TestNet:
+		return "TestNet"
+	default:
+		return "Custom"
+	}
 }
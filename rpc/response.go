@@ -17,6 +17,11 @@ func (r *ErrorResponse) HasError() bool {
 	return true
 }
 
+// setNetError records the error of sending the request or decoding the response
+func (r *ErrorResponse) setNetError(err error) {
+	r.NetError = err
+}
+
 func (r *ErrorResponse) GetErrorInfo() string {
 	if r.NetError != nil {
 		return r.NetError.Error()
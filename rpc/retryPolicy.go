@@ -0,0 +1,84 @@
+package rpc
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// RetryPolicy configures how a request is retried on transport errors and HTTP 429 or 5xx responses.
+// The delay before the nth retry is BaseDelay * 2^(n-1), capped by MaxDelay, and reduced by a random
+// fraction of at most Jitter, so clients failing together do not retry together
+type RetryPolicy struct {
+	MaxAttempts int           // the total count of attempts, 1 or less disables retrying
+	BaseDelay   time.Duration // the delay before the first retry
+	MaxDelay    time.Duration // the limit of the delay, 0 for no limit
+	Jitter      float64       // between 0 and 1
+}
+
+// DefaultRetryPolicy tries a request 3 times, with delays around 200ms and 400ms
+var DefaultRetryPolicy = RetryPolicy{MaxAttempts: 3, BaseDelay: 200 * time.Millisecond, MaxDelay: 5 * time.Second, Jitter: 0.2}
+
+// nonIdempotentMethods are never retried, since the node may have handled the failed attempt
+var nonIdempotentMethods = map[string]bool{
+	"sendrawtransaction": true,
+	"submitblock":        true,
+	"sendfrom":           true,
+	"sendmany":           true,
+	"sendtoaddress":      true,
+	"getnewaddress":      true,
+	"importprivkey":      true,
+	"openwallet":         true,
+	"closewallet":        true,
+	"traverseiterator":   true,
+}
+
+// WithRetryPolicy sets the retry policy of the client and returns the client. Only idempotent methods are
+// retried, requests sending transactions, changing the wallet or moving an iterator are not. The context of a
+// request still bounds the total time of all attempts
+func (n *RpcClient) WithRetryPolicy(p RetryPolicy) *RpcClient {
+	n.retryPolicy = &p
+	return n
+}
+
+// attempts returns the count of attempts for the method
+func (p *RetryPolicy) attempts(method string) int {
+	if p == nil || p.MaxAttempts < 1 || nonIdempotentMethods[method] {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+// delay returns the delay before the retry after the attempt
+func (p *RetryPolicy) delay(attempt int) time.Duration {
+	d := p.BaseDelay
+	// stop doubling before it overflows when there is no limit
+	for i := 1; i < attempt && (p.MaxDelay <= 0 || d < p.MaxDelay) && d <= math.MaxInt64/2; i++ {
+		d *= 2
+	}
+	if p.MaxDelay > 0 && d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	if p.Jitter > 0 {
+		d -= time.Duration(float64(d) * p.Jitter * rand.Float64())
+	}
+	return d
+}
+
+// wait sleeps before the retry after the attempt, it returns the error of the context if it is done first
+func (p *RetryPolicy) wait(ctx context.Context, attempt int) error {
+	timer := time.NewTimer(p.delay(attempt))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func isRetryableStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code >= 500
+}
@@ -0,0 +1,101 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newFlakyServer returns a server failing the first failures requests with 503, and the count of requests
+func newFlakyServer(failures int32, result string) (*httptest.Server, *int32) {
+	var count int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&count, 1) <= failures {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":` + result + `}`))
+	}))
+	return server, &count
+}
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	p := RetryPolicy{MaxAttempts: 5, BaseDelay: 100 * time.Millisecond, MaxDelay: 300 * time.Millisecond}
+	assert.Equal(t, 100*time.Millisecond, p.delay(1))
+	assert.Equal(t, 200*time.Millisecond, p.delay(2))
+	assert.Equal(t, 300*time.Millisecond, p.delay(3))
+	assert.Equal(t, 300*time.Millisecond, p.delay(10))
+
+	// without limit the delay does not overflow
+	p = RetryPolicy{MaxAttempts: 100, BaseDelay: 100 * time.Millisecond}
+	assert.Equal(t, 400*time.Millisecond, p.delay(3))
+	assert.True(t, p.delay(100) > p.delay(30))
+
+	p.Jitter = 0.5
+	for i := 0; i < 10; i++ {
+		d := p.delay(2)
+		assert.True(t, d > 100*time.Millisecond && d <= 200*time.Millisecond)
+	}
+}
+
+func TestRpcClient_WithRetryPolicy(t *testing.T) {
+	server, count := newFlakyServer(2, "2023")
+	defer server.Close()
+
+	client := NewRpcClient(server.URL).WithRetryPolicy(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond})
+	response := client.GetBlockCount()
+	assert.False(t, response.HasError())
+	assert.Equal(t, 2023, response.Result)
+	assert.Equal(t, int32(3), atomic.LoadInt32(count))
+}
+
+func TestRpcClient_WithRetryPolicy_Exhausted(t *testing.T) {
+	server, count := newFlakyServer(5, "2023")
+	defer server.Close()
+
+	client := NewRpcClient(server.URL).WithRetryPolicy(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond})
+	response := client.GetBlockCount()
+	assert.True(t, response.HasError())
+	assert.Equal(t, int32(3), atomic.LoadInt32(count))
+}
+
+func TestRpcClient_WithRetryPolicy_SendRawTransaction(t *testing.T) {
+	server, count := newFlakyServer(1, `{"hash":"0x13ccdb9f7eda95a24aa5a4841b24fed957fe7f1b944996cbc2e92a4fa4f1fa73"}`)
+	defer server.Close()
+
+	client := NewRpcClient(server.URL).WithRetryPolicy(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond})
+	response := client.SendRawTransaction("AA==")
+	assert.True(t, response.HasError())
+	assert.Equal(t, int32(1), atomic.LoadInt32(count))
+}
+
+func TestRpcClient_WithRetryPolicy_TraverseIterator(t *testing.T) {
+	server, count := newFlakyServer(1, `[]`)
+	defer server.Close()
+
+	client := NewRpcClient(server.URL).WithRetryPolicy(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond})
+	response := client.TraverseIterator("c5b628b6-10d9-4cc5-b850-3cfc0b659fcf", "593b02c6-138d-4945-846d-71e2b1b0ee32", 10)
+	assert.True(t, response.HasError())
+	assert.Equal(t, int32(1), atomic.LoadInt32(count))
+}
+
+func TestRpcClient_WithRetryPolicy_Deadline(t *testing.T) {
+	server, count := newFlakyServer(100, "2023")
+	defer server.Close()
+
+	client := NewRpcClient(server.URL).WithRetryPolicy(RetryPolicy{MaxAttempts: 100, BaseDelay: 20 * time.Millisecond})
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	response := client.GetBlockCountWithContext(ctx)
+	assert.True(t, response.HasError())
+	assert.True(t, time.Since(start) < time.Second)
+	assert.True(t, atomic.LoadInt32(count) < 100)
+	assert.True(t, errors.Is(response.NetError, context.DeadlineExceeded))
+}
@@ -0,0 +1,115 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// BatchInvoke sends the requests in one JSON-RPC batch and returns the raw responses in the order of
+// the requests, the responses are matched by id, so the ids of the requests must be unique. A response
+// may carry an error object when only that request fails
+func (n *RpcClient) BatchInvoke(requests []RpcRequest) ([]json.RawMessage, error) {
+	return n.BatchInvokeWithContext(context.Background(), requests)
+}
+
+// BatchInvokeWithContext is BatchInvoke with the context of the request
+func (n *RpcClient) BatchInvokeWithContext(ctx context.Context, requests []RpcRequest) ([]json.RawMessage, error) {
+	if len(requests) == 0 {
+		return []json.RawMessage{}, nil
+	}
+	indexes := make(map[int]int, len(requests))
+	for i, r := range requests {
+		if _, ok := indexes[r.ID]; ok {
+			return nil, fmt.Errorf("duplicate request id: %d", r.ID)
+		}
+		indexes[r.ID] = i
+	}
+	var responses []json.RawMessage
+	err := n.makeBatchRequest(ctx, requests, &responses)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]json.RawMessage, len(requests))
+	for _, raw := range responses {
+		r := RpcResponse{}
+		if err = json.Unmarshal(raw, &r); err != nil {
+			return nil, err
+		}
+		i, ok := indexes[r.ID]
+		if !ok {
+			return nil, fmt.Errorf("unexpected response id: %d", r.ID)
+		}
+		result[i] = raw
+	}
+	for i := range result {
+		if result[i] == nil {
+			return nil, fmt.Errorf("no response for request id: %d", requests[i].ID)
+		}
+	}
+	return result, nil
+}
+
+// Batch collects calls to send in one JSON-RPC batch, the typed responses returned when adding
+// the calls are filled by Execute
+type Batch struct {
+	client   *RpcClient
+	requests []RpcRequest
+	outs     []interface{}
+}
+
+// NewBatch creates an empty batch of the client
+func (n *RpcClient) NewBatch() *Batch {
+	return &Batch{client: n}
+}
+
+// Add adds a call, the response is decoded into out when the batch is executed
+func (b *Batch) Add(method string, params []interface{}, out interface{}) *Batch {
+	request := NewRequest(method, params)
+	request.ID = len(b.requests) + 1
+	b.requests = append(b.requests, request)
+	b.outs = append(b.outs, out)
+	return b
+}
+
+// Len returns the count of calls in the batch
+func (b *Batch) Len() int {
+	return len(b.requests)
+}
+
+// GetBlock adds a getblock call like RpcClient.GetBlock
+func (b *Batch) GetBlock(hashOrIndex string) *GetBlockResponse {
+	response := &GetBlockResponse{}
+	b.Add("getblock", getBlockParams(hashOrIndex), response)
+	return response
+}
+
+// GetNep17Balances adds a getnep17balances call like RpcClient.GetNep17Balances
+func (b *Batch) GetNep17Balances(address string) *GetNep17BalancesResponse {
+	response := &GetNep17BalancesResponse{}
+	b.Add("getnep17balances", []interface{}{address}, response)
+	return response
+}
+
+// Execute sends all calls and fills the responses. A call failing on the node only sets the error of its
+// response, the returned error is for the batch as a whole, and is also set to the NetError of every response
+func (b *Batch) Execute() error {
+	return b.ExecuteWithContext(context.Background())
+}
+
+// ExecuteWithContext is Execute with the context of the request
+func (b *Batch) ExecuteWithContext(ctx context.Context) error {
+	responses, err := b.client.BatchInvokeWithContext(ctx, b.requests)
+	if err != nil {
+		for _, out := range b.outs {
+			setNetError(out, err)
+		}
+		return err
+	}
+	for i, raw := range responses {
+		if err := json.Unmarshal(raw, b.outs[i]); err != nil {
+			setNetError(b.outs[i], fmt.Errorf("failed to decode %s response: %v", b.requests[i].Method, err))
+		}
+	}
+	return nil
+}
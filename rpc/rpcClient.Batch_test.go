@@ -0,0 +1,94 @@
+package rpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newBatchServer creates a server answering a batch in reverse order, getblock returns a block with
+// the requested index, an index above 1000 gets an error object
+func newBatchServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var requests []RpcRequest
+		assert.Nil(t, json.NewDecoder(r.Body).Decode(&requests))
+		responses := make([]string, 0, len(requests))
+		for i := len(requests) - 1; i >= 0; i-- {
+			request := requests[i]
+			switch {
+			case request.Method == "getblock" && request.Params[0].(float64) > 1000:
+				responses = append(responses, fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"error":{"code":-100,"message":"Unknown block"}}`, request.ID))
+			case request.Method == "getblock":
+				responses = append(responses, fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"result":{"index":%v}}`, request.ID, request.Params[0]))
+			case request.Method == "getnep17balances":
+				responses = append(responses, fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"result":{"balance":[],"address":"%v"}}`, request.ID, request.Params[0]))
+			}
+		}
+		_, _ = w.Write([]byte("["))
+		for i, response := range responses {
+			if i > 0 {
+				_, _ = w.Write([]byte(","))
+			}
+			_, _ = w.Write([]byte(response))
+		}
+		_, _ = w.Write([]byte("]"))
+	}))
+}
+
+func TestRpcClient_BatchInvoke(t *testing.T) {
+	server := newBatchServer(t)
+	defer server.Close()
+	client := NewRpcClient(server.URL)
+
+	requests := []RpcRequest{NewRequest("getblock", []interface{}{1, true}), NewRequest("getblock", []interface{}{2000, true})}
+	requests[0].ID, requests[1].ID = 7, 3
+	responses, err := client.BatchInvoke(requests)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(responses))
+	first := GetBlockResponse{}
+	assert.Nil(t, json.Unmarshal(responses[0], &first))
+	assert.Equal(t, 7, first.ID)
+	assert.Equal(t, 1, first.Result.Index)
+	second := GetBlockResponse{}
+	assert.Nil(t, json.Unmarshal(responses[1], &second))
+	assert.True(t, second.HasError())
+	assert.Equal(t, "Unknown block", second.GetErrorInfo())
+
+	requests[1].ID = 7
+	_, err = client.BatchInvoke(requests)
+	assert.NotNil(t, err)
+}
+
+func TestBatch_Execute(t *testing.T) {
+	server := newBatchServer(t)
+	defer server.Close()
+	client := NewRpcClient(server.URL)
+
+	batch := client.NewBatch()
+	blocks := make([]*GetBlockResponse, 0)
+	for _, index := range []string{"10", "11", "5000", "12"} {
+		blocks = append(blocks, batch.GetBlock(index))
+	}
+	balances := batch.GetNep17Balances("NVVwFw6XyhtRCFQ8SpUTMdPyYt4Vd9A1XQ")
+	assert.Equal(t, 5, batch.Len())
+	assert.Nil(t, batch.Execute())
+
+	// the responses are in the order of the calls although the server reverses them
+	assert.Equal(t, 10, blocks[0].Result.Index)
+	assert.Equal(t, 11, blocks[1].Result.Index)
+	assert.True(t, blocks[2].HasError())
+	assert.Nil(t, blocks[2].NetError)
+	assert.False(t, blocks[3].HasError())
+	assert.Equal(t, 12, blocks[3].Result.Index)
+	assert.Equal(t, "NVVwFw6XyhtRCFQ8SpUTMdPyYt4Vd9A1XQ", balances.Result.Address)
+
+	server.Close()
+	batch = client.NewBatch()
+	block := batch.GetBlock("10")
+	assert.NotNil(t, batch.Execute())
+	assert.NotNil(t, block.NetError)
+}
@@ -1,8 +1,13 @@
 package rpc
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
 	"github.com/joeqian10/neo3-gogogo/rpc/models"
+	"io"
 	"strconv"
+	"strings"
 )
 
 type GetBestBlockHashResponse struct {
@@ -47,6 +52,12 @@ type GetContractStateResponse struct {
 	Result models.RpcContractState `json:"result"`
 }
 
+type GetNativeContractsResponse struct {
+	RpcResponse
+	ErrorResponse
+	Result []models.NativeContractState `json:"result"`
+}
+
 type GetRawMemPoolResponse struct {
 	RpcResponse
 	ErrorResponse
@@ -84,93 +95,337 @@ type GetCommitteeResponse struct {
 }
 
 func (n *RpcClient) GetBestBlockHash() GetBestBlockHashResponse {
+	return n.GetBestBlockHashWithContext(context.Background())
+}
+
+// GetBestBlockHashWithContext is GetBestBlockHash with the context of the request
+func (n *RpcClient) GetBestBlockHashWithContext(ctx context.Context) GetBestBlockHashResponse {
 	response := GetBestBlockHashResponse{}
 	params := []interface{}{}
-	_ = n.makeRequest("getbestblockhash", params, &response)
+	_ = n.makeRequest(ctx, "getbestblockhash", params, &response)
 	return response
 }
 
 func (n *RpcClient) GetBlock(hashOrIndex string) GetBlockResponse {
-	params := []interface{}{hashOrIndex, true}
+	return n.GetBlockWithContext(context.Background(), hashOrIndex)
+}
+
+// GetBlockWithContext is GetBlock with the context of the request
+func (n *RpcClient) GetBlockWithContext(ctx context.Context, hashOrIndex string) GetBlockResponse {
+	response := GetBlockResponse{}
+	_ = n.makeRequest(ctx, "getblock", getBlockParams(hashOrIndex), &response)
+	return response
+}
+
+// getBlockParams returns the params of a verbose getblock, an index is sent as a number
+func getBlockParams(hashOrIndex string) []interface{} {
 	index, err := strconv.Atoi(hashOrIndex)
 	if err == nil {
-		params = []interface{}{index, true}
+		return []interface{}{index, true}
 	}
-	response := GetBlockResponse{}
-	_ = n.makeRequest("getblock", params, &response)
-	return response
+	return []interface{}{hashOrIndex, true}
+}
+
+// GetBlockStream gets the verbose block like GetBlock, but decodes the transactions one by one from the
+// response and passes each to onTransaction instead of keeping them, so the returned block has no tx.
+// Decoding stops at the first error returned by onTransaction
+func (n *RpcClient) GetBlockStream(hashOrIndex string, onTransaction func(tx models.RpcTransaction) error) (*models.RpcBlock, error) {
+	return n.GetBlockStreamWithContext(context.Background(), hashOrIndex, onTransaction)
+}
+
+// GetBlockStreamWithContext is GetBlockStream with the context of the request
+func (n *RpcClient) GetBlockStreamWithContext(ctx context.Context, hashOrIndex string, onTransaction func(tx models.RpcTransaction) error) (*models.RpcBlock, error) {
+	block := models.RpcBlock{}
+	err := n.makeStreamRequest(ctx, "getblock", getBlockParams(hashOrIndex), func(body io.Reader) error {
+		return decodeBlockStream(json.NewDecoder(body), &block, onTransaction)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &block, nil
+}
+
+// decodeBlockStream decodes a getblock response, the tx array in result is decoded item by item
+func decodeBlockStream(dec *json.Decoder, block *models.RpcBlock, onTransaction func(tx models.RpcTransaction) error) error {
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+	hasResult := false
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		switch key {
+		case "error":
+			rpcError := RpcError{}
+			if err = dec.Decode(&rpcError); err != nil {
+				return err
+			}
+			if len(rpcError.Message) != 0 {
+				return fmt.Errorf(rpcError.Message)
+			}
+		case "result":
+			if err = decodeBlockResult(dec, block, onTransaction); err != nil {
+				return err
+			}
+			hasResult = true
+		default:
+			var skip json.RawMessage
+			if err = dec.Decode(&skip); err != nil {
+				return err
+			}
+		}
+	}
+	if !hasResult {
+		return fmt.Errorf("no result in response")
+	}
+	return expectDelim(dec, '}')
+}
+
+func decodeBlockResult(dec *json.Decoder, block *models.RpcBlock, onTransaction func(tx models.RpcTransaction) error) error {
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+	fields := make(map[string]json.RawMessage)
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		name, _ := key.(string)
+		if name != "tx" {
+			var value json.RawMessage
+			if err = dec.Decode(&value); err != nil {
+				return err
+			}
+			fields[name] = value
+			continue
+		}
+		if err = expectDelim(dec, '['); err != nil {
+			return err
+		}
+		for dec.More() {
+			trx := models.RpcTransaction{}
+			if err = dec.Decode(&trx); err != nil {
+				return err
+			}
+			if err = onTransaction(trx); err != nil {
+				return err
+			}
+		}
+		if err = expectDelim(dec, ']'); err != nil {
+			return err
+		}
+	}
+	if err := expectDelim(dec, '}'); err != nil {
+		return err
+	}
+	b, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, block)
+}
+
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	t, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if d, ok := t.(json.Delim); !ok || d != delim {
+		return fmt.Errorf("invalid json: expected %s, got %v", delim, t)
+	}
+	return nil
 }
 
 func (n *RpcClient) GetBlockCount() GetBlockCountResponse {
+	return n.GetBlockCountWithContext(context.Background())
+}
+
+// GetBlockCountWithContext is GetBlockCount with the context of the request
+func (n *RpcClient) GetBlockCountWithContext(ctx context.Context) GetBlockCountResponse {
 	response := GetBlockCountResponse{}
 	params := []interface{}{}
-	_ = n.makeRequest("getblockcount", params, &response)
+	_ = n.makeRequest(ctx, "getblockcount", params, &response)
 	return response
 }
 
 func (n *RpcClient) GetBlockHash(index uint32) GetBlockHashResponse {
+	return n.GetBlockHashWithContext(context.Background(), index)
+}
+
+// GetBlockHashWithContext is GetBlockHash with the context of the request
+func (n *RpcClient) GetBlockHashWithContext(ctx context.Context, index uint32) GetBlockHashResponse {
 	response := GetBlockHashResponse{}
 	params := []interface{}{index}
-	_ = n.makeRequest("getblockhash", params, &response)
+	_ = n.makeRequest(ctx, "getblockhash", params, &response)
 	return response
 }
 
 func (n *RpcClient) GetBlockHeader(hashOrIndex string) GetBlockHeaderResponse {
+	return n.GetBlockHeaderWithContext(context.Background(), hashOrIndex)
+}
+
+// GetBlockHeaderWithContext is GetBlockHeader with the context of the request
+func (n *RpcClient) GetBlockHeaderWithContext(ctx context.Context, hashOrIndex string) GetBlockHeaderResponse {
 	params := []interface{}{hashOrIndex, true}
 	index, err := strconv.Atoi(hashOrIndex)
 	if err == nil {
 		params = []interface{}{index, true}
 	}
 	response := GetBlockHeaderResponse{}
-	_ = n.makeRequest("getblockheader", params, &response)
+	_ = n.makeRequest(ctx, "getblockheader", params, &response)
 	return response
 }
 
 func (n *RpcClient) GetContractState(scriptHash string) GetContractStateResponse {
+	return n.GetContractStateWithContext(context.Background(), scriptHash)
+}
+
+// GetContractStateWithContext is GetContractState with the context of the request
+func (n *RpcClient) GetContractStateWithContext(ctx context.Context, scriptHash string) GetContractStateResponse {
 	response := GetContractStateResponse{}
 	params := []interface{}{scriptHash}
-	_ = n.makeRequest("getcontractstate", params, &response)
+	_ = n.makeRequest(ctx, "getcontractstate", params, &response)
 	return response
 }
 
 func (n *RpcClient) GetRawMemPool() GetRawMemPoolResponse {
+	return n.GetRawMemPoolWithContext(context.Background())
+}
+
+// GetRawMemPoolWithContext is GetRawMemPool with the context of the request
+func (n *RpcClient) GetRawMemPoolWithContext(ctx context.Context) GetRawMemPoolResponse {
 	response := GetRawMemPoolResponse{}
 	params := []interface{}{}
-	_ = n.makeRequest("getrawmempool", params, &response)
+	_ = n.makeRequest(ctx, "getrawmempool", params, &response)
 	return response
 }
 
 func (n *RpcClient) GetRawTransaction(txid string) GetRawTransactionResponse {
+	return n.GetRawTransactionWithContext(context.Background(), txid)
+}
+
+// GetRawTransactionWithContext is GetRawTransaction with the context of the request
+func (n *RpcClient) GetRawTransactionWithContext(ctx context.Context, txid string) GetRawTransactionResponse {
 	response := GetRawTransactionResponse{}
 	params := []interface{}{txid, 1}
-	_ = n.makeRequest("getrawtransaction", params, &response)
+	_ = n.makeRequest(ctx, "getrawtransaction", params, &response)
 	return response
 }
 
 func (n *RpcClient) GetStorage(scripthash string, key string) GetStorageResponse {
+	return n.GetStorageWithContext(context.Background(), scripthash, key)
+}
+
+// GetStorageWithContext is GetStorage with the context of the request
+func (n *RpcClient) GetStorageWithContext(ctx context.Context, scripthash string, key string) GetStorageResponse {
 	response := GetStorageResponse{}
 	params := []interface{}{scripthash, key}
-	_ = n.makeRequest("getstorage", params, &response)
+	_ = n.makeRequest(ctx, "getstorage", params, &response)
 	return response
 }
 
+// GetStorages reads several storage keys of one contract in a single batch request.
+// An invokescript can not open the storage context of another contract, so the reads
+// are sent as a JSON-RPC batch of getstorage calls. Unknown keys are not included in
+// the result, any other error of a key is returned. The keys must be distinct.
+func (n *RpcClient) GetStorages(scripthash string, keys []string) (map[string]string, error) {
+	return n.GetStoragesWithContext(context.Background(), scripthash, keys)
+}
+
+// GetStoragesWithContext is GetStorages with the context of the request
+func (n *RpcClient) GetStoragesWithContext(ctx context.Context, scripthash string, keys []string) (map[string]string, error) {
+	result := make(map[string]string)
+	if len(keys) == 0 {
+		return result, nil
+	}
+	requests := make([]RpcRequest, len(keys))
+	seen := make(map[string]bool, len(keys))
+	for i, key := range keys {
+		if seen[key] {
+			return nil, fmt.Errorf("duplicate key: %s", key)
+		}
+		seen[key] = true
+		requests[i] = NewRequest("getstorage", []interface{}{scripthash, key})
+		requests[i].ID = i + 1
+	}
+	var responses []GetStorageResponse
+	err := n.makeBatchRequest(ctx, requests, &responses)
+	if err != nil {
+		return nil, err
+	}
+	answered := make([]bool, len(keys))
+	for _, r := range responses {
+		if r.ID < 1 || r.ID > len(keys) || answered[r.ID-1] {
+			return nil, fmt.Errorf("unexpected response id: %d", r.ID)
+		}
+		answered[r.ID-1] = true
+		if r.HasError() {
+			if isUnknownStorageError(r.Error) {
+				continue
+			}
+			return nil, fmt.Errorf("key %s: %s", keys[r.ID-1], r.GetErrorInfo())
+		}
+		result[keys[r.ID-1]] = r.Result
+	}
+	if len(responses) != len(keys) {
+		return nil, fmt.Errorf("%d responses for %d keys", len(responses), len(keys))
+	}
+	return result, nil
+}
+
+// isUnknownStorageError returns whether the node reports the key does not exist, the code is -100
+// before neo 3.6 and -104 since
+func isUnknownStorageError(e RpcError) bool {
+	return (e.Code == -100 || e.Code == -104) && strings.HasPrefix(e.Message, "Unknown storage")
+}
+
 func (n *RpcClient) GetTransactionHeight(txid string) GetTransactionHeightResponse {
+	return n.GetTransactionHeightWithContext(context.Background(), txid)
+}
+
+// GetTransactionHeightWithContext is GetTransactionHeight with the context of the request
+func (n *RpcClient) GetTransactionHeightWithContext(ctx context.Context, txid string) GetTransactionHeightResponse {
 	response := GetTransactionHeightResponse{}
 	params := []interface{}{txid}
-	_ = n.makeRequest("gettransactionheight", params, &response)
+	_ = n.makeRequest(ctx, "gettransactionheight", params, &response)
 	return response
 }
 
 func (n *RpcClient) GetNextBlockValidators() GetNextBlockValidatorsResponse {
+	return n.GetNextBlockValidatorsWithContext(context.Background())
+}
+
+// GetNextBlockValidatorsWithContext is GetNextBlockValidators with the context of the request
+func (n *RpcClient) GetNextBlockValidatorsWithContext(ctx context.Context) GetNextBlockValidatorsResponse {
 	response := GetNextBlockValidatorsResponse{}
 	params := []interface{}{}
-	_ = n.makeRequest("getnextblockvalidators", params, &response)
+	_ = n.makeRequest(ctx, "getnextblockvalidators", params, &response)
 	return response
 }
 
 func (n *RpcClient) GetCommittee() GetCommitteeResponse {
+	return n.GetCommitteeWithContext(context.Background())
+}
+
+// GetCommitteeWithContext is GetCommittee with the context of the request
+func (n *RpcClient) GetCommitteeWithContext(ctx context.Context) GetCommitteeResponse {
 	response := GetCommitteeResponse{}
 	params := []interface{}{}
-	_ = n.makeRequest("getcommittee", params, &response)
+	_ = n.makeRequest(ctx, "getcommittee", params, &response)
+	return response
+}
+
+func (n *RpcClient) GetNativeContracts() GetNativeContractsResponse {
+	return n.GetNativeContractsWithContext(context.Background())
+}
+
+// GetNativeContractsWithContext is GetNativeContracts with the context of the request
+func (n *RpcClient) GetNativeContractsWithContext(ctx context.Context) GetNativeContractsResponse {
+	response := GetNativeContractsResponse{}
+	params := []interface{}{}
+	_ = n.makeRequest(ctx, "getnativecontracts", params, &response)
 	return response
 }
@@ -2,11 +2,19 @@ package rpc
 
 import (
 	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/rpc/models"
+	"github.com/joeqian10/neo3-gogogo/sc"
+	"github.com/joeqian10/neo3-gogogo/tx"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"strings"
 	"testing"
 )
 
@@ -288,6 +296,64 @@ func TestRpcClient_GetRawTransaction(t *testing.T) {
 	response := rpc.GetRawTransaction("0x3d39da5b227e3f02f5210b24690a0523162788668e490363c6a39813bb162e51")
 	r := response.Result
 	assert.Equal(t, "DEDcGjmiHJ22R4LjUuXOF83UDtJB3FUZPy4t8Ol+dSpQovI9KAfVVOrtz/NZBmEuVGXiALkJU6vklZ9XzzDrz0PJ", r.Witnesses[0].Invocation)
+	assert.Equal(t, "100000000", r.SysFee)
+	assert.Equal(t, 2102808, r.ValidUntilBlock)
+}
+
+func TestRpcTransaction_GetAttributes(t *testing.T) {
+	var client = new(HttpClientMock)
+	var rpc = RpcClient{Endpoint: new(url.URL), httpClient: client}
+	client.On("Do", mock.Anything).Return(&http.Response{
+		Body: ioutil.NopCloser(bytes.NewReader([]byte(`{
+			"jsonrpc": "2.0",
+			"id": 1,
+			"result": {
+				"hash": "0x3d39da5b227e3f02f5210b24690a0523162788668e490363c6a39813bb162e51",
+				"size": 271,
+				"version": 0,
+				"nonce": 1233336052,
+				"sender": "NZs2zXSPuuv9ZF6TDGSWT1RBmE8rfGj7UW",
+				"sysfee": "100000000",
+				"netfee": "1270450",
+				"validuntilblock": 2102808,
+				"attributes": [
+					{
+						"type": "HighPriority"
+					},
+					{
+						"type": "OracleResponse",
+						"id": 3,
+						"code": "Success",
+						"result": "aGVsbG8="
+					}
+				],
+				"signers": [
+					{
+						"account": "0x2916eba24e652fa006f3e5eb8f9892d2c3b00399",
+						"scopes": "CalledByEntry"
+					}
+				],
+				"script": "AoCWmAAMFGklqlVHEkOanGE7oRTvo/rCPdvKDBSZA7DD0pKYj+vl8wagL2VOousWKRPADAh0cmFuc2ZlcgwUiXcg2M129PAKv6N8Dt2InCCP3ptBYn1bUjk=",
+				"witnesses": []
+			}
+		}`))),
+	}, nil)
+
+	response := rpc.GetRawTransaction("0x3d39da5b227e3f02f5210b24690a0523162788668e490363c6a39813bb162e51")
+	assert.False(t, response.HasError())
+	attributes, err := response.Result.GetAttributes()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(attributes))
+	assert.Equal(t, tx.HighPriority, attributes[0].GetAttributeType())
+	oracle, ok := attributes[1].(*tx.OracleResponseAttribute)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(3), oracle.Id)
+	assert.Equal(t, tx.Success, oracle.Code)
+	assert.Equal(t, []byte("hello"), oracle.Result)
+
+	a := models.RpcTransactionAttribute{Type: "Unknown"}
+	_, err = a.ToAttribute()
+	assert.NotNil(t, err)
 }
 
 func TestRpcClient_GetStorage(t *testing.T) {
@@ -306,6 +372,85 @@ func TestRpcClient_GetStorage(t *testing.T) {
 	assert.Equal(t, "410321048096980021020702280100", r)
 }
 
+func TestRpcClient_GetStorages(t *testing.T) {
+	var client = new(HttpClientMock)
+	var rpc = RpcClient{Endpoint: new(url.URL), httpClient: client}
+	client.On("Do", mock.Anything).Return(&http.Response{
+		Body: ioutil.NopCloser(bytes.NewReader([]byte(`[
+			{
+				"jsonrpc": "2.0",
+				"id": 3,
+				"result": "AQ=="
+			},
+			{
+				"jsonrpc": "2.0",
+				"id": 1,
+				"result": "QQMhBICWmAAhAgcCKAEA"
+			},
+			{
+				"jsonrpc": "2.0",
+				"id": 2,
+				"error": {
+					"code": -100,
+					"message": "Unknown storage"
+				}
+			}
+		]`))),
+	}, nil)
+
+	keys := []string{"FGklqlVHEkOanGE7oRTvo/rCPdvK", "AQ==", "Ag=="}
+	r, err := rpc.GetStorages("0x9bde8f209c88dd0e7ca3bf0af0f476cdd8207789", keys)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(r))
+	assert.Equal(t, "QQMhBICWmAAhAgcCKAEA", r["FGklqlVHEkOanGE7oRTvo/rCPdvK"])
+	assert.Equal(t, "AQ==", r["Ag=="])
+	_, ok := r["AQ=="]
+	assert.False(t, ok)
+}
+
+func TestRpcClient_GetStorages_Errors(t *testing.T) {
+	getStorages := func(body string, keys []string) (map[string]string, error) {
+		var client = new(HttpClientMock)
+		var rpc = RpcClient{Endpoint: new(url.URL), httpClient: client}
+		client.On("Do", mock.Anything).Return(&http.Response{
+			Body: ioutil.NopCloser(bytes.NewReader([]byte(body))),
+		}, nil)
+		return rpc.GetStorages("0x9bde8f209c88dd0e7ca3bf0af0f476cdd8207789", keys)
+	}
+
+	// an error other than an unknown key
+	_, err := getStorages(`[
+		{"jsonrpc": "2.0", "id": 1, "result": "AQ=="},
+		{"jsonrpc": "2.0", "id": 2, "error": {"code": -32602, "message": "Invalid params"}}
+	]`, []string{"AQ==", "Ag=="})
+	assert.NotNil(t, err)
+
+	// an unknown key since neo 3.6
+	r, err := getStorages(`[
+		{"jsonrpc": "2.0", "id": 1, "result": "AQ=="},
+		{"jsonrpc": "2.0", "id": 2, "error": {"code": -104, "message": "Unknown storage item"}}
+	]`, []string{"AQ==", "Ag=="})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(r))
+
+	// a key without response
+	_, err = getStorages(`[
+		{"jsonrpc": "2.0", "id": 1, "result": "AQ=="}
+	]`, []string{"AQ==", "Ag=="})
+	assert.NotNil(t, err)
+
+	// a key with two responses
+	_, err = getStorages(`[
+		{"jsonrpc": "2.0", "id": 1, "result": "AQ=="},
+		{"jsonrpc": "2.0", "id": 1, "result": "AQ=="}
+	]`, []string{"AQ==", "Ag=="})
+	assert.NotNil(t, err)
+
+	// duplicate keys
+	_, err = getStorages(`[]`, []string{"AQ==", "AQ=="})
+	assert.NotNil(t, err)
+}
+
 func TestRpcClient_GetTransactionHeight(t *testing.T) {
 	var client = new(HttpClientMock)
 	var rpc = RpcClient{Endpoint: new(url.URL), httpClient: client}
@@ -382,3 +527,210 @@ func TestRpcClient_GetCommittee(t *testing.T) {
 	assert.Equal(t, 21, len(r))
 	assert.Equal(t, "020f2887f41474cfeb11fd262e982051c1541418137c02a0f4961af911045de639", r[0])
 }
+
+func TestRpcContractState_VerifyHash(t *testing.T) {
+	// native contracts are hashed with a zero deployer and checksum
+	neo := models.RpcContractState{
+		Id:       -5,
+		Hash:     "0xef4073a0f2b305a38ec4050e4d3d28bc40ea63f5",
+		Nef:      models.RpcNefFile{Magic: 860243278, Compiler: "neo-core-v3.0", CheckSum: 1325686241},
+		Manifest: models.RpcContractManifest{Name: "NeoToken"},
+	}
+	assert.Nil(t, neo.VerifyHash(nil))
+	gas := models.RpcContractState{
+		Id:       -6,
+		Hash:     "0xd2a4cff31913016155e38e474a2c06d08be276cf",
+		Nef:      models.RpcNefFile{Magic: 860243278, Compiler: "neo-core-v3.0", CheckSum: 2663858513},
+		Manifest: models.RpcContractManifest{Name: "GasToken"},
+	}
+	assert.Nil(t, gas.VerifyHash(nil))
+	gas.Manifest.Name = "NeoToken"
+	assert.NotNil(t, gas.VerifyHash(nil))
+
+	// a deployed contract
+	deployer, _ := helper.UInt160FromString("0x2916eba24e652fa006f3e5eb8f9892d2c3b00399")
+	other, _ := helper.UInt160FromString("0xedae9b97c72dbec43a7201b6388c24bfd86c71ae")
+	contract := models.RpcContractState{
+		Id:       8,
+		Hash:     "0x" + sc.GetContractHash(deployer, 3243376412, "testContract").String(),
+		Nef:      models.RpcNefFile{Magic: 860243278, Compiler: "neon", CheckSum: 3243376412},
+		Manifest: models.RpcContractManifest{Name: "testContract"},
+	}
+	assert.Nil(t, contract.VerifyHash(deployer))
+	assert.NotNil(t, contract.VerifyHash(other))
+	assert.NotNil(t, contract.VerifyHash(nil))
+}
+
+func TestRpcContractManifest_CanCall(t *testing.T) {
+	target := &models.RpcContractState{
+		Hash: "0x2916eba24e652fa006f3e5eb8f9892d2c3b00399",
+		Manifest: models.RpcContractManifest{
+			Groups: []models.RpcContractGroup{{PubKey: "03b209fd4f53a7170ea4444e0cb0a6bb6a53c2bd016926989cf85f9b0fba17a70c"}},
+		},
+	}
+
+	var wildcard models.RpcContractManifest
+	err := json.Unmarshal([]byte(`{"permissions":[{"contract":"*","methods":"*"}]}`), &wildcard)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"*"}, wildcard.Permissions[0].Methods)
+	allowed, err := wildcard.CanCall(target, "transfer")
+	assert.Nil(t, err)
+	assert.True(t, allowed)
+
+	var specific models.RpcContractManifest
+	err = json.Unmarshal([]byte(`{"permissions":[
+		{"contract":"0x2916eba24e652fa006f3e5eb8f9892d2c3b00399","methods":["balanceOf"]},
+		{"contract":"03b209fd4f53a7170ea4444e0cb0a6bb6a53c2bd016926989cf85f9b0fba17a70c","methods":["symbol"]}
+	]}`), &specific)
+	assert.Nil(t, err)
+	allowed, err = specific.CanCall(target, "balanceOf")
+	assert.Nil(t, err)
+	assert.True(t, allowed)
+	allowed, err = specific.CanCall(target, "symbol") // allowed by group
+	assert.Nil(t, err)
+	assert.True(t, allowed)
+	allowed, err = specific.CanCall(target, "transfer")
+	assert.Nil(t, err)
+	assert.False(t, allowed)
+
+	other := &models.RpcContractState{Hash: "0xedae9b97c72dbec43a7201b6388c24bfd86c71ae"}
+	allowed, err = specific.CanCall(other, "balanceOf")
+	assert.Nil(t, err)
+	assert.False(t, allowed)
+
+	invalid := models.RpcContractManifest{Permissions: []models.RpcContractPermission{{Contract: "abc", Methods: []string{"*"}}}}
+	_, err = invalid.CanCall(target, "balanceOf")
+	assert.NotNil(t, err)
+	err = json.Unmarshal([]byte(`{"permissions":[{"contract":"*","methods":"balanceOf"}]}`), &invalid)
+	assert.NotNil(t, err)
+}
+
+// countingReader counts the bytes read from the response body
+type countingReader struct {
+	r io.Reader
+	n int
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += n
+	return n, err
+}
+
+func TestRpcClient_GetBlockStream(t *testing.T) {
+	trx := `{
+		"hash": "0x3d39da5b227e3f02f5210b24690a0523162788668e490363c6a39813bb162e51",
+		"size": 270,
+		"version": 0,
+		"nonce": 1233336052,
+		"sender": "NZs2zXSPuuv9ZF6TDGSWT1RBmE8rfGj7UW",
+		"sysfee": "100000000",
+		"netfee": "1270450",
+		"validuntilblock": 2102808,
+		"attributes": [],
+		"signers": [{"account": "0x2916eba24e652fa006f3e5eb8f9892d2c3b00399", "scopes": "CalledByEntry"}],
+		"script": "AoCWmAAMFGklqlVHEkOanGE7oRTvo/rCPdvKDBSZA7DD0pKYj+vl8wagL2VOousWKRPADAh0cmFuc2ZlcgwUiXcg2M129PAKv6N8Dt2InCCP3ptBYn1bUjk=",
+		"witnesses": [{
+			"invocation": "DEDcGjmiHJ22R4LjUuXOF83UDtJB3FUZPy4t8Ol+dSpQovI9KAfVVOrtz/NZBmEuVGXiALkJU6vklZ9XzzDrz0PJ",
+			"verification": "EQwhA6oFL7y45bM6Tu/WYlNvhoRkHwQQnx1eac3abwhIkChqEQtBMHOzuw=="
+		}]
+	}`
+	count := 5000
+	txs := make([]string, count)
+	for i := range txs {
+		txs[i] = trx
+	}
+	body := `{
+		"jsonrpc": "2.0",
+		"id": 1,
+		"result": {
+			"hash": "0x1329b78cbdcded8058d4f65c0f1f63fa79c2a4ed5fa266951734018f587f7835",
+			"size": 1350492,
+			"version": 0,
+			"previousblockhash": "0x991cb1c359cdcf8129b5bc54b4c4fc8345ac17927d4825bcda4d6a8c46dcfb78",
+			"merkleroot": "0xf2eb105cc5608fe076e563cb40a4e1593df9bd93a954c069fca2ad74e52f9ece",
+			"time": 1578382911810,
+			"index": 409,
+			"nextconsensus": "NZs2zXSPuuv9ZF6TDGSWT1RBmE8rfGj7UW",
+			"witnesses": [],
+			"tx": [` + strings.Join(txs, ",") + `],
+			"confirmations": 2,
+			"nextblockhash": "0xb59b28a806d8f30a8c71195500bf3e834238df2fc79fd5f984e516737c8bb3cd"
+		}
+	}`
+	reader := &countingReader{r: strings.NewReader(body)}
+	var client = new(HttpClientMock)
+	var rpc = RpcClient{Endpoint: new(url.URL), httpClient: client}
+	client.On("Do", mock.Anything).Return(&http.Response{Body: ioutil.NopCloser(reader)}, nil)
+
+	n, readAtFirst := 0, 0
+	block, err := rpc.GetBlockStream("409", func(tx models.RpcTransaction) error {
+		if n == 0 {
+			readAtFirst = reader.n
+		}
+		n++
+		assert.Equal(t, 2102808, tx.ValidUntilBlock)
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, count, n)
+	assert.True(t, readAtFirst < len(body)/10) // the first tx comes before the body is read
+	assert.Equal(t, 409, block.Index)
+	assert.Equal(t, 2, block.Confirmations)
+	assert.Equal(t, "0xb59b28a806d8f30a8c71195500bf3e834238df2fc79fd5f984e516737c8bb3cd", block.NextBlockHash)
+	assert.Nil(t, block.Tx)
+
+	// the callback stops decoding
+	client = new(HttpClientMock)
+	rpc = RpcClient{Endpoint: new(url.URL), httpClient: client}
+	client.On("Do", mock.Anything).Return(&http.Response{Body: ioutil.NopCloser(strings.NewReader(body))}, nil)
+	n = 0
+	_, err = rpc.GetBlockStream("409", func(tx models.RpcTransaction) error {
+		n++
+		if n == 10 {
+			return fmt.Errorf("stop")
+		}
+		return nil
+	})
+	assert.EqualError(t, err, "stop")
+	assert.Equal(t, 10, n)
+
+	client = new(HttpClientMock)
+	rpc = RpcClient{Endpoint: new(url.URL), httpClient: client}
+	client.On("Do", mock.Anything).Return(&http.Response{Body: ioutil.NopCloser(strings.NewReader(`{
+		"jsonrpc": "2.0",
+		"id": 1,
+		"error": {"code": -100, "message": "Unknown block"}
+	}`))}, nil)
+	_, err = rpc.GetBlockStream("409", func(tx models.RpcTransaction) error { return nil })
+	assert.EqualError(t, err, "Unknown block")
+}
+
+func TestRpcClient_GetNativeContracts(t *testing.T) {
+	var client = new(HttpClientMock)
+	var rpc = RpcClient{Endpoint: new(url.URL), httpClient: client}
+	client.On("Do", mock.Anything).Return(&http.Response{
+		Body: ioutil.NopCloser(bytes.NewReader([]byte(`{
+			"jsonrpc": "2.0",
+			"id": 1,
+			"result": [
+				{
+					"id": -6,
+					"hash": "0xd2a4cff31913016155e38e474a2c06d08be276cf",
+					"nef": {"magic": 860243278, "compiler": "neo-core-v3.0", "tokens": [], "script": "QRr3e2c=", "checksum": 2663858513},
+					"manifest": {"name": "GasToken", "groups": [], "supportedstandards": ["NEP-17"], "abi": {"methods": [], "events": []}, "permissions": [{"contract": "*", "methods": "*"}], "trusts": [], "extra": null},
+					"updatehistory": [0]
+				}
+			]
+		}`))),
+	}, nil)
+
+	response := rpc.GetNativeContracts()
+	assert.False(t, response.HasError())
+	assert.Equal(t, 1, len(response.Result))
+	c := response.Result[0]
+	assert.Equal(t, -6, c.Id)
+	assert.Equal(t, "0xd2a4cff31913016155e38e474a2c06d08be276cf", c.Hash)
+	assert.Equal(t, "GasToken", c.Manifest.Name)
+	assert.Equal(t, []uint32{0}, c.UpdateHistory)
+}
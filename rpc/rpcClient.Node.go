@@ -1,6 +1,10 @@
 package rpc
 
-import "github.com/joeqian10/neo3-gogogo/rpc/models"
+import (
+	"context"
+
+	"github.com/joeqian10/neo3-gogogo/rpc/models"
+)
 
 type GetConnectionCountResponse struct {
 	RpcResponse
@@ -37,36 +41,61 @@ type SubmitBlockResponse struct {
 }
 
 func (n *RpcClient) GetConnectionCount() GetConnectionCountResponse {
+	return n.GetConnectionCountWithContext(context.Background())
+}
+
+// GetConnectionCountWithContext is GetConnectionCount with the context of the request
+func (n *RpcClient) GetConnectionCountWithContext(ctx context.Context) GetConnectionCountResponse {
 	response := GetConnectionCountResponse{}
 	params := []interface{}{}
-	_ = n.makeRequest("getconnectioncount", params, &response)
+	_ = n.makeRequest(ctx, "getconnectioncount", params, &response)
 	return response
 }
 
 func (n *RpcClient) GetPeers() GetPeersResponse {
+	return n.GetPeersWithContext(context.Background())
+}
+
+// GetPeersWithContext is GetPeers with the context of the request
+func (n *RpcClient) GetPeersWithContext(ctx context.Context) GetPeersResponse {
 	response := GetPeersResponse{}
 	params := []interface{}{}
-	_ = n.makeRequest("getpeers", params, &response)
+	_ = n.makeRequest(ctx, "getpeers", params, &response)
 	return response
 }
 
 func (n *RpcClient) GetVersion() GetVersionResponse {
+	return n.GetVersionWithContext(context.Background())
+}
+
+// GetVersionWithContext is GetVersion with the context of the request
+func (n *RpcClient) GetVersionWithContext(ctx context.Context) GetVersionResponse {
 	response := GetVersionResponse{}
 	params := []interface{}{}
-	_ = n.makeRequest("getversion", params, &response)
+	_ = n.makeRequest(ctx, "getversion", params, &response)
 	return response
 }
 
 func (n *RpcClient) SendRawTransaction(rawTransactionInHex string) SendRawTransactionResponse {
+	return n.SendRawTransactionWithContext(context.Background(), rawTransactionInHex)
+}
+
+// SendRawTransactionWithContext is SendRawTransaction with the context of the request
+func (n *RpcClient) SendRawTransactionWithContext(ctx context.Context, rawTransactionInHex string) SendRawTransactionResponse {
 	response := SendRawTransactionResponse{}
 	params := []interface{}{rawTransactionInHex, 1}
-	_ = n.makeRequest("sendrawtransaction", params, &response)
+	_ = n.makeRequest(ctx, "sendrawtransaction", params, &response)
 	return response
 }
 
 func (n *RpcClient) SubmitBlock(blockHex string) SubmitBlockResponse {
+	return n.SubmitBlockWithContext(context.Background(), blockHex)
+}
+
+// SubmitBlockWithContext is SubmitBlock with the context of the request
+func (n *RpcClient) SubmitBlockWithContext(ctx context.Context, blockHex string) SubmitBlockResponse {
 	response := SubmitBlockResponse{}
 	params := []interface{}{blockHex}
-	_ = n.makeRequest("submitblock", params, &response)
+	_ = n.makeRequest(ctx, "submitblock", params, &response)
 	return response
 }
@@ -2,12 +2,14 @@ package rpc
 
 import (
 	"bytes"
+	"github.com/joeqian10/neo3-gogogo/rpc/models"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"io/ioutil"
 	"net/http"
 	"net/url"
 	"testing"
+	"time"
 )
 
 func TestRpcClient_GetConnectionCount(t *testing.T) {
@@ -46,6 +48,50 @@ func TestRpcClient_GetPeers(t *testing.T) {
 	assert.Equal(t, 0, len(r.Bad))
 }
 
+func TestRpcClient_GetPeers2(t *testing.T) {
+	var client = new(HttpClientMock)
+	var rpc = RpcClient{Endpoint: new(url.URL), httpClient: client}
+	client.On("Do", mock.Anything).Return(&http.Response{
+		Body: ioutil.NopCloser(bytes.NewReader([]byte(`{
+			"jsonrpc": "2.0",
+			"id": 1,
+			"result": {
+				"unconnected": [
+					{
+						"address": "47.90.28.99",
+						"port": 10333
+					},
+					{
+						"address": "34.208.107.201",
+						"port": "10333"
+					}
+				],
+				"bad": [
+					{
+						"address": "127.0.0.1",
+						"port": 20333
+					}
+				],
+				"connected": [
+					{
+						"address": "::ffff:161.117.141.11",
+						"port": 10333
+					}
+				]
+			}
+		}`))),
+	}, nil)
+
+	response := rpc.GetPeers()
+	assert.False(t, response.HasError())
+	r := response.Result
+	assert.Equal(t, 4, r.Count())
+	assert.Equal(t, 2, len(r.Unconnected))
+	assert.Equal(t, 10333, r.Unconnected[1].Port)
+	assert.Equal(t, "127.0.0.1:20333", r.Bad[0].Endpoint())
+	assert.Equal(t, "[::ffff:161.117.141.11]:10333", r.Connected[0].Endpoint())
+}
+
 func TestRpcClient_GetVersion(t *testing.T) {
 	var client = new(HttpClientMock)
 	var rpc = RpcClient{Endpoint: new(url.URL), httpClient: client}
@@ -67,6 +113,44 @@ func TestRpcClient_GetVersion(t *testing.T) {
 	assert.Equal(t, 20333, r.TcpPort)
 }
 
+func TestRpcProtocol_EstimateExpiry(t *testing.T) {
+	var client = new(HttpClientMock)
+	var rpc = RpcClient{Endpoint: new(url.URL), httpClient: client}
+	client.On("Do", mock.Anything).Return(&http.Response{
+		Body: ioutil.NopCloser(bytes.NewReader([]byte(`{
+			"jsonrpc": "2.0",
+			"id": 1,
+			"result": {
+				"tcpport": 10333,
+				"wsport": 10334,
+				"nonce": 1930156121,
+				"useragent": "/Neo:3.0.3/",
+				"protocol": {
+					"addressversion": 53,
+					"network": 860833102,
+					"validatorscount": 7,
+					"msperblock": 15000,
+					"maxtraceableblocks": 2102400,
+					"maxvaliduntilblockincrement": 5760,
+					"maxtransactionsperblock": 512,
+					"memorypoolmaxtransactions": 50000,
+					"initialgasdistribution": 5200000000000000
+				}
+			}
+		}`))),
+	}, nil)
+
+	response := rpc.GetVersion()
+	assert.False(t, response.HasError())
+	p := response.Result.Protocol
+	assert.Equal(t, uint32(860833102), p.Network)
+	assert.Equal(t, "MainNet", p.NetworkName())
+	assert.Equal(t, 15*time.Second, p.BlockTime())
+	assert.Equal(t, 4*time.Minute, p.EstimateExpiry(100, 116))
+	assert.Equal(t, time.Duration(0), p.EstimateExpiry(116, 116))
+	assert.Equal(t, time.Duration(0), p.EstimateExpiry(200, 116))
+}
+
 func TestRpcClient_SendRawTransaction(t *testing.T) {
 	var client = new(HttpClientMock)
 	var rpc = RpcClient{Endpoint: new(url.URL), httpClient: client}
@@ -102,3 +186,9 @@ func TestRpcClient_SubmitBlock(t *testing.T) {
 	r := response.Result
 	assert.Equal(t, "0x407c75ed84bc2cb70303fbdb791d45b56ccef7209813c53da1c2456c1241294a", r.Hash)
 }
+
+func TestRpcProtocol_NetworkName(t *testing.T) {
+	assert.Equal(t, "MainNet", (&models.RpcProtocol{Network: 860833102}).NetworkName())
+	assert.Equal(t, "TestNet", (&models.RpcProtocol{Network: 894710606}).NetworkName())
+	assert.Equal(t, "Custom", (&models.RpcProtocol{Network: 1234}).NetworkName())
+}
@@ -1,6 +1,10 @@
 package rpc
 
-import "github.com/joeqian10/neo3-gogogo/rpc/models"
+import (
+	"context"
+
+	"github.com/joeqian10/neo3-gogogo/rpc/models"
+)
 
 type GetApplicationLogResponse struct {
 	RpcResponse
@@ -22,22 +26,37 @@ type GetNep17TransfersResponse struct {
 
 // the endpoint needs to use ApplicationLogs plugin
 func (n *RpcClient) GetApplicationLog(txId string) GetApplicationLogResponse {
+	return n.GetApplicationLogWithContext(context.Background(), txId)
+}
+
+// GetApplicationLogWithContext is GetApplicationLog with the context of the request
+func (n *RpcClient) GetApplicationLogWithContext(ctx context.Context, txId string) GetApplicationLogResponse {
 	response := GetApplicationLogResponse{}
 	params := []interface{}{txId}
-	_ = n.makeRequest("getapplicationlog", params, &response)
+	_ = n.makeRequest(ctx, "getapplicationlog", params, &response)
 	return response
 }
 
 // this endpoint needs RpcNep17Tracker plugin
 func (n *RpcClient) GetNep17Balances(address string) GetNep17BalancesResponse {
+	return n.GetNep17BalancesWithContext(context.Background(), address)
+}
+
+// GetNep17BalancesWithContext is GetNep17Balances with the context of the request
+func (n *RpcClient) GetNep17BalancesWithContext(ctx context.Context, address string) GetNep17BalancesResponse {
 	response := GetNep17BalancesResponse{}
 	params := []interface{}{address}
-	_ = n.makeRequest("getnep17balances", params, &response)
+	_ = n.makeRequest(ctx, "getnep17balances", params, &response)
 	return response
 }
 
 // this endpoint needs RpcNep17Tracker plugin
 func (n *RpcClient) GetNep17Transfers(address string, startTime *int, endTime *int) GetNep17TransfersResponse {
+	return n.GetNep17TransfersWithContext(context.Background(), address, startTime, endTime)
+}
+
+// GetNep17TransfersWithContext is GetNep17Transfers with the context of the request
+func (n *RpcClient) GetNep17TransfersWithContext(ctx context.Context, address string, startTime *int, endTime *int) GetNep17TransfersResponse {
 	response := GetNep17TransfersResponse{}
 	var params []interface{}
 	if startTime != nil {
@@ -49,6 +68,6 @@ func (n *RpcClient) GetNep17Transfers(address string, startTime *int, endTime *i
 	} else {
 		params = []interface{}{address}
 	}
-	_ = n.makeRequest("getnep17balances", params, &response)
+	_ = n.makeRequest(ctx, "getnep17transfers", params, &response)
 	return response
 }
\ No newline at end of file
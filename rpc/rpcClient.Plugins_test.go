@@ -2,9 +2,13 @@ package rpc
 
 import (
 	"bytes"
+	"github.com/joeqian10/neo3-gogogo/crypto"
+	"github.com/joeqian10/neo3-gogogo/sc"
+	"github.com/joeqian10/neo3-gogogo/vm"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"io/ioutil"
+	"math/big"
 	"net/http"
 	"net/url"
 	"testing"
@@ -80,6 +84,157 @@ func TestRpcClient_GetApplicationLog(t *testing.T) {
 	assert.Equal(t, "Array", r.Executions[0].Notifications[0].State.Type)
 }
 
+func TestRpcNotification_GetStateParameter(t *testing.T) {
+	var client = new(HttpClientMock)
+	var rpc = RpcClient{Endpoint: new(url.URL), httpClient: client}
+	client.On("Do", mock.Anything).Return(&http.Response{
+		Body: ioutil.NopCloser(bytes.NewReader([]byte(`{
+			"jsonrpc": "2.0",
+			"id": 1,
+			"result": {
+				"txid": "0x2aa2a01ba4ec4fa7fd3b1e8cd1ad1c8e5a3a1f5ee5b1ba43cc8f0d6f6cf4e1d8",
+				"executions": [
+					{
+						"trigger": "Application",
+						"vmstate": "HALT",
+						"gasconsumed": "9977780",
+						"stack": [{"type": "Boolean", "value": true}],
+						"notifications": [
+							{
+								"contract": "0xd2a4cff31913016155e38e474a2c06d08be276cf",
+								"eventname": "Transfer",
+								"state": {
+									"type": "Array",
+									"value": [
+										{"type": "Any"},
+										{"type": "ByteString", "value": "9S37k0BBDIaRxjEhW0Sk+9lDN4s="},
+										{"type": "Integer", "value": "25000000"}
+									]
+								}
+							},
+							{
+								"contract": "0xef4073a0f2b305a38ec4050e4d3d28bc40ea63f5",
+								"eventname": "Transfer",
+								"state": {
+									"type": "Array",
+									"value": [
+										{"type": "ByteString", "value": "9S37k0BBDIaRxjEhW0Sk+9lDN4s="},
+										{"type": "ByteString", "value": "1rSxahaE1EDW2TzNNlNk0rjQEpI="},
+										{"type": "Integer", "value": "10"}
+									]
+								}
+							}
+						]
+					}
+				]
+			}
+		}`))),
+	}, nil)
+
+	response := rpc.GetApplicationLog("0x2aa2a01ba4ec4fa7fd3b1e8cd1ad1c8e5a3a1f5ee5b1ba43cc8f0d6f6cf4e1d8")
+	assert.False(t, response.HasError())
+	notifications := response.Result.Executions[0].Notifications
+	from, _ := crypto.Base64Decode("9S37k0BBDIaRxjEhW0Sk+9lDN4s=")
+	to, _ := crypto.Base64Decode("1rSxahaE1EDW2TzNNlNk0rjQEpI=")
+
+	// GAS minted by claiming, from is null
+	gas, err := notifications[0].GetStateParameter()
+	assert.Nil(t, err)
+	assert.Equal(t, sc.Array, gas.Type)
+	items := gas.Value.([]sc.ContractParameter)
+	assert.Equal(t, 3, len(items))
+	assert.Equal(t, sc.Any, items[0].Type)
+	assert.Nil(t, items[0].Value)
+	assert.Equal(t, sc.ByteArray, items[1].Type)
+	assert.Equal(t, from, items[1].Value)
+	assert.Equal(t, "25000000", items[2].Value.(*big.Int).String())
+
+	neo, err := notifications[1].GetStateParameter()
+	assert.Nil(t, err)
+	items = neo.Value.([]sc.ContractParameter)
+	assert.Equal(t, from, items[0].Value)
+	assert.Equal(t, to, items[1].Value)
+	assert.Equal(t, sc.Integer, items[2].Type)
+	assert.Equal(t, "10", items[2].Value.(*big.Int).String())
+
+	// the log is not changed by decoding
+	_, ok := notifications[1].State.Value.([]interface{})
+	assert.True(t, ok)
+}
+
+func TestRpcExecution_GetTrigger(t *testing.T) {
+	var client = new(HttpClientMock)
+	var rpc = RpcClient{Endpoint: new(url.URL), httpClient: client}
+	client.On("Do", mock.Anything).Return(&http.Response{
+		Body: ioutil.NopCloser(bytes.NewReader([]byte(`{
+			"jsonrpc": "2.0",
+			"id": 1,
+			"result": {
+				"blockhash": "0x9d46a2c9a0a7b6c0f8c1b2ab4f1e0c7ab2a8d3b3e9a9e4b8f8e5a2d1c0b7f6e5",
+				"executions": [
+					{
+						"trigger": "OnPersist",
+						"vmstate": "HALT",
+						"gasconsumed": "0",
+						"stack": [],
+						"notifications": []
+					},
+					{
+						"trigger": "PostPersist",
+						"vmstate": "HALT",
+						"gasconsumed": "0",
+						"stack": [],
+						"notifications": []
+					},
+					{
+						"trigger": "Application",
+						"vmstate": "FAULT",
+						"exception": "ASSERT is executed with false result.",
+						"gasconsumed": "1007390",
+						"stack": [],
+						"notifications": []
+					}
+				]
+			}
+		}`))),
+	}, nil)
+
+	response := rpc.GetApplicationLog("0x9d46a2c9a0a7b6c0f8c1b2ab4f1e0c7ab2a8d3b3e9a9e4b8f8e5a2d1c0b7f6e5")
+	assert.False(t, response.HasError())
+	executions := response.Result.Executions
+	cases := []struct {
+		trigger sc.TriggerType
+		state   vm.VMState
+	}{
+		{sc.OnPersist, vm.HALT},
+		{sc.PostPersist, vm.HALT},
+		{sc.Application, vm.FAULT},
+	}
+	for i, c := range cases {
+		trigger, err := executions[i].GetTrigger()
+		assert.Nil(t, err)
+		assert.Equal(t, c.trigger, trigger)
+		assert.Equal(t, executions[i].Trigger, trigger.String())
+		state, err := executions[i].GetVMState()
+		assert.Nil(t, err)
+		assert.Equal(t, c.state, state)
+		assert.Equal(t, executions[i].VMState, state.String())
+	}
+
+	trigger, err := sc.NewTriggerTypeFromString("verification")
+	assert.Nil(t, err)
+	assert.Equal(t, "Verification", trigger.String())
+	for _, s := range []string{"NONE", "BREAK"} {
+		state, err := vm.NewVMStateFromString(s)
+		assert.Nil(t, err)
+		assert.Equal(t, s, state.String())
+	}
+	_, err = sc.NewTriggerTypeFromString("System")
+	assert.NotNil(t, err)
+	_, err = vm.NewVMStateFromString("")
+	assert.NotNil(t, err)
+}
+
 func TestRpcClient_GetNep17Balances(t *testing.T) {
 	var client = new(HttpClientMock)
 	var rpc = RpcClient{Endpoint: new(url.URL), httpClient: client}
@@ -109,6 +264,47 @@ func TestRpcClient_GetNep17Balances(t *testing.T) {
 	r := response.Result
 	assert.Equal(t, "NVVwFw6XyhtRCFQ8SpUTMdPyYt4Vd9A1XQ", r.Address)
 	assert.Equal(t, "0x9bde8f209c88dd0e7ca3bf0af0f476cdd8207789", r.Balances[0].AssetHash)
+	assert.Equal(t, "9995000000000000", r.Balances[1].Amount)
+	amount, err := r.Balances[1].GetAmount()
+	assert.Nil(t, err)
+	assert.Equal(t, big.NewInt(9995000000000000), amount)
+
+	r.Balances[1].Amount = "invalid"
+	_, err = r.Balances[1].GetAmount()
+	assert.NotNil(t, err)
+}
+
+func TestRpcClient_GetNep17Balances_TokensTracker(t *testing.T) {
+	var client = new(HttpClientMock)
+	var rpc = RpcClient{Endpoint: new(url.URL), httpClient: client}
+	client.On("Do", mock.Anything).Return(&http.Response{
+		Body: ioutil.NopCloser(bytes.NewReader([]byte(`{
+			"jsonrpc": "2.0",
+			"id": 1,
+			"result": {
+				"balance": [
+					{
+						"assethash": "0xd2a4cff31913016155e38e474a2c06d08be276cf",
+						"name": "GasToken",
+						"symbol": "GAS",
+						"decimals": "8",
+						"amount": "3000000100000000",
+						"lastupdatedblock": 3
+					}
+				],
+				"address": "NUqLhf1p1vQyP2KJjMcEwmdEBPnbCGouVp"
+			}
+		}`))),
+	}, nil)
+
+	response := rpc.GetNep17Balances("NUqLhf1p1vQyP2KJjMcEwmdEBPnbCGouVp")
+	assert.False(t, response.HasError())
+	b := response.Result.Balances[0]
+	assert.Equal(t, "0xd2a4cff31913016155e38e474a2c06d08be276cf", b.AssetHash)
+	assert.Equal(t, "GAS", b.Symbol)
+	assert.Equal(t, "8", b.Decimals)
+	assert.Equal(t, "3000000100000000", b.Amount)
+	assert.Equal(t, 3, b.LastUpdatedBlock)
 }
 
 func TestRpcClient_GetNep17Transfers(t *testing.T) {
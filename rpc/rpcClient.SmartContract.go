@@ -1,7 +1,9 @@
 package rpc
 
 import (
+	"context"
 	"github.com/joeqian10/neo3-gogogo/rpc/models"
+	"github.com/joeqian10/neo3-gogogo/sc"
 )
 
 type InvokeResultResponse struct {
@@ -10,6 +12,18 @@ type InvokeResultResponse struct {
 	Result models.InvokeResult `json:"result"`
 }
 
+type TraverseIteratorResponse struct {
+	RpcResponse
+	ErrorResponse
+	Result []models.StackItem `json:"result"`
+}
+
+type TerminateSessionResponse struct {
+	RpcResponse
+	ErrorResponse
+	Result bool `json:"result"`
+}
+
 type GetUnclaimedGasResponse struct {
 	RpcResponse
 	ErrorResponse
@@ -17,6 +31,11 @@ type GetUnclaimedGasResponse struct {
 }
 
 func (n *RpcClient) InvokeFunction(scriptHash string, method string, args []models.RpcContractParameter, signers []models.RpcSigner) InvokeResultResponse {
+	return n.InvokeFunctionWithContext(context.Background(), scriptHash, method, args, signers)
+}
+
+// InvokeFunctionWithContext is InvokeFunction with the context of the request
+func (n *RpcClient) InvokeFunctionWithContext(ctx context.Context, scriptHash string, method string, args []models.RpcContractParameter, signers []models.RpcSigner) InvokeResultResponse {
 	response := InvokeResultResponse{}
 	if args == nil {
 		args = []models.RpcContractParameter{}
@@ -25,12 +44,37 @@ func (n *RpcClient) InvokeFunction(scriptHash string, method string, args []mode
 		signers = []models.RpcSigner{}
 	}
 	params := []interface{}{scriptHash, method, args, signers}
-	_ = n.makeRequest("invokefunction", params, &response)
+	_ = n.makeRequest(ctx, "invokefunction", params, &response)
+	return response
+}
+
+// InvokeFunctionWithParameters calls invokefunction like InvokeFunction, the args are encoded by
+// sc.ContractParameter.MarshalJSON so arrays and maps can be passed to the node directly
+func (n *RpcClient) InvokeFunctionWithParameters(scriptHash string, method string, args []sc.ContractParameter, signers []models.RpcSigner) InvokeResultResponse {
+	return n.InvokeFunctionWithParametersWithContext(context.Background(), scriptHash, method, args, signers)
+}
+
+// InvokeFunctionWithParametersWithContext is InvokeFunctionWithParameters with the context of the request
+func (n *RpcClient) InvokeFunctionWithParametersWithContext(ctx context.Context, scriptHash string, method string, args []sc.ContractParameter, signers []models.RpcSigner) InvokeResultResponse {
+	response := InvokeResultResponse{}
+	if args == nil {
+		args = []sc.ContractParameter{}
+	}
+	if signers == nil {
+		signers = []models.RpcSigner{}
+	}
+	params := []interface{}{scriptHash, method, args, signers}
+	_ = n.makeRequest(ctx, "invokefunction", params, &response)
 	return response
 }
 
 // if there is no need to pass "signers", just pass nil
 func (n *RpcClient) InvokeScript(scriptInBase64 string, signers []models.RpcSigner) InvokeResultResponse {
+	return n.InvokeScriptWithContext(context.Background(), scriptInBase64, signers)
+}
+
+// InvokeScriptWithContext is InvokeScript with the context of the request
+func (n *RpcClient) InvokeScriptWithContext(ctx context.Context, scriptInBase64 string, signers []models.RpcSigner) InvokeResultResponse {
 	response := InvokeResultResponse{}
 	var params []interface{}
 	if signers != nil {
@@ -38,13 +82,64 @@ func (n *RpcClient) InvokeScript(scriptInBase64 string, signers []models.RpcSign
 	} else {
 		params = []interface{}{scriptInBase64}
 	}
-	_ = n.makeRequest("invokescript", params, &response)
+	_ = n.makeRequest(ctx, "invokescript", params, &response)
+	return response
+}
+
+// InvokeScriptWithDiagnostics runs the script like InvokeScript, and the result has the diagnostics of
+// the contracts invoked and the storage changed
+func (n *RpcClient) InvokeScriptWithDiagnostics(scriptInBase64 string, signers []models.RpcSigner) InvokeResultResponse {
+	return n.InvokeScriptWithDiagnosticsWithContext(context.Background(), scriptInBase64, signers)
+}
+
+// InvokeScriptWithDiagnosticsWithContext is InvokeScriptWithDiagnostics with the context of the request
+func (n *RpcClient) InvokeScriptWithDiagnosticsWithContext(ctx context.Context, scriptInBase64 string, signers []models.RpcSigner) InvokeResultResponse {
+	response := InvokeResultResponse{}
+	if signers == nil {
+		signers = []models.RpcSigner{}
+	}
+	params := []interface{}{scriptInBase64, signers, true}
+	_ = n.makeRequest(ctx, "invokescript", params, &response)
 	return response
 }
 
 func (n *RpcClient) GetUnclaimedGas(address string) GetUnclaimedGasResponse {
+	return n.GetUnclaimedGasWithContext(context.Background(), address)
+}
+
+// GetUnclaimedGasWithContext is GetUnclaimedGas with the context of the request
+func (n *RpcClient) GetUnclaimedGasWithContext(ctx context.Context, address string) GetUnclaimedGasResponse {
 	response := GetUnclaimedGasResponse{}
 	params := []interface{}{address}
-	_ = n.makeRequest("getunclaimedgas", params, &response)
+	_ = n.makeRequest(ctx, "getunclaimedgas", params, &response)
+	return response
+}
+
+// TraverseIterator gets at most count items of the iterator in the session, which are returned by invoke methods
+// in InvokeResult.Session and InvokeStack.Id. The iterator moves forward, so it is called again for the next page
+// until fewer than count items are returned
+func (n *RpcClient) TraverseIterator(session, iterator string, count int) TraverseIteratorResponse {
+	return n.TraverseIteratorWithContext(context.Background(), session, iterator, count)
+}
+
+// TraverseIteratorWithContext is TraverseIterator with the context of the request
+func (n *RpcClient) TraverseIteratorWithContext(ctx context.Context, session, iterator string, count int) TraverseIteratorResponse {
+	response := TraverseIteratorResponse{}
+	params := []interface{}{session, iterator, count}
+	_ = n.makeRequest(ctx, "traverseiterator", params, &response)
+	return response
+}
+
+// TerminateSession releases the session before it expires on the node, the result is false if the session
+// was not found
+func (n *RpcClient) TerminateSession(session string) TerminateSessionResponse {
+	return n.TerminateSessionWithContext(context.Background(), session)
+}
+
+// TerminateSessionWithContext is TerminateSession with the context of the request
+func (n *RpcClient) TerminateSessionWithContext(ctx context.Context, session string) TerminateSessionResponse {
+	response := TerminateSessionResponse{}
+	params := []interface{}{session}
+	_ = n.makeRequest(ctx, "terminatesession", params, &response)
 	return response
 }
@@ -2,10 +2,17 @@ package rpc
 
 import (
 	"bytes"
+	"encoding/json"
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/rpc/models"
+	"github.com/joeqian10/neo3-gogogo/sc"
+	"github.com/joeqian10/neo3-gogogo/vm"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"io/ioutil"
+	"math/big"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"testing"
 )
@@ -37,6 +44,40 @@ func TestRpcClient_InvokeFunction(t *testing.T) {
 	assert.Equal(t, "8", r.Stack[0].Value)
 }
 
+func TestRpcClient_InvokeFunctionWithParameters(t *testing.T) {
+	var client = new(HttpClientMock)
+	var rpc = RpcClient{Endpoint: new(url.URL), httpClient: client}
+	var body []byte
+	client.On("Do", mock.Anything).Run(func(args mock.Arguments) {
+		body, _ = ioutil.ReadAll(args.Get(0).(*http.Request).Body)
+	}).Return(&http.Response{
+		Body: ioutil.NopCloser(bytes.NewReader([]byte(`{"jsonrpc":"2.0","id":1,"result":{"state":"HALT","gasconsumed":"2007570","stack":[]}}`))),
+	}, nil)
+
+	a, _ := helper.UInt160FromString("0x2916eba24e652fa006f3e5eb8f9892d2c3b00399")
+	b, _ := helper.UInt160FromString("0x8c23f196d8a1bfd103a9dcb1f9ccf0c611377d3b")
+	args := []sc.ContractParameter{{
+		Type:  sc.Array,
+		Value: []sc.ContractParameter{{Type: sc.Hash160, Value: a}, {Type: sc.Hash160, Value: b}},
+	}}
+	response := rpc.InvokeFunctionWithParameters("0xd2a4cff31913016155e38e474a2c06d08be276cf", "balanceOf", args, nil)
+	assert.False(t, response.HasError())
+	assert.Equal(t, "HALT", response.Result.State)
+
+	request := struct {
+		Params []json.RawMessage `json:"params"`
+	}{}
+	assert.Nil(t, json.Unmarshal(body, &request))
+	assert.Equal(t, 4, len(request.Params))
+	assert.Equal(t, `[{"type":"Array","value":[{"type":"Hash160","value":"0x2916eba24e652fa006f3e5eb8f9892d2c3b00399"},{"type":"Hash160","value":"0x8c23f196d8a1bfd103a9dcb1f9ccf0c611377d3b"}]}]`, string(request.Params[2]))
+	assert.Equal(t, `[]`, string(request.Params[3]))
+
+	// an invalid parameter is not sent
+	response = rpc.InvokeFunctionWithParameters("0xd2a4cff31913016155e38e474a2c06d08be276cf", "balanceOf", []sc.ContractParameter{{Type: sc.Hash160, Value: "a"}}, nil)
+	assert.True(t, response.HasError())
+	client.AssertNumberOfCalls(t, "Do", 1)
+}
+
 func TestRpcClient_InvokeScript(t *testing.T) {
 	var client = new(HttpClientMock)
 	var rpc = RpcClient{Endpoint: new(url.URL), httpClient: client}
@@ -82,3 +123,447 @@ func TestRpcClient_GetUnclaimedGas(t *testing.T) {
 	r := response.Result
 	assert.Equal(t, "9693738", r.Unclaimed)
 }
+
+func TestInvokeResult_Items(t *testing.T) {
+	var client = new(HttpClientMock)
+	var rpc = RpcClient{Endpoint: new(url.URL), httpClient: client}
+	client.On("Do", mock.Anything).Return(&http.Response{
+		Body: ioutil.NopCloser(bytes.NewReader([]byte(`{
+			"jsonrpc": "2.0",
+			"id": 1,
+			"result": {
+				"script": "wh8MBnRva2Vuc0EMFHlvdXIgY29udHJhY3QgaGFzaCBoZXJlQWJ9W1I=",
+				"state": "HALT",
+				"gasconsumed": "1007390",
+				"stack": [
+					{
+						"type": "Array",
+						"value": [
+							{
+								"type": "ByteString",
+								"value": "dG9rZW4x"
+							},
+							{
+								"type": "ByteString",
+								"value": "dG9rZW4y"
+							}
+						]
+					}
+				]
+			}
+		}`))),
+	}, nil)
+
+	response := rpc.InvokeFunction("0x8c23f196d8a1bfd103a9dcb1f9ccf0c611377d3b", "tokens", nil, nil)
+	r := response.Result
+	assert.False(t, r.IsIterator())
+	items, err := r.Items(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(items))
+	assert.Equal(t, "dG9rZW4y", items[1].Value)
+}
+
+func TestInvokeResult_Items_Iterator(t *testing.T) {
+	var client = new(HttpClientMock)
+	var rpc = RpcClient{Endpoint: new(url.URL), httpClient: client}
+	client.On("Do", mock.Anything).Return(&http.Response{
+		Body: ioutil.NopCloser(bytes.NewReader([]byte(`{
+			"jsonrpc": "2.0",
+			"id": 1,
+			"result": {
+				"script": "wh8MBnRva2Vuc0EMFHlvdXIgY29udHJhY3QgaGFzaCBoZXJlQWJ9W1I=",
+				"state": "HALT",
+				"gasconsumed": "1007390",
+				"exception": null,
+				"stack": [
+					{
+						"type": "InteropInterface",
+						"interface": "IIterator",
+						"id": "fcf7b800-192a-488e-9d6e-c2d6df7be7a5"
+					}
+				],
+				"session": "a93e1bd3-5a84-4cf0-8a5a-fd9bd4e4d1b6"
+			}
+		}`))),
+	}, nil)
+
+	response := rpc.InvokeFunction("0x8c23f196d8a1bfd103a9dcb1f9ccf0c611377d3b", "tokens", nil, nil)
+	r := response.Result
+	assert.True(t, r.IsIterator())
+
+	_, err := r.Items(nil)
+	assert.NotNil(t, err)
+
+	items, err := r.Items(func(sessionId string, iteratorId string) ([]models.InvokeStack, error) {
+		assert.Equal(t, "a93e1bd3-5a84-4cf0-8a5a-fd9bd4e4d1b6", sessionId)
+		assert.Equal(t, "fcf7b800-192a-488e-9d6e-c2d6df7be7a5", iteratorId)
+		return []models.InvokeStack{{Type: "ByteString", Value: "dG9rZW4x"}}, nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(items))
+	assert.Equal(t, "dG9rZW4x", items[0].Value)
+}
+
+func TestRpcClient_TraverseIterator(t *testing.T) {
+	session := "a93e1bd3-5a84-4cf0-8a5a-fd9bd4e4d1b6"
+	iterator := "fcf7b800-192a-488e-9d6e-c2d6df7be7a5"
+	pages := []string{
+		`[{"type":"ByteString","value":"dG9rZW4x"},{"type":"ByteString","value":"dG9rZW4y"}]`,
+		`[{"type":"ByteString","value":"dG9rZW4z"}]`,
+	}
+	traversed, terminated := 0, false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := RpcRequest{}
+		assert.Nil(t, json.NewDecoder(r.Body).Decode(&request))
+		var result string
+		switch request.Method {
+		case "invokefunction":
+			result = `{"state":"HALT","gasconsumed":"1007390","stack":[{"type":"InteropInterface","interface":"IIterator","id":"` + iterator + `"}],"session":"` + session + `"}`
+		case "traverseiterator":
+			assert.Equal(t, []interface{}{session, iterator, float64(2)}, request.Params)
+			result = `[]`
+			if traversed < len(pages) {
+				result = pages[traversed]
+			}
+			traversed++
+		case "terminatesession":
+			assert.Equal(t, []interface{}{session}, request.Params)
+			result = `true`
+			terminated = true
+		}
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":` + result + `}`))
+	}))
+	defer server.Close()
+
+	rpc := NewRpcClient(server.URL)
+	response := rpc.InvokeFunction("0x8c23f196d8a1bfd103a9dcb1f9ccf0c611377d3b", "tokens", nil, nil)
+	assert.False(t, response.HasError())
+	assert.Equal(t, session, response.Result.Session)
+	assert.Equal(t, []string{iterator}, response.Result.GetIteratorIds())
+
+	// enumerate the tokens by pages of 2 until a short page
+	tokens := []string{}
+	for {
+		page := rpc.TraverseIterator(response.Result.Session, response.Result.GetIteratorIds()[0], 2)
+		assert.False(t, page.HasError())
+		for _, item := range page.Result {
+			tokens = append(tokens, item.Value.(string))
+		}
+		if len(page.Result) < 2 {
+			break
+		}
+	}
+	assert.Equal(t, []string{"dG9rZW4x", "dG9rZW4y", "dG9rZW4z"}, tokens)
+	assert.Equal(t, 2, traversed)
+
+	terminate := rpc.TerminateSession(session)
+	assert.False(t, terminate.HasError())
+	assert.True(t, terminate.Result)
+	assert.True(t, terminated)
+}
+
+func TestIExecutionResult(t *testing.T) {
+	var client = new(HttpClientMock)
+	var rpc = RpcClient{Endpoint: new(url.URL), httpClient: client}
+	client.On("Do", mock.Anything).Return(&http.Response{
+		Body: ioutil.NopCloser(bytes.NewReader([]byte(`{
+			"jsonrpc": "2.0",
+			"id": 1,
+			"result": {
+				"script": "EMAfDAhkZWNpbWFscwwUz3bii9AGLEpHjuNVYQETGfPPpNJBYn1bUg==",
+				"state": "HALT",
+				"gasconsumed": "1007390",
+				"stack": []
+			}
+		}`))),
+	}, nil).Once()
+	client.On("Do", mock.Anything).Return(&http.Response{
+		Body: ioutil.NopCloser(bytes.NewReader([]byte(`{
+			"jsonrpc": "2.0",
+			"id": 1,
+			"result": {
+				"script": "EMAfDAhkZWNpbWFscwwUz3bii9AGLEpHjuNVYQETGfPPpNJBYn1bUg==",
+				"state": "FAULT",
+				"gasconsumed": "2028330",
+				"exception": "error",
+				"stack": []
+			}
+		}`))),
+	}, nil).Once()
+	client.On("Do", mock.Anything).Return(&http.Response{
+		Body: ioutil.NopCloser(bytes.NewReader([]byte(`{
+			"jsonrpc": "2.0",
+			"id": 1,
+			"result": {
+				"txid": "0xd6ea48f1c33defc1815562b3ace4ead99bf33a8ae67b2642cf73c2f192a717e5",
+				"executions": [
+					{
+						"trigger": "Application",
+						"vmstate": "HALT",
+						"gasconsumed": "9007990",
+						"stack": [],
+						"notifications": []
+					}
+				]
+			}
+		}`))),
+	}, nil).Once()
+
+	r1 := rpc.InvokeFunction("0xd2a4cff31913016155e38e474a2c06d08be276cf", "decimals", nil, nil)
+	r2 := rpc.InvokeScript("EMAfDAhkZWNpbWFscwwUz3bii9AGLEpHjuNVYQETGfPPpNJBYn1bUg==", nil)
+	r3 := rpc.GetApplicationLog("0xd6ea48f1c33defc1815562b3ace4ead99bf33a8ae67b2642cf73c2f192a717e5")
+	results := []models.IExecutionResult{&r1.Result, &r2.Result, &r3.Result.Executions[0]}
+	states := []string{"HALT", "FAULT", "HALT"}
+	gas := []int64{1007390, 2028330, 9007990}
+	for i, r := range results {
+		assert.Equal(t, states[i], r.GetState())
+		g, err := r.GetGasConsumed()
+		assert.Nil(t, err)
+		assert.Equal(t, gas[i], g)
+	}
+
+	r := models.InvokeResult{GasConsumed: "0.126"}
+	_, err := r.GetGasConsumed()
+	assert.NotNil(t, err)
+}
+
+func TestInvokeResult_Validate(t *testing.T) {
+	// 70 levels of nested arrays
+	nested := `{"type":"ByteString","value":"AQID"}`
+	for i := 0; i < 70; i++ {
+		nested = `{"type":"Array","value":[` + nested + `]}`
+	}
+	var client = new(HttpClientMock)
+	var rpc = RpcClient{Endpoint: new(url.URL), httpClient: client}
+	client.On("Do", mock.Anything).Return(&http.Response{
+		Body: ioutil.NopCloser(bytes.NewReader([]byte(`{
+			"jsonrpc": "2.0",
+			"id": 1,
+			"result": {
+				"script": "wh8MBnRva2Vuc0EMFHlvdXIgY29udHJhY3QgaGFzaCBoZXJlQWJ9W1I=",
+				"state": "HALT",
+				"gasconsumed": "1007390",
+				"stack": [` + nested + `]
+			}
+		}`))),
+	}, nil)
+
+	response := rpc.InvokeScript("wh8MBnRva2Vuc0EMFHlvdXIgY29udHJhY3QgaGFzaCBoZXJlQWJ9W1I=", nil)
+	assert.False(t, response.HasError())
+	r := response.Result
+
+	err := r.Validate(vm.DefaultExecutionEngineLimits())
+	assert.NotNil(t, err)
+
+	limits := vm.DefaultExecutionEngineLimits()
+	limits.MaxNestingDepth = 100
+	assert.Nil(t, r.Validate(limits))
+
+	limits.MaxStackSize = 50
+	assert.NotNil(t, r.Validate(limits))
+
+	limits = vm.DefaultExecutionEngineLimits()
+	limits.MaxNestingDepth = 100
+	limits.MaxItemSize = 2
+	assert.NotNil(t, r.Validate(limits))
+}
+
+func TestStackItem_ToGo(t *testing.T) {
+	var stack []models.StackItem
+	err := json.Unmarshal([]byte(`[{
+		"type": "Array",
+		"value": [
+			{"type": "Integer", "value": "100"},
+			{"type": "Integer", "value": "100000000000000000000"},
+			{"type": "Boolean", "value": true},
+			{"type": "ByteString", "value": "aGVsbG8="},
+			{"type": "Any"},
+			{
+				"type": "Struct",
+				"value": [{"type": "Buffer", "value": "AQI="}]
+			},
+			{
+				"type": "Map",
+				"value": [
+					{
+						"key": {"type": "ByteString", "value": "bmFtZQ=="},
+						"value": {"type": "ByteString", "value": "bmVv"}
+					},
+					{
+						"key": {"type": "Integer", "value": "9007199254740992"},
+						"value": {"type": "Array", "value": []}
+					}
+				]
+			}
+		]
+	}]`), &stack)
+	assert.Nil(t, err)
+
+	v, err := stack[0].ToGo(models.UTF8Encoding)
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{
+		float64(100),
+		"100000000000000000000",
+		true,
+		"hello",
+		nil,
+		[]interface{}{"\x01\x02"},
+		map[string]interface{}{
+			"name":             "neo",
+			"9007199254740992": []interface{}{},
+		},
+	}, v)
+
+	v, err = stack[0].ToGo(models.HexEncoding)
+	assert.Nil(t, err)
+	items := v.([]interface{})
+	assert.Equal(t, "68656c6c6f", items[3])
+	assert.Equal(t, []interface{}{"0102"}, items[5])
+	assert.Equal(t, "6e656f", items[6].(map[string]interface{})["6e616d65"])
+
+
+	// not valid utf8, rendered in hex
+	invalid := models.StackItem{Type: "ByteString", Value: "/w=="}
+	v, err = invalid.ToGo(models.UTF8Encoding)
+	assert.Nil(t, err)
+	assert.Equal(t, "ff", v)
+
+	// the stack item is not changed
+	_, ok := stack[0].Value.([]interface{})
+	assert.True(t, ok)
+
+	v, err = stack[0].ToGo(models.Base64Encoding)
+	assert.Nil(t, err)
+	out, err := json.Marshal(v)
+	assert.Nil(t, err)
+	assert.Contains(t, string(out), `[100,"100000000000000000000",true,"aGVsbG8=",null`)
+}
+
+func TestStackItem_GetBigInteger(t *testing.T) {
+	var stack []models.StackItem
+	// 2^256 - 1
+	err := json.Unmarshal([]byte(`[
+		{"type": "Integer", "value": "115792089237316195423570985008687907853269984665640564039457584007913129639935"},
+		{"type": "Integer", "value": "-9223372036854775809"},
+		{"type": "Integer", "value": "1.5"},
+		{"type": "ByteString", "value": "aGVsbG8="}
+	]`), &stack)
+	assert.Nil(t, err)
+
+	i, err := stack[0].GetBigInteger()
+	assert.Nil(t, err)
+	expected := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+	assert.Equal(t, 0, expected.Cmp(i))
+
+	i, err = stack[1].GetBigInteger()
+	assert.Nil(t, err)
+	assert.Equal(t, "-9223372036854775809", i.String())
+
+	_, err = stack[2].GetBigInteger()
+	assert.NotNil(t, err)
+	_, err = stack[3].GetBigInteger()
+	assert.NotNil(t, err)
+
+	p, err := stack[0].ToParameter()
+	assert.Nil(t, err)
+	assert.Equal(t, 0, expected.Cmp(p.Value.(*big.Int)))
+}
+
+func TestInvokeResult_GetStackParameters(t *testing.T) {
+	var result models.InvokeResult
+	err := json.Unmarshal([]byte(`{
+		"state": "HALT",
+		"gasconsumed": "2007570",
+		"stack": [
+			{"type": "Integer", "value": "8913620128"},
+			{"type": "ByteString", "value": "aGVsbG8="},
+			{"type": "Boolean", "value": true},
+			{"type": "Pointer", "value": 42},
+			{"type": "InteropInterface", "interface": "IIterator", "id": "fcf7b800-192a-488e-9d6e-c2d6df7be7a5"},
+			{"type": "Array", "value": [
+				{"type": "Integer", "value": "1"},
+				{"type": "Struct", "value": [{"type": "ByteString", "value": "YQ=="}, {"type": "Boolean", "value": false}]}
+			]},
+			{"type": "Map", "value": [
+				{"key": {"type": "ByteString", "value": "YQ=="}, "value": {"type": "Array", "value": [{"type": "Integer", "value": "-2"}]}}
+			]}
+		]
+	}`), &result)
+	assert.Nil(t, err)
+
+	parameters, err := result.GetStackParameters()
+	assert.Nil(t, err)
+	assert.Equal(t, 7, len(parameters))
+	assert.Equal(t, sc.Integer, parameters[0].Type)
+	assert.Equal(t, "8913620128", parameters[0].Value.(*big.Int).String())
+	assert.Equal(t, sc.ByteArray, parameters[1].Type)
+	assert.Equal(t, []byte("hello"), parameters[1].Value)
+	assert.Equal(t, true, parameters[2].Value)
+	assert.Equal(t, "42", parameters[3].Value.(*big.Int).String())
+	assert.Equal(t, sc.InteropInterface, parameters[4].Type)
+
+	// nested array
+	assert.Equal(t, sc.Array, parameters[5].Type)
+	array := parameters[5].Value.([]sc.ContractParameter)
+	assert.Equal(t, 2, len(array))
+	assert.Equal(t, "1", array[0].Value.(*big.Int).String())
+	st := array[1].Value.([]sc.ContractParameter)
+	assert.Equal(t, []byte("a"), st[0].Value)
+	assert.Equal(t, false, st[1].Value)
+
+	// map of an array
+	assert.Equal(t, sc.Map, parameters[6].Type)
+	m := parameters[6].Value.(map[interface{}]interface{})
+	assert.Equal(t, 1, len(m))
+	for k, v := range m {
+		assert.Equal(t, []byte("a"), k.(*sc.ContractParameter).Value)
+		values := v.(sc.ContractParameter).Value.([]sc.ContractParameter)
+		assert.Equal(t, "-2", values[0].Value.(*big.Int).String())
+	}
+
+	result = models.InvokeResult{StackError: "error: invalid operation"}
+	_, err = result.GetStackParameters()
+	assert.NotNil(t, err)
+	result = models.InvokeResult{Stack: []models.StackItem{{Type: "Unknown", Value: "1"}}}
+	_, err = result.GetStackParameters()
+	assert.NotNil(t, err)
+}
+
+func TestInvokeResult_IsTruncated(t *testing.T) {
+	var client = new(HttpClientMock)
+	var rpc = RpcClient{Endpoint: new(url.URL), httpClient: client}
+	client.On("Do", mock.Anything).Return(&http.Response{
+		Body: ioutil.NopCloser(bytes.NewReader([]byte(`{
+			"jsonrpc": "2.0",
+			"id": 1,
+			"result": {
+				"script": "EMAfDAR0ZXN0DBQ7fTcRxvDM+bHcqQPRv6HYlvEjjEFifVtS",
+				"state": "HALT",
+				"gasconsumed": "1007390",
+				"stack": "error: invalid operation"
+			}
+		}`))),
+	}, nil)
+
+	response := rpc.InvokeScript("", nil)
+	assert.False(t, response.HasError())
+	r := response.Result
+	assert.Equal(t, "HALT", r.State)
+	assert.Equal(t, "error: invalid operation", r.StackError)
+	assert.Equal(t, 0, len(r.Stack))
+	assert.True(t, r.IsTruncated())
+
+	// an iterator unwrapped up to the max items
+	var result models.InvokeResult
+	err := json.Unmarshal([]byte(`{
+		"state": "HALT",
+		"stack": [{"type": "InteropInterface", "iterator": [{"type": "Integer", "value": "1"}], "truncated": true}]
+	}`), &result)
+	assert.Nil(t, err)
+	assert.True(t, result.IsTruncated())
+
+	err = json.Unmarshal([]byte(`{"state": "HALT", "stack": [{"type": "Integer", "value": "1"}]}`), &result)
+	assert.Nil(t, err)
+	assert.False(t, result.IsTruncated())
+	assert.Equal(t, "1", result.Stack[0].Value)
+}
@@ -1,16 +1,29 @@
 package rpc
 
 import (
+	"context"
 	"github.com/joeqian10/neo3-gogogo/mpt"
 	"github.com/joeqian10/neo3-gogogo/rpc/models"
 )
 
+type FindStatesResponse struct {
+	RpcResponse
+	ErrorResponse
+	Result models.RpcFoundStates `json:"result"`
+}
+
 type GetProofResponse struct {
 	RpcResponse
 	ErrorResponse
 	Result string `json:"result"`
 }
 
+type GetStateResponse struct {
+	RpcResponse
+	ErrorResponse
+	Result string `json:"result"` // base64
+}
+
 type GetStateHeightResponse struct {
 	RpcResponse
 	ErrorResponse
@@ -29,30 +42,80 @@ type VerifyProofResponse struct {
 	Result string `json:"result"` // base64
 }
 
+// FindStates lists the storage of the contract whose keys start with the prefix under the state root, after the
+// key from. The prefix and from are in base64, and the node limits the count of results if count is not positive
+func (n *RpcClient) FindStates(rootHash, contractScriptHash, prefixInBase64, fromInBase64 string, count int) FindStatesResponse {
+	return n.FindStatesWithContext(context.Background(), rootHash, contractScriptHash, prefixInBase64, fromInBase64, count)
+}
+
+// FindStatesWithContext is FindStates with the context of the request
+func (n *RpcClient) FindStatesWithContext(ctx context.Context, rootHash, contractScriptHash, prefixInBase64, fromInBase64 string, count int) FindStatesResponse {
+	response := FindStatesResponse{}
+	params := []interface{}{rootHash, contractScriptHash, prefixInBase64, fromInBase64}
+	if count > 0 {
+		params = append(params, count)
+	}
+	_ = n.makeRequest(ctx, "findstates", params, &response)
+	return response
+}
+
 func (n *RpcClient) GetProof(rootHash, contractScriptHash, storeKey string) GetProofResponse {
+	return n.GetProofWithContext(context.Background(), rootHash, contractScriptHash, storeKey)
+}
+
+// GetProofWithContext is GetProof with the context of the request
+func (n *RpcClient) GetProofWithContext(ctx context.Context, rootHash, contractScriptHash, storeKey string) GetProofResponse {
 	response := GetProofResponse{}
 	params := []interface{}{rootHash, contractScriptHash, storeKey}
-	_ = n.makeRequest("getproof", params, &response)
+	_ = n.makeRequest(ctx, "getproof", params, &response)
+	return response
+}
+
+// GetState gets the value of the storage key of the contract under the state root, the key is in base64
+func (n *RpcClient) GetState(rootHash, contractScriptHash, storeKey string) GetStateResponse {
+	return n.GetStateWithContext(context.Background(), rootHash, contractScriptHash, storeKey)
+}
+
+// GetStateWithContext is GetState with the context of the request
+func (n *RpcClient) GetStateWithContext(ctx context.Context, rootHash, contractScriptHash, storeKey string) GetStateResponse {
+	response := GetStateResponse{}
+	params := []interface{}{rootHash, contractScriptHash, storeKey}
+	_ = n.makeRequest(ctx, "getstate", params, &response)
 	return response
 }
 
 func (n *RpcClient) GetStateHeight() GetStateHeightResponse {
+	return n.GetStateHeightWithContext(context.Background())
+}
+
+// GetStateHeightWithContext is GetStateHeight with the context of the request
+func (n *RpcClient) GetStateHeightWithContext(ctx context.Context) GetStateHeightResponse {
 	response := GetStateHeightResponse{}
 	params := []interface{}{}
-	_ = n.makeRequest("getstateheight", params, &response)
+	_ = n.makeRequest(ctx, "getstateheight", params, &response)
 	return response
 }
 
 func (n *RpcClient) GetStateRoot(blockHeight uint32) GetStateRootResponse {
+	return n.GetStateRootWithContext(context.Background(), blockHeight)
+}
+
+// GetStateRootWithContext is GetStateRoot with the context of the request
+func (n *RpcClient) GetStateRootWithContext(ctx context.Context, blockHeight uint32) GetStateRootResponse {
 	response := GetStateRootResponse{}
 	params := []interface{}{blockHeight}
-	_ = n.makeRequest("getstateroot", params, &response)
+	_ = n.makeRequest(ctx, "getstateroot", params, &response)
 	return response
 }
 
 func (n *RpcClient) VerifyProof(rootHash string, proofInBase64 string) VerifyProofResponse {
+	return n.VerifyProofWithContext(context.Background(), rootHash, proofInBase64)
+}
+
+// VerifyProofWithContext is VerifyProof with the context of the request
+func (n *RpcClient) VerifyProofWithContext(ctx context.Context, rootHash string, proofInBase64 string) VerifyProofResponse {
 	response := VerifyProofResponse{}
 	params := []interface{}{rootHash, proofInBase64}
-	_ = n.makeRequest("verifyproof", params, &response)
+	_ = n.makeRequest(ctx, "verifyproof", params, &response)
 	return response
 }
@@ -10,6 +10,33 @@ import (
 	"testing"
 )
 
+func TestRpcClient_FindStates(t *testing.T) {
+	var client = new(HttpClientMock)
+	var rpc = RpcClient{Endpoint: new(url.URL), httpClient: client}
+	client.On("Do", mock.Anything).Return(&http.Response{
+		Body: ioutil.NopCloser(bytes.NewReader([]byte(`{
+			"jsonrpc": "2.0",
+			"id": 1,
+			"result": {
+				"firstProof": "CAEAAAAUITjU",
+				"lastProof": "CAEAAAAUITjV",
+				"truncated": true,
+				"results": [
+					{"key": "FCE41A==", "value": "AQ=="},
+					{"key": "FCE41Q==", "value": "Ag=="}
+				]
+			}
+		}`))),
+	}, nil)
+
+	response := rpc.FindStates("", "", "FA==", "", 2)
+	r := response.Result
+	assert.True(t, r.Truncated)
+	assert.Equal(t, 2, len(r.Results))
+	assert.Equal(t, "FCE41Q==", r.Results[1].Key)
+	assert.Equal(t, "Ag==", r.Results[1].Value)
+}
+
 func TestRpcClient_GetProof(t *testing.T) {
 	var client = new(HttpClientMock)
 	var rpc = RpcClient{Endpoint: new(url.URL), httpClient: client}
@@ -26,6 +53,22 @@ func TestRpcClient_GetProof(t *testing.T) {
 	assert.Equal(t, "2q8ACBubhEJKzaXrq9mt5PesW40qC01AEAXQMA6HZIFwAAAAwUgx5XPSf6zOCs4aYsWF30ZLdqvAMFIG5uEQkr", r)
 }
 
+func TestRpcClient_GetState(t *testing.T) {
+	var client = new(HttpClientMock)
+	var rpc = RpcClient{Endpoint: new(url.URL), httpClient: client}
+	client.On("Do", mock.Anything).Return(&http.Response{
+		Body: ioutil.NopCloser(bytes.NewReader([]byte(`{
+			"jsonrpc": "2.0",
+			"id": 1,
+			"result": "AAI="
+		}`))),
+	}, nil)
+
+	response := rpc.GetState("0x7bf925dbd33af0e00d392b92313da59369ed86c82494d0e02040b24faac0a3ca", "0xef4073a0f2b305a38ec4050e4d3d28bc40ea63f5", "Fw==")
+	assert.False(t, response.HasError())
+	assert.Equal(t, "AAI=", response.Result)
+}
+
 func TestRpcClient_GetStateHeight(t *testing.T) {
 	var client = new(HttpClientMock)
 	var rpc = RpcClient{Endpoint: new(url.URL), httpClient: client}
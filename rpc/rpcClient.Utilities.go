@@ -1,6 +1,10 @@
 package rpc
 
-import "github.com/joeqian10/neo3-gogogo/rpc/models"
+import (
+	"context"
+
+	"github.com/joeqian10/neo3-gogogo/rpc/models"
+)
 
 type ListPluginsResponse struct {
 	RpcResponse
@@ -15,15 +19,25 @@ type ValidateAddressResponse struct {
 }
 
 func (n *RpcClient) ListPlugins() ListPluginsResponse {
+	return n.ListPluginsWithContext(context.Background())
+}
+
+// ListPluginsWithContext is ListPlugins with the context of the request
+func (n *RpcClient) ListPluginsWithContext(ctx context.Context) ListPluginsResponse {
 	response := ListPluginsResponse{}
 	params := []interface{}{}
-	_ = n.makeRequest("listplugins", params, &response)
+	_ = n.makeRequest(ctx, "listplugins", params, &response)
 	return response
 }
 
 func (n *RpcClient) ValidateAddress(address string) ValidateAddressResponse {
+	return n.ValidateAddressWithContext(context.Background(), address)
+}
+
+// ValidateAddressWithContext is ValidateAddress with the context of the request
+func (n *RpcClient) ValidateAddressWithContext(ctx context.Context, address string) ValidateAddressResponse {
 	response := ValidateAddressResponse{}
 	params := []interface{}{address}
-	_ = n.makeRequest("validateaddress", params, &response)
+	_ = n.makeRequest(ctx, "validateaddress", params, &response)
 	return response
 }
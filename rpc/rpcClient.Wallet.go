@@ -1,6 +1,10 @@
 package rpc
 
-import "github.com/joeqian10/neo3-gogogo/rpc/models"
+import (
+	"context"
+
+	"github.com/joeqian10/neo3-gogogo/rpc/models"
+)
 
 type CloseWalletResponse struct {
 	RpcResponse
@@ -75,76 +79,131 @@ type SendToAddressResponse struct {
 }
 
 func (n *RpcClient) CloseWallet() CloseWalletResponse {
+	return n.CloseWalletWithContext(context.Background())
+}
+
+// CloseWalletWithContext is CloseWallet with the context of the request
+func (n *RpcClient) CloseWalletWithContext(ctx context.Context) CloseWalletResponse {
 	response := CloseWalletResponse{}
 	params := []interface{}{}
-	_ = n.makeRequest("closewallet", params, &response)
+	_ = n.makeRequest(ctx, "closewallet", params, &response)
 	return response
 }
 
 func (n *RpcClient) DumpPrivKey(address string) DumpPrivKeyResponse {
+	return n.DumpPrivKeyWithContext(context.Background(), address)
+}
+
+// DumpPrivKeyWithContext is DumpPrivKey with the context of the request
+func (n *RpcClient) DumpPrivKeyWithContext(ctx context.Context, address string) DumpPrivKeyResponse {
 	response := DumpPrivKeyResponse{}
 	params := []interface{}{address}
-	_ = n.makeRequest("dumpprivkey", params, &response)
+	_ = n.makeRequest(ctx, "dumpprivkey", params, &response)
 	return response
 }
 
 func (n *RpcClient) GetNewAddress() GetNewAddressResponse {
+	return n.GetNewAddressWithContext(context.Background())
+}
+
+// GetNewAddressWithContext is GetNewAddress with the context of the request
+func (n *RpcClient) GetNewAddressWithContext(ctx context.Context) GetNewAddressResponse {
 	response := GetNewAddressResponse{}
 	params := []interface{}{}
-	_ = n.makeRequest("getnewaddress", params, &response)
+	_ = n.makeRequest(ctx, "getnewaddress", params, &response)
 	return response
 }
 
 func (n *RpcClient) GetWalletBalance(assetId string) GetWalletBalanceResponse {
+	return n.GetWalletBalanceWithContext(context.Background(), assetId)
+}
+
+// GetWalletBalanceWithContext is GetWalletBalance with the context of the request
+func (n *RpcClient) GetWalletBalanceWithContext(ctx context.Context, assetId string) GetWalletBalanceResponse {
 	response := GetWalletBalanceResponse{}
 	params := []interface{}{assetId}
-	_ = n.makeRequest("getwalletbalance", params, &response)
+	_ = n.makeRequest(ctx, "getwalletbalance", params, &response)
 	return response
 }
 
 func (n *RpcClient) GetWalletUnclaimedGas() GetWalletUnclaimedGasResponse {
+	return n.GetWalletUnclaimedGasWithContext(context.Background())
+}
+
+// GetWalletUnclaimedGasWithContext is GetWalletUnclaimedGas with the context of the request
+func (n *RpcClient) GetWalletUnclaimedGasWithContext(ctx context.Context) GetWalletUnclaimedGasResponse {
 	response := GetWalletUnclaimedGasResponse{}
 	params := []interface{}{}
-	_ = n.makeRequest("getwalletunclaimedgas", params, &response)
+	_ = n.makeRequest(ctx, "getwalletunclaimedgas", params, &response)
 	return response
 }
 
 func (n *RpcClient) ImportPrivKey(wif string) ImportPrivKeyResponse {
+	return n.ImportPrivKeyWithContext(context.Background(), wif)
+}
+
+// ImportPrivKeyWithContext is ImportPrivKey with the context of the request
+func (n *RpcClient) ImportPrivKeyWithContext(ctx context.Context, wif string) ImportPrivKeyResponse {
 	response := ImportPrivKeyResponse{}
 	params := []interface{}{wif}
-	_ = n.makeRequest("importprivkey", params, &response)
+	_ = n.makeRequest(ctx, "importprivkey", params, &response)
 	return response
 }
 
 func (n *RpcClient) CalculateNetworkFee(tx string) CalculateNetworkFeeResponse {
+	return n.CalculateNetworkFeeWithContext(context.Background(), tx)
+}
+
+// CalculateNetworkFeeWithContext is CalculateNetworkFee with the context of the request
+func (n *RpcClient) CalculateNetworkFeeWithContext(ctx context.Context, tx string) CalculateNetworkFeeResponse {
 	response := CalculateNetworkFeeResponse{}
 	params := []interface{}{tx}
-	_ = n.makeRequest("calculatenetworkfee", params, &response)
+	_ = n.makeRequest(ctx, "calculatenetworkfee", params, &response)
 	return response
 }
 
 func (n *RpcClient) ListAddress() ListAddressResponse {
+	return n.ListAddressWithContext(context.Background())
+}
+
+// ListAddressWithContext is ListAddress with the context of the request
+func (n *RpcClient) ListAddressWithContext(ctx context.Context) ListAddressResponse {
 	response := ListAddressResponse{}
 	params := []interface{}{}
-	_ = n.makeRequest("listaddress", params, &response)
+	_ = n.makeRequest(ctx, "listaddress", params, &response)
 	return response
 }
 
 func (n *RpcClient) OpenWallet(path string, password string) OpenWalletResponse {
+	return n.OpenWalletWithContext(context.Background(), path, password)
+}
+
+// OpenWalletWithContext is OpenWallet with the context of the request
+func (n *RpcClient) OpenWalletWithContext(ctx context.Context, path string, password string) OpenWalletResponse {
 	response := OpenWalletResponse{}
 	params := []interface{}{path, password}
-	_ = n.makeRequest("openwallet", params, &response)
+	_ = n.makeRequest(ctx, "openwallet", params, &response)
 	return response
 }
 
 func (n *RpcClient) SendFrom(assetId string, from string, to string, amount string) SendFromResponse {
+	return n.SendFromWithContext(context.Background(), assetId, from, to, amount)
+}
+
+// SendFromWithContext is SendFrom with the context of the request
+func (n *RpcClient) SendFromWithContext(ctx context.Context, assetId string, from string, to string, amount string) SendFromResponse {
 	response := SendFromResponse{}
 	params := []interface{}{assetId, from, to, amount}
-	_ = n.makeRequest("sendfrom", params, &response)
+	_ = n.makeRequest(ctx, "sendfrom", params, &response)
 	return response
 }
 
 func (n *RpcClient) SendMany(fromAddress string, outputs []models.RpcTransferOut, signers ...models.RpcSigner) SendManyResponse {
+	return n.SendManyWithContext(context.Background(), fromAddress, outputs, signers...)
+}
+
+// SendManyWithContext is SendMany with the context of the request
+func (n *RpcClient) SendManyWithContext(ctx context.Context, fromAddress string, outputs []models.RpcTransferOut, signers ...models.RpcSigner) SendManyResponse {
 	response := SendManyResponse{}
 	var params []interface{}
 	if fromAddress == "" {
@@ -153,20 +212,30 @@ func (n *RpcClient) SendMany(fromAddress string, outputs []models.RpcTransferOut
 		params = []interface{}{fromAddress, outputs, signers}
 	}
 
-	_ = n.makeRequest("sendfrom", params, &response)
+	_ = n.makeRequest(ctx, "sendfrom", params, &response)
 	return response
 }
 
 func (n *RpcClient) SendToAddress(assetId string, to string, amount string) SendToAddressResponse {
+	return n.SendToAddressWithContext(context.Background(), assetId, to, amount)
+}
+
+// SendToAddressWithContext is SendToAddress with the context of the request
+func (n *RpcClient) SendToAddressWithContext(ctx context.Context, assetId string, to string, amount string) SendToAddressResponse {
 	response := SendToAddressResponse{}
 	params := []interface{}{assetId, to, amount}
-	_ = n.makeRequest("sendtoaddress", params, &response)
+	_ = n.makeRequest(ctx, "sendtoaddress", params, &response)
 	return response
 }
 
 func (n *RpcClient) InvokeContractVerify(scriptHash string, args []models.RpcContractParameter, signers []models.RpcSigner) InvokeResultResponse {
+	return n.InvokeContractVerifyWithContext(context.Background(), scriptHash, args, signers)
+}
+
+// InvokeContractVerifyWithContext is InvokeContractVerify with the context of the request
+func (n *RpcClient) InvokeContractVerifyWithContext(ctx context.Context, scriptHash string, args []models.RpcContractParameter, signers []models.RpcSigner) InvokeResultResponse {
 	response := InvokeResultResponse{}
 	params := []interface{}{scriptHash, args, signers}
-	_ = n.makeRequest("invokecontractverify", params, &response)
+	_ = n.makeRequest(ctx, "invokecontractverify", params, &response)
 	return response
 }
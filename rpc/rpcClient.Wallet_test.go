@@ -2,12 +2,17 @@ package rpc
 
 import (
 	"bytes"
+	"github.com/joeqian10/neo3-gogogo/crypto"
+	"github.com/joeqian10/neo3-gogogo/keys"
 	"github.com/joeqian10/neo3-gogogo/rpc/models"
+	"github.com/joeqian10/neo3-gogogo/sc"
+	"github.com/joeqian10/neo3-gogogo/tx"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"strconv"
 	"testing"
 )
 
@@ -133,6 +138,37 @@ func TestRpcClient_CalculateNetworkFee(t *testing.T) {
 	assert.Equal(t, "2384840", r.NetworkFee)
 }
 
+func TestRpcClient_CalculateNetworkFee_Local(t *testing.T) {
+	pair, err := keys.NewKeyPairFromWIF(keys.KeyCases[0].Wif)
+	assert.Nil(t, err)
+	verificationScript, err := sc.CreateSignatureRedeemScript(pair.PublicKey)
+	assert.Nil(t, err)
+	witness := tx.Witness{InvocationScript: []byte{}, VerificationScript: verificationScript}
+	trx := tx.NewTransaction()
+	trx.SetScript([]byte{byte(sc.PUSH1)})
+	trx.SetSigners([]tx.Signer{{Account: witness.GetScriptHash(), Scopes: tx.CalledByEntry}})
+	trx.SetWitnesses([]tx.Witness{witness})
+
+	// the node charges 159 bytes * 1000 and 30 * (8 + 8 + 0 + 32768) for the verification
+	var client = new(HttpClientMock)
+	var rpc = RpcClient{Endpoint: new(url.URL), httpClient: client}
+	client.On("Do", mock.Anything).Return(&http.Response{
+		Body: ioutil.NopCloser(bytes.NewReader([]byte(`{
+			"jsonrpc": "2.0",
+			"id": 1,
+			"result": {
+				"networkfee": "1142520"
+			}
+		}`))),
+	}, nil)
+	response := rpc.CalculateNetworkFee(crypto.Base64Encode(trx.ToByteArray()))
+	assert.False(t, response.HasError())
+
+	local, err := tx.CalculateNetworkFee(trx, tx.FeePerByte)
+	assert.Nil(t, err)
+	assert.Equal(t, response.Result.NetworkFee, strconv.FormatInt(local, 10))
+}
+
 func TestRpcClient_ListAddress(t *testing.T) {
 	var client = new(HttpClientMock)
 	var rpc = RpcClient{Endpoint: new(url.URL), httpClient: client}
@@ -2,7 +2,10 @@ package rpc
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"runtime"
@@ -15,22 +18,49 @@ type IHttpClient interface {
 }
 
 type RpcClient struct {
-	Endpoint   *url.URL
-	_url       string
-	httpClient IHttpClient
-	userName   string
-	password   string
+	Endpoint    *url.URL
+	_url        string
+	httpClient  IHttpClient
+	userName    string
+	password    string
+	retryPolicy *RetryPolicy
 }
 
+// DefaultHttpTimeout is the timeout of the http client used when none is supplied
+const DefaultHttpTimeout = time.Second * 30
+
 func NewClient(endpoint string) *RpcClient {
+	return NewRpcClientWithHttpClient(endpoint, &http.Client{
+		Timeout: time.Second * 60,
+	})
+}
+
+// NewRpcClient creates a client sending JSON-RPC requests to the endpoint, same as NewClient
+func NewRpcClient(endpoint string) *RpcClient {
+	return NewClient(endpoint)
+}
+
+// NewRpcClientWithHttpClient creates a client sending JSON-RPC requests to the endpoint by the http client,
+// which can be configured with timeouts, TLS config and proxies. If client is nil, a client with
+// DefaultHttpTimeout is used
+func NewRpcClientWithHttpClient(endpoint string, client *http.Client) *RpcClient {
 	u, err := url.Parse(endpoint)
 	if err != nil {
 		return nil
 	}
-	var netClient = &http.Client{
-		Timeout: time.Second * 60,
+	n := &RpcClient{Endpoint: u, _url: endpoint}
+	n.SetHttpClient(client)
+	return n
+}
+
+// SetHttpClient sets the http client sending the requests, a client with DefaultHttpTimeout is used if client is nil
+func (n *RpcClient) SetHttpClient(client *http.Client) {
+	if client == nil {
+		client = &http.Client{
+			Timeout: DefaultHttpTimeout,
+		}
 	}
-	return &RpcClient{Endpoint: u, httpClient: netClient, _url: endpoint}
+	n.httpClient = client
 }
 
 func (n *RpcClient) SetBasicAuth(user string, pass string) {
@@ -42,31 +72,115 @@ func (n *RpcClient) GetUrl() string {
 	return n._url
 }
 
-func (n *RpcClient) makeRequest(method string, params []interface{}, out interface{}) error {
-	request := NewRequest(method, params)
-	jsonValue, _ := json.Marshal(request)
-	req, err := http.NewRequest("POST", n.Endpoint.String(), bytes.NewBuffer(jsonValue))
+// makeRequest sends the request and decodes the response into out. If out embeds ErrorResponse,
+// the transport or decoding error is also set to its NetError. When the context is done, the error
+// wraps the error of the context, so it can be checked by errors.Is
+func (n *RpcClient) makeRequest(ctx context.Context, method string, params []interface{}, out interface{}) error {
+	err := n.doRequest(ctx, method, params, out)
 	if err != nil {
-		return err
+		setNetError(out, err)
 	}
-	if n.userName != "" && n.password != "" {
-		req.SetBasicAuth(n.userName, n.password)
+	return err
+}
+
+// setNetError sets err to the NetError of out if out embeds ErrorResponse
+func setNetError(out interface{}, err error) {
+	if r, ok := out.(interface{ setNetError(error) }); ok {
+		r.setNetError(err)
 	}
-	req.Header.Add("content-type", "application/json")
-	req.Header.Set("Connection", "close")
-	req.Close = true
-	res, err := n.httpClient.Do(req)
+}
+
+func (n *RpcClient) doRequest(ctx context.Context, method string, params []interface{}, out interface{}) error {
+	request := NewRequest(method, params)
+	jsonValue, err := json.Marshal(request)
 	if err != nil {
 		return err
 	}
+	res, err := n.postWithRetry(ctx, method, jsonValue)
+	if err != nil {
+		return contextError(ctx, method, err)
+	}
 	defer res.Body.Close()
 	err = json.NewDecoder(res.Body).Decode(&out)
 	if err != nil {
-		return err
+		if ctx.Err() != nil {
+			return contextError(ctx, method, err)
+		}
+		return fmt.Errorf("failed to decode %s response with status %s: %v", method, res.Status, err)
 	}
 	return nil
 }
 
+// makeBatchRequest sends all requests in one JSON-RPC batch, the responses are decoded into out as an array
+func (n *RpcClient) makeBatchRequest(ctx context.Context, requests []RpcRequest, out interface{}) error {
+	jsonValue, err := json.Marshal(requests)
+	if err != nil {
+		return err
+	}
+	res, err := n.post(ctx, jsonValue)
+	if err != nil {
+		return contextError(ctx, "batch", err)
+	}
+	defer res.Body.Close()
+	return json.NewDecoder(res.Body).Decode(out)
+}
+
+// makeStreamRequest sends the request and passes the response body to decode without buffering it
+func (n *RpcClient) makeStreamRequest(ctx context.Context, method string, params []interface{}, decode func(body io.Reader) error) error {
+	request := NewRequest(method, params)
+	jsonValue, _ := json.Marshal(request)
+	res, err := n.post(ctx, jsonValue)
+	if err != nil {
+		return contextError(ctx, method, err)
+	}
+	defer res.Body.Close()
+	return decode(res.Body)
+}
+
+// postWithRetry posts the request of the method, and retries it by the retry policy of the client on
+// transport errors and retryable status codes. The response of the last attempt is returned
+func (n *RpcClient) postWithRetry(ctx context.Context, method string, jsonValue []byte) (*http.Response, error) {
+	attempts := n.retryPolicy.attempts(method)
+	for attempt := 1; ; attempt++ {
+		res, err := n.post(ctx, jsonValue)
+		if attempt >= attempts || ctx.Err() != nil {
+			return res, err
+		}
+		if err == nil {
+			if !isRetryableStatus(res.StatusCode) {
+				return res, nil
+			}
+			res.Body.Close()
+		}
+		if err = n.retryPolicy.wait(ctx, attempt); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// post sends the json body to the endpoint, the request is aborted when the context is done
+func (n *RpcClient) post(ctx context.Context, jsonValue []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", n.Endpoint.String(), bytes.NewBuffer(jsonValue))
+	if err != nil {
+		return nil, err
+	}
+	if n.userName != "" && n.password != "" {
+		req.SetBasicAuth(n.userName, n.password)
+	}
+	req.Header.Add("content-type", "application/json")
+	req.Header.Set("Connection", "close")
+	req.Close = true
+	return n.httpClient.Do(req)
+}
+
+// contextError wraps the error of the context if it is done, otherwise returns err
+func contextError(ctx context.Context, method string, err error) error {
+	if ctx.Err() != nil {
+		return fmt.Errorf("%s request aborted: %w", method, ctx.Err())
+	}
+	return err
+}
+
 func getRpcName() string {
 	pc := make([]uintptr, 15)
 	n := runtime.Callers(2, pc)
@@ -88,8 +202,13 @@ type GetCrossChainProofResponse struct {
 }
 
 func (n *RpcClient) GetCrossChainProof(blockIndex int, txID string) GetCrossChainProofResponse {
+	return n.GetCrossChainProofWithContext(context.Background(), blockIndex, txID)
+}
+
+// GetCrossChainProofWithContext is GetCrossChainProof with the context of the request
+func (n *RpcClient) GetCrossChainProofWithContext(ctx context.Context, blockIndex int, txID string) GetCrossChainProofResponse {
 	response := GetCrossChainProofResponse{}
 	params := []interface{}{blockIndex, txID}
-	_ = n.makeRequest("getcrossproof", params, &response)
+	_ = n.makeRequest(ctx, "getcrossproof", params, &response)
 	return response
 }
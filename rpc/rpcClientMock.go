@@ -80,6 +80,11 @@ func (r *RpcClientMock) GetCommittee() GetCommitteeResponse {
 	return args.Get(0).(GetCommitteeResponse)
 }
 
+func (r *RpcClientMock) GetNativeContracts() GetNativeContractsResponse {
+	args := r.Called()
+	return args.Get(0).(GetNativeContractsResponse)
+}
+
 // node
 func (r *RpcClientMock) GetConnectionCount() GetConnectionCountResponse {
 	args := r.Called()
@@ -133,17 +138,42 @@ func (r *RpcClientMock) InvokeScript(s string, signers []models.RpcSigner) Invok
 	return args.Get(0).(InvokeResultResponse)
 }
 
+func (r *RpcClientMock) InvokeScriptWithDiagnostics(s string, signers []models.RpcSigner) InvokeResultResponse {
+	args := r.Called(s, signers)
+	return args.Get(0).(InvokeResultResponse)
+}
+
 func (r *RpcClientMock) GetUnclaimedGas(s string) GetUnclaimedGasResponse {
 	args := r.Called(s)
 	return args.Get(0).(GetUnclaimedGasResponse)
 }
 
+func (r *RpcClientMock) TraverseIterator(s1, s2 string, i int) TraverseIteratorResponse {
+	args := r.Called(s1, s2, i)
+	return args.Get(0).(TraverseIteratorResponse)
+}
+
+func (r *RpcClientMock) TerminateSession(s string) TerminateSessionResponse {
+	args := r.Called(s)
+	return args.Get(0).(TerminateSessionResponse)
+}
+
 // state
+func (r *RpcClientMock) FindStates(s1, s2, s3, s4 string, i int) FindStatesResponse {
+	args := r.Called(s1, s2, s3, s4, i)
+	return args.Get(0).(FindStatesResponse)
+}
+
 func (r *RpcClientMock) GetProof(s1, s2, s3 string) GetProofResponse {
 	args := r.Called(s1, s2, s3)
 	return args.Get(0).(GetProofResponse)
 }
 
+func (r *RpcClientMock) GetState(s1, s2, s3 string) GetStateResponse {
+	args := r.Called(s1, s2, s3)
+	return args.Get(0).(GetStateResponse)
+}
+
 func (r *RpcClientMock) GetStateHeight() GetStateHeightResponse {
 	args := r.Called()
 	return args.Get(0).(GetStateHeightResponse)
@@ -1,8 +1,14 @@
 package rpc
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
 	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -24,4 +30,112 @@ func TestNewClient(t *testing.T) {
 	assert.NotNil(t, endpoint)
 	assert.Equal(t, "seed1.ngd.network:20332", endpoint.Host)
 	assert.Equal(t, "http", endpoint.Scheme)
+	assert.Equal(t, time.Second*60, rpcClient.httpClient.(*http.Client).Timeout)
+}
+
+func TestNewRpcClientWithHttpClient(t *testing.T) {
+	rpcClient := NewRpcClientWithHttpClient("http://seed1.ngd.network:20332", nil)
+	assert.NotNil(t, rpcClient)
+	assert.Equal(t, DefaultHttpTimeout, rpcClient.httpClient.(*http.Client).Timeout)
+
+	client := &http.Client{Timeout: time.Second}
+	rpcClient.SetHttpClient(client)
+	assert.Equal(t, client, rpcClient.httpClient)
+
+	assert.Nil(t, NewRpcClientWithHttpClient(":invalid", client))
+}
+
+func TestNewRpcClientWithHttpClient_Timeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":2023}`))
+	}))
+	defer server.Close()
+
+	rpcClient := NewRpcClientWithHttpClient(server.URL, &http.Client{Timeout: time.Millisecond})
+	response := rpcClient.GetBlockCount()
+	assert.True(t, response.HasError())
+	var netErr net.Error
+	assert.True(t, errors.As(response.NetError, &netErr))
+	assert.True(t, netErr.Timeout())
+}
+
+func TestNewRpcClient_HttpServer(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := RpcRequest{}
+		err := json.NewDecoder(r.Body).Decode(&request)
+		assert.Nil(t, err)
+		assert.Equal(t, "application/json", r.Header.Get("content-type"))
+		assert.Equal(t, "2.0", request.JsonRpc)
+		switch request.Method {
+		case "getblockcount":
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":2023}`))
+		case "getblock":
+			assert.Equal(t, []interface{}{float64(409), true}, request.Params)
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"hash":"0x6d1556889c92249da88d3b2cb4b1a9d6ce2e5b3e6c5b9e8cbc3d6e3f0b5d0a4e","index":409,"tx":[]}}`))
+		default:
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32601,"message":"Method not found"}}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewRpcClient(server.URL)
+	count := client.GetBlockCount()
+	assert.False(t, count.HasError())
+	assert.Equal(t, 2023, count.Result)
+
+	block := client.GetBlock("409")
+	assert.False(t, block.HasError())
+	assert.Equal(t, 409, block.Result.Index)
+
+	version := client.GetVersion()
+	assert.True(t, version.HasError())
+	assert.Nil(t, version.NetError)
+	assert.Equal(t, "Method not found", version.GetErrorInfo())
+}
+
+func TestNewRpcClient_NetError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad gateway", http.StatusBadGateway)
+	}))
+	client := NewRpcClient(server.URL)
+	response := client.GetBlockCount()
+	assert.True(t, response.HasError())
+	assert.NotNil(t, response.NetError)
+
+	server.Close()
+	response = client.GetBlockCount()
+	assert.True(t, response.HasError())
+	assert.NotNil(t, response.NetError)
+}
+
+func TestRpcClient_GetBlockWithContext_Cancel(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-time.After(10 * time.Second):
+		}
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"index":409}}`))
+	}))
+	defer server.Close()
+	defer close(release)
+	client := NewRpcClient(server.URL)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+	}()
+	start := time.Now()
+	response := client.GetBlockWithContext(ctx, "409")
+	assert.Less(t, int64(time.Since(start)), int64(2*time.Second))
+	assert.True(t, response.HasError())
+	assert.Equal(t, "", response.Error.Message)
+	assert.True(t, errors.Is(response.NetError, context.Canceled))
+
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	count := client.GetBlockCountWithContext(ctx)
+	assert.True(t, errors.Is(count.NetError, context.DeadlineExceeded))
 }
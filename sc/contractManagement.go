@@ -0,0 +1,61 @@
+package sc
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"github.com/joeqian10/neo3-gogogo/helper"
+)
+
+const ContractManagementId = "0xfffdc93764dbaddd97c48f252a53ea4643faa3fd"
+
+var ContractManagement, _ = helper.UInt160FromString(ContractManagementId)
+
+// GetNefCheckSum reads the checksum at the end of the nef file and verifies it
+func GetNefCheckSum(nef []byte) (uint32, error) {
+	if len(nef) < 4 {
+		return 0, fmt.Errorf("invalid nef file length: %d", len(nef))
+	}
+	checkSum := binary.LittleEndian.Uint32(nef[len(nef)-4:])
+	computed := binary.LittleEndian.Uint32(helper.Hash256(nef[:len(nef)-4]).ToByteArray()[:4])
+	if checkSum != computed {
+		return 0, fmt.Errorf("nef checksum mismatch: %d, computed: %d", checkSum, computed)
+	}
+	return checkSum, nil
+}
+
+// GetContractHash calculates the hash of a contract deployed by sender, with the nef checksum and the name in manifest
+func GetContractHash(sender *helper.UInt160, nefCheckSum uint32, name string) *helper.UInt160 {
+	sb := NewScriptBuilder()
+	sb.Emit(ABORT)
+	sb.EmitPushBytes(sender.ToByteArray())
+	sb.EmitPushInteger(nefCheckSum)
+	sb.EmitPushString(name)
+	b, _ := sb.ToArray()
+	return helper.Hash160(b)
+}
+
+// DeployScript makes the script calling ContractManagement.deploy, and returns it with the hash
+// the contract will have once the sender deploys it
+func DeployScript(sender *helper.UInt160, nef []byte, manifest string, data interface{}) ([]byte, *helper.UInt160, error) {
+	checkSum, err := GetNefCheckSum(nef)
+	if err != nil {
+		return nil, nil, err
+	}
+	var m struct {
+		Name string `json:"name"`
+	}
+	err = json.Unmarshal([]byte(manifest), &m)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid manifest: %s", err)
+	}
+	args := []interface{}{nef, manifest}
+	if data != nil {
+		args = append(args, data)
+	}
+	script, err := MakeScript(ContractManagement, "deploy", args)
+	if err != nil {
+		return nil, nil, err
+	}
+	return script, GetContractHash(sender, checkSum, m.Name), nil
+}
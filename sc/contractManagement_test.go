@@ -0,0 +1,52 @@
+package sc
+
+import (
+	"encoding/binary"
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func makeNef(script []byte) []byte {
+	nef := []byte{0x4e, 0x45, 0x46, 0x33}  // magic
+	nef = append(nef, make([]byte, 64)...) // compiler
+	nef = append(nef, 0x00)                // source
+	nef = append(nef, 0x00)                // reserved
+	nef = append(nef, 0x00)                // tokens
+	nef = append(nef, 0x00, 0x00)          // reserved
+	nef = append(nef, byte(len(script)))   // script
+	nef = append(nef, script...)
+	return append(nef, helper.Hash256(nef).ToByteArray()[:4]...) // checksum
+}
+
+func TestDeployScript(t *testing.T) {
+	sender, _ := helper.UInt160FromString("0x2916eba24e652fa006f3e5eb8f9892d2c3b00399")
+	nef := makeNef([]byte{byte(PUSH1), byte(RET)})
+	manifest := `{"name":"Test","groups":[],"abi":{"methods":[],"events":[]}}`
+
+	script, hash, err := DeployScript(sender, nef, manifest, nil)
+	assert.Nil(t, err)
+
+	checkSum := binary.LittleEndian.Uint32(nef[len(nef)-4:])
+	assert.Equal(t, checkSum, binary.LittleEndian.Uint32(helper.Hash256(nef[:len(nef)-4]).ToByteArray()[:4]))
+	assert.Equal(t, GetContractHash(sender, checkSum, "Test"), hash)
+
+	expected, _ := MakeScript(ContractManagement, "deploy", []interface{}{nef, manifest})
+	assert.Equal(t, expected, script)
+
+	// a different sender gets a different hash
+	_, hash2, err := DeployScript(helper.UInt160Zero, nef, manifest, nil)
+	assert.Nil(t, err)
+	assert.NotEqual(t, hash, hash2)
+}
+
+func TestDeployScript_Invalid(t *testing.T) {
+	sender, _ := helper.UInt160FromString("0x2916eba24e652fa006f3e5eb8f9892d2c3b00399")
+	nef := makeNef([]byte{byte(PUSH1), byte(RET)})
+	nef[len(nef)-1] ^= 0xff
+	_, _, err := DeployScript(sender, nef, `{"name":"Test"}`, nil)
+	assert.NotNil(t, err)
+
+	_, _, err = DeployScript(sender, makeNef([]byte{byte(RET)}), `not json`, nil)
+	assert.NotNil(t, err)
+}
@@ -1,8 +1,16 @@
 package sc
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"math/big"
+	"sort"
 	"strings"
+
+	"github.com/joeqian10/neo3-gogogo/crypto"
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/vm"
 )
 
 type ContractParameterType byte
@@ -66,6 +74,7 @@ func NewContractParameterTypeFromString(s string) (ContractParameterType, error)
 	}
 }
 
+// String returns the name of the type as in neo-cli, e.g. "Map" for 0x22, or empty for an unknown type
 func (cpt ContractParameterType) String() string {
 	var s string
 	switch byte(cpt) {
@@ -89,7 +98,7 @@ func (cpt ContractParameterType) String() string {
 		s = "Signature"
 	case 0x20:
 		s = "Array"
-	case 0x21:
+	case 0x22:
 		s = "Map"
 	case 0x30:
 		s = "InteropInterface"
@@ -100,3 +109,328 @@ func (cpt ContractParameterType) String() string {
 	}
 	return s
 }
+
+// ToStackItemType returns the type of the stack item the parameter is pushed as
+func (cpt ContractParameterType) ToStackItemType() (vm.StackItemType, error) {
+	switch cpt {
+	case Any:
+		return vm.Any, nil
+	case Boolean:
+		return vm.Boolean, nil
+	case Integer:
+		return vm.Integer, nil
+	case ByteArray, String, Hash160, Hash256, PublicKey, Signature:
+		return vm.ByteString, nil
+	case Array:
+		return vm.Array, nil
+	case Map:
+		return vm.Map, nil
+	case InteropInterface:
+		return vm.InteropInterface, nil
+	default:
+		return vm.Any, fmt.Errorf("contract parameter type %s cannot be mapped to a stack item type", cpt.String())
+	}
+}
+
+// NewContractParameterTypeFromStackItemType returns the parameter type a stack item of type sit is decoded to
+func NewContractParameterTypeFromStackItemType(sit vm.StackItemType) (ContractParameterType, error) {
+	switch sit {
+	case vm.Any:
+		return Any, nil
+	case vm.Boolean:
+		return Boolean, nil
+	case vm.Integer:
+		return Integer, nil
+	case vm.ByteString, vm.Buffer:
+		return ByteArray, nil
+	case vm.Array, vm.Struct:
+		return Array, nil
+	case vm.Map:
+		return Map, nil
+	case vm.InteropInterface:
+		return InteropInterface, nil
+	default:
+		return Any, fmt.Errorf("stack item type %s cannot be mapped to a contract parameter type", sit.String())
+	}
+}
+
+type contractParameterJson struct {
+	Type  string      `json:"type"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+type contractParameterMapEntryJson struct {
+	Key   json.RawMessage `json:"key"`
+	Value json.RawMessage `json:"value"`
+}
+
+// MarshalJSON encodes the parameter in the same format as neo-cli, the field order is fixed
+// and map entries are sorted by their encoded keys, so the output is deterministic
+func (p ContractParameter) MarshalJSON() ([]byte, error) {
+	r := contractParameterJson{Type: p.Type.String()}
+	if len(r.Type) == 0 {
+		return nil, fmt.Errorf("invalid param type: %d", p.Type)
+	}
+	if p.Value == nil {
+		return json.Marshal(r)
+	}
+	switch p.Type {
+	case Signature, ByteArray:
+		b, ok := p.Value.([]byte)
+		if !ok {
+			return nil, fmt.Errorf("invalid %s value", p.Type.String())
+		}
+		r.Value = crypto.Base64Encode(b)
+	case Boolean:
+		b, ok := p.Value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("invalid Boolean value")
+		}
+		r.Value = b
+	case Integer:
+		bi, err := toBigInt(p.Value)
+		if err != nil {
+			return nil, err
+		}
+		r.Value = bi.String()
+	case Hash160:
+		u, ok := p.Value.(*helper.UInt160)
+		if !ok {
+			return nil, fmt.Errorf("invalid Hash160 value")
+		}
+		r.Value = "0x" + u.String()
+	case Hash256:
+		u, ok := p.Value.(*helper.UInt256)
+		if !ok {
+			return nil, fmt.Errorf("invalid Hash256 value")
+		}
+		r.Value = "0x" + u.String()
+	case PublicKey:
+		switch v := p.Value.(type) {
+		case []byte:
+			r.Value = helper.BytesToHex(v)
+		case *crypto.ECPoint:
+			r.Value = helper.BytesToHex(v.EncodePoint(true))
+		default:
+			return nil, fmt.Errorf("invalid PublicKey value")
+		}
+	case String:
+		str, ok := p.Value.(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid String value")
+		}
+		r.Value = str
+	case Array:
+		a, ok := p.Value.([]ContractParameter)
+		if !ok {
+			return nil, fmt.Errorf("invalid Array value")
+		}
+		items := make([]json.RawMessage, len(a))
+		for i := range a {
+			b, err := json.Marshal(a[i])
+			if err != nil {
+				return nil, err
+			}
+			items[i] = b
+		}
+		r.Value = items
+	case Map:
+		m, ok := p.Value.(map[interface{}]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid Map value")
+		}
+		entries := make([]contractParameterMapEntryJson, 0, len(m))
+		for k, v := range m {
+			key, err := marshalObject(k)
+			if err != nil {
+				return nil, err
+			}
+			value, err := marshalObject(v)
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, contractParameterMapEntryJson{Key: key, Value: value})
+		}
+		sort.Slice(entries, func(i, j int) bool {
+			return bytes.Compare(entries[i].Key, entries[j].Key) < 0
+		})
+		r.Value = entries
+	case Any, InteropInterface, Void:
+		// no value is encoded
+	default:
+		return nil, fmt.Errorf("invalid param type: %d", p.Type)
+	}
+	return json.Marshal(r)
+}
+
+// UnmarshalJSON decodes the parameter from the format of neo-cli. Integer values are decoded as *big.Int,
+// Hash160 as *helper.UInt160, Hash256 as *helper.UInt256, PublicKey as *crypto.ECPoint, Array as
+// []ContractParameter, and Map as map[interface{}]interface{} with *ContractParameter keys, since a
+// ContractParameter holding a []byte cannot be a map key, and ContractParameter values
+func (p *ContractParameter) UnmarshalJSON(data []byte) error {
+	var r struct {
+		Type  string          `json:"type"`
+		Value json.RawMessage `json:"value"`
+	}
+	if err := json.Unmarshal(data, &r); err != nil {
+		return err
+	}
+	t, err := NewContractParameterTypeFromString(r.Type)
+	if err != nil {
+		return fmt.Errorf("invalid param type: %s", r.Type)
+	}
+	p.Type = t
+	p.Value = nil
+	if len(r.Value) == 0 || string(r.Value) == "null" {
+		return nil
+	}
+	switch t {
+	case Signature, ByteArray:
+		var str string
+		if err = json.Unmarshal(r.Value, &str); err != nil {
+			return fmt.Errorf("invalid %s value: %v", t.String(), err)
+		}
+		p.Value, err = crypto.Base64Decode(str)
+	case Boolean:
+		var b bool
+		if err = json.Unmarshal(r.Value, &b); err != nil {
+			return fmt.Errorf("invalid Boolean value: %v", err)
+		}
+		p.Value = b
+	case Integer:
+		var n json.Number
+		if err = json.Unmarshal(r.Value, &n); err != nil {
+			return fmt.Errorf("invalid Integer value: %v", err)
+		}
+		bi, ok := new(big.Int).SetString(n.String(), 10)
+		if !ok {
+			return fmt.Errorf("invalid Integer value: %s", n.String())
+		}
+		p.Value = bi
+	case Hash160:
+		var str string
+		if err = json.Unmarshal(r.Value, &str); err != nil {
+			return fmt.Errorf("invalid Hash160 value: %v", err)
+		}
+		if len(strings.TrimPrefix(str, "0x")) != 2*helper.UINT160SIZE {
+			return fmt.Errorf("invalid Hash160 value: %s", str)
+		}
+		p.Value, err = helper.UInt160FromString(str)
+	case Hash256:
+		var str string
+		if err = json.Unmarshal(r.Value, &str); err != nil {
+			return fmt.Errorf("invalid Hash256 value: %v", err)
+		}
+		if len(strings.TrimPrefix(str, "0x")) != 2*helper.UINT256SIZE {
+			return fmt.Errorf("invalid Hash256 value: %s", str)
+		}
+		p.Value, err = helper.UInt256FromString(str)
+	case PublicKey:
+		var str string
+		if err = json.Unmarshal(r.Value, &str); err != nil {
+			return fmt.Errorf("invalid PublicKey value: %v", err)
+		}
+		p.Value, err = crypto.NewECPointFromString(str)
+	case String:
+		var str string
+		if err = json.Unmarshal(r.Value, &str); err != nil {
+			return fmt.Errorf("invalid String value: %v", err)
+		}
+		p.Value = str
+	case Array:
+		var a []ContractParameter
+		if err = json.Unmarshal(r.Value, &a); err != nil {
+			return err
+		}
+		p.Value = a
+	case Map:
+		var entries []struct {
+			Key   ContractParameter `json:"key"`
+			Value ContractParameter `json:"value"`
+		}
+		if err = json.Unmarshal(r.Value, &entries); err != nil {
+			return err
+		}
+		m := make(map[interface{}]interface{}, len(entries))
+		for i := range entries {
+			key := entries[i].Key
+			m[&key] = entries[i].Value
+		}
+		p.Value = m
+	case Any, InteropInterface, Void:
+		// the value is ignored
+	}
+	if err != nil {
+		p.Value = nil
+		return fmt.Errorf("invalid %s value: %v", t.String(), err)
+	}
+	return nil
+}
+
+// marshalObject encodes a map key or value, plain go values are encoded as the parameter they are pushed as
+func marshalObject(obj interface{}) ([]byte, error) {
+	p, err := NewContractParameterFromObject(obj)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(p)
+}
+
+// NewContractParameterFromObject wraps a go value into a ContractParameter of the matching type
+func NewContractParameterFromObject(obj interface{}) (ContractParameter, error) {
+	switch v := obj.(type) {
+	case nil:
+		return ContractParameter{Type: Any}, nil
+	case ContractParameter:
+		return v, nil
+	case *ContractParameter:
+		return *v, nil
+	case bool:
+		return ContractParameter{Type: Boolean, Value: v}, nil
+	case string:
+		return ContractParameter{Type: String, Value: v}, nil
+	case []byte:
+		return ContractParameter{Type: ByteArray, Value: v}, nil
+	case *helper.UInt160:
+		return ContractParameter{Type: Hash160, Value: v}, nil
+	case *helper.UInt256:
+		return ContractParameter{Type: Hash256, Value: v}, nil
+	case *crypto.ECPoint:
+		return ContractParameter{Type: PublicKey, Value: v}, nil
+	case big.Int, *big.Int, int8, uint8, int16, uint16, int32, uint32, int64, uint64, int, uint:
+		return ContractParameter{Type: Integer, Value: v}, nil
+	default:
+		return ContractParameter{}, fmt.Errorf("invalid argument type")
+	}
+}
+
+func toBigInt(v interface{}) (*big.Int, error) {
+	switch n := v.(type) {
+	case big.Int:
+		return &n, nil
+	case *big.Int:
+		return n, nil
+	case int8:
+		return big.NewInt(int64(n)), nil
+	case uint8:
+		return big.NewInt(int64(n)), nil
+	case int16:
+		return big.NewInt(int64(n)), nil
+	case uint16:
+		return big.NewInt(int64(n)), nil
+	case int32:
+		return big.NewInt(int64(n)), nil
+	case uint32:
+		return big.NewInt(int64(n)), nil
+	case int64:
+		return big.NewInt(n), nil
+	case uint64:
+		return new(big.Int).SetUint64(n), nil
+	case int:
+		return big.NewInt(int64(n)), nil
+	case uint:
+		return new(big.Int).SetUint64(uint64(n)), nil
+	default:
+		return nil, fmt.Errorf("invalid Integer value")
+	}
+}
@@ -0,0 +1,128 @@
+package sc
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/joeqian10/neo3-gogogo/crypto"
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/vm"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContractParameterType_String(t *testing.T) {
+	assert.Equal(t, "Map", Map.String())
+	assert.Equal(t, "Array", Array.String())
+}
+
+func TestContractParameter_MarshalJSON(t *testing.T) {
+	p := ContractParameter{
+		Type: Array,
+		Value: []ContractParameter{
+			{Type: Hash160, Value: helper.UInt160FromBytes(helper.HexToBytes("28b3adab7269f9c2181db3cb741ebf551930e270"))},
+			{Type: Integer, Value: big.NewInt(-100)},
+			{Type: ByteArray, Value: []byte{0x01, 0x02}},
+			{Type: Boolean, Value: true},
+			{Type: Any},
+		},
+	}
+	b, err := json.Marshal(p)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"type":"Array","value":[{"type":"Hash160","value":"0x70e2301955bf1e74cbb31d18c2f96972abadb328"},{"type":"Integer","value":"-100"},{"type":"ByteArray","value":"AQI="},{"type":"Boolean","value":true},{"type":"Any"}]}`, string(b))
+}
+
+func TestContractParameter_MarshalJSON_Map(t *testing.T) {
+	m := map[interface{}]interface{}{}
+	m["b"] = big.NewInt(2)
+	m["a"] = big.NewInt(1)
+	m[big.NewInt(3)] = "c"
+	m[true] = ContractParameter{Type: ByteArray, Value: []byte{0xff}}
+	p := ContractParameter{Type: Map, Value: m}
+
+	expected := `{"type":"Map","value":[` +
+		`{"key":{"type":"Boolean","value":true},"value":{"type":"ByteArray","value":"/w=="}},` +
+		`{"key":{"type":"Integer","value":"3"},"value":{"type":"String","value":"c"}},` +
+		`{"key":{"type":"String","value":"a"},"value":{"type":"Integer","value":"1"}},` +
+		`{"key":{"type":"String","value":"b"},"value":{"type":"Integer","value":"2"}}]}`
+	for i := 0; i < 20; i++ {
+		b, err := json.Marshal(p)
+		assert.Nil(t, err)
+		assert.Equal(t, expected, string(b))
+	}
+}
+
+func TestContractParameter_UnmarshalJSON(t *testing.T) {
+	cases := []string{
+		`{"type":"Any"}`,
+		`{"type":"Boolean","value":false}`,
+		`{"type":"Integer","value":"-115792089237316195423570985008687907853269984665640564039457584007913129639935"}`,
+		`{"type":"ByteArray","value":"AQI="}`,
+		`{"type":"String","value":"hello"}`,
+		`{"type":"Hash160","value":"0x70e2301955bf1e74cbb31d18c2f96972abadb328"}`,
+		`{"type":"Hash256","value":"0x8e8e8b80e20cb1aa55b0e9a3e6bd3e7e5f46e2d6b2c2dc5f24bd1b5ecb5d9a3a"}`,
+		`{"type":"PublicKey","value":"03b209fd4f53a7170ea4444e0cb0a6bb6a53c2bd016926989cf85f9b0fba17a70c"}`,
+		`{"type":"Signature","value":"` + crypto.Base64Encode(make([]byte, 64)) + `"}`,
+		`{"type":"Array","value":[{"type":"Integer","value":"1"},{"type":"Array","value":[{"type":"String","value":"a"}]}]}`,
+		`{"type":"Map","value":[` +
+			`{"key":{"type":"ByteArray","value":"AQI="},"value":{"type":"Integer","value":"1"}},` +
+			`{"key":{"type":"String","value":"a"},"value":{"type":"Map","value":[{"key":{"type":"Integer","value":"2"},"value":{"type":"Boolean","value":true}}]}}]}`,
+		`{"type":"InteropInterface"}`,
+		`{"type":"Void"}`,
+	}
+	for _, c := range cases {
+		var p ContractParameter
+		assert.Nil(t, json.Unmarshal([]byte(c), &p), c)
+		b, err := json.Marshal(p)
+		assert.Nil(t, err, c)
+		assert.Equal(t, c, string(b))
+	}
+
+	var p ContractParameter
+	assert.Nil(t, json.Unmarshal([]byte(cases[5]), &p))
+	assert.Equal(t, "70e2301955bf1e74cbb31d18c2f96972abadb328", p.Value.(*helper.UInt160).String())
+	assert.Nil(t, json.Unmarshal([]byte(`{"type":"Integer","value":123}`), &p))
+	assert.Equal(t, big.NewInt(123), p.Value)
+
+	// a decoded map can be pushed
+	assert.Nil(t, json.Unmarshal([]byte(cases[10]), &p))
+	sb := NewScriptBuilder()
+	sb.EmitPushParameter(p)
+	_, err := sb.ToArray()
+	assert.Nil(t, err)
+
+	for _, c := range []string{
+		`{"type":"Unknown"}`,
+		`{"type":"Integer","value":"1.5"}`,
+		`{"type":"Hash160","value":"0x01"}`,
+		`{"type":"ByteArray","value":"%%"}`,
+		`{"type":"Boolean","value":"yes"}`,
+		`{"type":"PublicKey","value":"0102"}`,
+	} {
+		assert.NotNil(t, json.Unmarshal([]byte(c), &p), c)
+	}
+}
+
+func TestContractParameterType_ToStackItemType(t *testing.T) {
+	for _, name := range []string{"Any", "Boolean", "Integer", "ByteString", "Array", "Map", "InteropInterface"} {
+		sit, err := vm.NewStackItemTypeFromString(name)
+		assert.Nil(t, err)
+		cpt, err := NewContractParameterTypeFromStackItemType(sit)
+		assert.Nil(t, err)
+		sit2, err := cpt.ToStackItemType()
+		assert.Nil(t, err)
+		assert.Equal(t, name, sit2.String())
+	}
+
+	sit, err := Hash160.ToStackItemType()
+	assert.Nil(t, err)
+	assert.Equal(t, vm.ByteString, sit)
+	cpt, err := NewContractParameterTypeFromStackItemType(vm.Struct)
+	assert.Nil(t, err)
+	assert.Equal(t, Array, cpt)
+
+	_, err = Void.ToStackItemType()
+	assert.NotNil(t, err)
+	_, err = NewContractParameterTypeFromStackItemType(vm.Pointer)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,325 @@
+package sc
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/joeqian10/neo3-gogogo/crypto"
+	"github.com/joeqian10/neo3-gogogo/helper"
+)
+
+// Instruction is a decoded opcode with its operand, the operand of PUSHDATA opcodes excludes the length prefix
+type Instruction struct {
+	Offset  int
+	OpCode  OpCode
+	Operand []byte
+}
+
+// Size returns the length of the instruction in bytes, including the size prefix of the operand
+func (i Instruction) Size() int {
+	return 1 + operandSizePrefixes[i.OpCode] + len(i.Operand)
+}
+
+// operandSizes is the fixed operand size of each opcode, the opcodes not in it have no operand
+var operandSizes = map[OpCode]int{
+	PUSHINT8:   1,
+	PUSHINT16:  2,
+	PUSHINT32:  4,
+	PUSHINT64:  8,
+	PUSHINT128: 16,
+	PUSHINT256: 32,
+	PUSHA:      4,
+	JMP:        1,
+	JMP_L:      4,
+	JMPIF:      1,
+	JMPIF_L:    4,
+	JMPIFNOT:   1,
+	JMPIFNOT_L: 4,
+	JMPEQ:      1,
+	JMPEQ_L:    4,
+	JMPNE:      1,
+	JMPNE_L:    4,
+	JMPGT:      1,
+	JMPGT_L:    4,
+	JMPGE:      1,
+	JMPGE_L:    4,
+	JMPLT:      1,
+	JMPLT_L:    4,
+	JMPLE:      1,
+	JMPLE_L:    4,
+	CALL:       1,
+	CALL_L:     4,
+	CALLT:      2,
+	TRY:        2,
+	TRY_L:      8,
+	ENDTRY:     1,
+	ENDTRY_L:   4,
+	SYSCALL:    4,
+	INITSSLOT:  1,
+	INITSLOT:   2,
+	LDSFLD:     1,
+	STSFLD:     1,
+	LDLOC:      1,
+	STLOC:      1,
+	LDARG:      1,
+	STARG:      1,
+	NEWARRAY_T: 1,
+	ISTYPE:     1,
+	CONVERT:    1,
+}
+
+// operandSizePrefixes is the size of the length prefix of the operand for PUSHDATA opcodes
+var operandSizePrefixes = map[OpCode]int{
+	PUSHDATA1: 1,
+	PUSHDATA2: 2,
+	PUSHDATA4: 4,
+}
+
+// readInstruction decodes the instruction at offset of the script
+func readInstruction(script []byte, offset int) (Instruction, error) {
+	op := OpCode(script[offset])
+	if _, ok := OpCodePrices[op]; !ok {
+		return Instruction{}, fmt.Errorf("unknown opcode 0x%02x at offset %d", byte(op), offset)
+	}
+	pos := offset + 1
+	size := operandSizes[op]
+	if prefix, ok := operandSizePrefixes[op]; ok {
+		if pos+prefix > len(script) {
+			return Instruction{}, fmt.Errorf("truncated operand size prefix at offset %d", offset)
+		}
+		switch prefix {
+		case 1:
+			size = int(script[pos])
+		case 2:
+			size = int(binary.LittleEndian.Uint16(script[pos:]))
+		case 4:
+			s := binary.LittleEndian.Uint32(script[pos:])
+			if uint64(s) > uint64(len(script)) {
+				return Instruction{}, fmt.Errorf("truncated operand at offset %d", offset)
+			}
+			size = int(s)
+		}
+		pos += prefix
+	}
+	if pos+size > len(script) {
+		return Instruction{}, fmt.Errorf("truncated operand at offset %d", offset)
+	}
+	return Instruction{
+		Offset:  offset,
+		OpCode:  op,
+		Operand: script[pos : pos+size],
+	}, nil
+}
+
+// Disassemble decodes all the instructions of the script
+func Disassemble(script []byte) ([]Instruction, error) {
+	instructions := make([]Instruction, 0)
+	reader := NewScriptReader(script)
+	for {
+		offset := reader.GetOffset()
+		op, operand, err := reader.ReadNext()
+		if err == io.EOF {
+			return instructions, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		instructions = append(instructions, Instruction{Offset: offset, OpCode: op, Operand: operand})
+	}
+}
+
+// defaultExecFeeFactor is the exec fee factor of the Policy native contract when it is not changed by the committee
+const defaultExecFeeFactor = 30
+
+// EstimateGas returns the GAS in the smallest unit to run each instruction of the script once with the
+// default exec fee factor. The script is not executed: jumps are not followed, the contracts called are not
+// priced and the dynamic fees such as storage are not included, so the result is a lower bound of the
+// gas consumed by invokescript.
+func EstimateGas(script []byte) (int64, error) {
+	return EstimateGasWithFactor(script, defaultExecFeeFactor)
+}
+
+// EstimateGasWithFactor is EstimateGas with the exec fee factor from Policy.getExecFeeFactor
+func EstimateGasWithFactor(script []byte, execFeeFactor int64) (int64, error) {
+	instructions, err := Disassemble(script)
+	if err != nil {
+		return 0, err
+	}
+	price := int64(0)
+	for i, ins := range instructions {
+		price += OpCodePrices[ins.OpCode]
+		if ins.OpCode != SYSCALL {
+			continue
+		}
+		service, ok := interopServicesByHash[uint(binary.LittleEndian.Uint32(ins.Operand))]
+		if !ok {
+			return 0, fmt.Errorf("unknown interop service 0x%08x at offset %d", binary.LittleEndian.Uint32(ins.Operand), ins.Offset)
+		}
+		if service == System_Crypto_CheckMultisig {
+			// the count of public keys is pushed right before the syscall in a multi-sig contract
+			n, ok := pushedInteger(instructions, i-1)
+			if !ok || n <= 0 {
+				return 0, fmt.Errorf("the count of public keys for %s at offset %d is unknown", service, ins.Offset)
+			}
+			price += InteropPrices[System_Crypto_CheckSig] * n
+			continue
+		}
+		servicePrice, ok := InteropPrices[service]
+		if !ok {
+			return 0, fmt.Errorf("the price of %s at offset %d is unknown", service, ins.Offset)
+		}
+		price += servicePrice
+	}
+	return price * execFeeFactor, nil
+}
+
+// pushedInteger returns the small integer pushed by the instruction at index i
+func pushedInteger(instructions []Instruction, i int) (int64, bool) {
+	if i < 0 {
+		return 0, false
+	}
+	n, ok := instructions[i].GetInteger()
+	if !ok || !n.IsInt64() {
+		return 0, false
+	}
+	return n.Int64(), true
+}
+
+// GetInteger returns the integer pushed by the instruction, which is one of PUSHM1 to PUSH16 and
+// PUSHINT8 to PUSHINT256
+func (i Instruction) GetInteger() (*big.Int, bool) {
+	switch {
+	case i.OpCode >= PUSHM1 && i.OpCode <= PUSH16:
+		return big.NewInt(int64(i.OpCode) - int64(PUSH0)), true
+	case i.OpCode >= PUSHINT8 && i.OpCode <= PUSHINT256:
+		return helper.BigIntFromNeoBytes(i.Operand), true
+	}
+	return nil, false
+}
+
+// DecodePushInteger decodes the integer pushed at the start of the script, it is the inverse of EmitPushBigInt.
+// The size of the push instruction is returned as well, so the script after it can be read on
+func DecodePushInteger(script []byte) (*big.Int, int, error) {
+	if len(script) == 0 {
+		return nil, 0, fmt.Errorf("script is empty")
+	}
+	ins, err := readInstruction(script, 0)
+	if err != nil {
+		return nil, 0, err
+	}
+	n, ok := ins.GetInteger()
+	if !ok {
+		return nil, 0, fmt.Errorf("opcode 0x%02x does not push an integer", byte(ins.OpCode))
+	}
+	return n, ins.Size(), nil
+}
+
+// Validate checks the script is well-formed, it returns an error on unknown opcodes or truncated operands
+func Validate(script []byte) error {
+	_, err := Disassemble(script)
+	return err
+}
+
+// GetCalledContracts returns the hashes of the contracts called by System.Contract.Call in the script,
+// in the order of the first call. The hash must be pushed right before the syscall as EmitDynamicCall does,
+// otherwise an error is returned since the called contract cannot be determined statically
+func GetCalledContracts(script []byte) ([]helper.UInt160, error) {
+	instructions, err := Disassemble(script)
+	if err != nil {
+		return nil, err
+	}
+	contractCall := helper.UInt32ToBytes(uint32(System_Contract_Call.ToInteropMethodHash()))
+	result := make([]helper.UInt160, 0)
+	seen := make(map[helper.UInt160]bool)
+	for i, ins := range instructions {
+		if ins.OpCode != SYSCALL || binary.LittleEndian.Uint32(ins.Operand) != binary.LittleEndian.Uint32(contractCall) {
+			continue
+		}
+		if i == 0 || instructions[i-1].OpCode != PUSHDATA1 || len(instructions[i-1].Operand) != helper.UINT160SIZE {
+			return nil, fmt.Errorf("cannot determine the called contract at offset %d", ins.Offset)
+		}
+		hash := *helper.UInt160FromBytes(instructions[i-1].Operand)
+		if !seen[hash] {
+			seen[hash] = true
+			result = append(result, hash)
+		}
+	}
+	return result, nil
+}
+
+// witnessMethods are the methods of the native and nep17 contracts checking the witness of their first argument
+var witnessMethods = map[string]bool{
+	"transfer":            true,
+	"vote":                true,
+	"registerCandidate":   true,
+	"unregisterCandidate": true,
+	"refuel":              true,
+}
+
+// WitnessCheck is an account whose witness is checked, by the contract called or by the script itself when Contract is nil
+type WitnessCheck struct {
+	Account  helper.UInt160
+	Contract *helper.UInt160
+}
+
+// GetCheckedWitnesses finds the witnesses checked when running the script with a heuristic: the hash or public
+// key pushed right before System.Runtime.CheckWitness, and the first argument of the calls made by EmitDynamicCall
+// to the methods checking the witness of it, such as transfer. The checks inside other contract methods can not be found
+func GetCheckedWitnesses(script []byte) ([]WitnessCheck, error) {
+	instructions, err := Disassemble(script)
+	if err != nil {
+		return nil, err
+	}
+	checkWitness := uint32(System_Runtime_CheckWitness.ToInteropMethodHash())
+	contractCall := uint32(System_Contract_Call.ToInteropMethodHash())
+	result := make([]WitnessCheck, 0)
+	for i, ins := range instructions {
+		if ins.OpCode != SYSCALL {
+			continue
+		}
+		switch binary.LittleEndian.Uint32(ins.Operand) {
+		case checkWitness:
+			if i < 1 {
+				continue
+			}
+			if account, ok := pushedAccount(instructions[i-1]); ok {
+				result = append(result, WitnessCheck{Account: *account})
+			}
+		case contractCall:
+			// arg0, PUSHn, PACK, flags, method, hash, SYSCALL
+			if i < 6 || instructions[i-1].OpCode != PUSHDATA1 || len(instructions[i-1].Operand) != helper.UINT160SIZE ||
+				instructions[i-2].OpCode != PUSHDATA1 || !witnessMethods[string(instructions[i-2].Operand)] ||
+				instructions[i-4].OpCode != PACK {
+				continue
+			}
+			if account, ok := pushedAccount(instructions[i-6]); ok {
+				result = append(result, WitnessCheck{Account: *account, Contract: helper.UInt160FromBytes(instructions[i-1].Operand)})
+			}
+		}
+	}
+	return result, nil
+}
+
+// pushedAccount returns the account of a pushed script hash, or of the standard contract of a pushed public key
+func pushedAccount(ins Instruction) (*helper.UInt160, bool) {
+	if ins.OpCode != PUSHDATA1 {
+		return nil, false
+	}
+	switch len(ins.Operand) {
+	case helper.UINT160SIZE:
+		return helper.UInt160FromBytes(ins.Operand), true
+	case 33:
+		p, err := crypto.NewECPointFromBytes(ins.Operand)
+		if err != nil {
+			return nil, false
+		}
+		script, err := CreateSignatureRedeemScript(p)
+		if err != nil {
+			return nil, false
+		}
+		return crypto.BytesToScriptHash(script), true
+	}
+	return nil, false
+}
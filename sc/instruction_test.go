@@ -0,0 +1,170 @@
+package sc
+
+import (
+	"github.com/joeqian10/neo3-gogogo/crypto"
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"math/big"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	scriptHash, _ := helper.UInt160FromString("0x2916eba24e652fa006f3e5eb8f9892d2c3b00399")
+	script, err := MakeScript(scriptHash, "transfer", []interface{}{scriptHash, scriptHash, 100000000, []byte("data")})
+	assert.Nil(t, err)
+	assert.Nil(t, Validate(script))
+
+	instructions, err := Disassemble(script)
+	assert.Nil(t, err)
+	assert.Equal(t, SYSCALL, instructions[len(instructions)-1].OpCode)
+	assert.Equal(t, 4, len(instructions[len(instructions)-1].Operand))
+
+	// truncated in the syscall operand
+	assert.NotNil(t, Validate(script[:len(script)-2]))
+	// truncated in a PUSHDATA operand
+	assert.NotNil(t, Validate([]byte{byte(PUSHDATA1), 0x05, 0x01, 0x02}))
+	assert.NotNil(t, Validate([]byte{byte(PUSHDATA2), 0x05}))
+	// unknown opcode
+	assert.NotNil(t, Validate([]byte{byte(PUSH1), 0xff}))
+	assert.Nil(t, Validate([]byte{}))
+}
+
+func TestGetCalledContracts(t *testing.T) {
+	h1, _ := helper.UInt160FromString("0x2916eba24e652fa006f3e5eb8f9892d2c3b00399")
+	h2, _ := helper.UInt160FromString("0x8c23f196d8a1bfd103a9dcb1f9ccf0c611377d3b")
+	sb := NewScriptBuilder()
+	sb.EmitDynamicCall(h1, "a", nil)
+	sb.EmitDynamicCall(h2, "b", nil)
+	sb.EmitDynamicCall(h1, "c", nil)
+	script, err := sb.ToArray()
+	assert.Nil(t, err)
+	called, err := GetCalledContracts(script)
+	assert.Nil(t, err)
+	assert.Equal(t, []helper.UInt160{*h1, *h2}, called)
+
+	// the hash is computed at runtime
+	sb = NewScriptBuilder()
+	sb.Emit(DUP)
+	sb.EmitSysCall(System_Contract_Call.ToInteropMethodHash())
+	script, _ = sb.ToArray()
+	_, err = GetCalledContracts(script)
+	assert.NotNil(t, err)
+}
+
+func TestGetCheckedWitnesses(t *testing.T) {
+	gas, _ := helper.UInt160FromString("0xd2a4cff31913016155e38e474a2c06d08be276cf")
+	from, _ := helper.UInt160FromString("0x2916eba24e652fa006f3e5eb8f9892d2c3b00399")
+	to, _ := helper.UInt160FromString("0xedae9b97c72dbec43a7201b6388c24bfd86c71ae")
+	p, _ := crypto.NewECPointFromString("03b209fd4f53a7170ea4444e0cb0a6bb6a53c2bd016926989cf85f9b0fba17a70c")
+	redeemScript, _ := CreateSignatureRedeemScript(p)
+
+	sb := NewScriptBuilder()
+	sb.EmitDynamicCall(gas, "transfer", []interface{}{
+		ContractParameter{Type: Hash160, Value: from},
+		ContractParameter{Type: Hash160, Value: to},
+		ContractParameter{Type: Integer, Value: big.NewInt(1)},
+		ContractParameter{Type: Any},
+	})
+	sb.Emit(ASSERT)
+	sb.EmitDynamicCall(gas, "balanceOf", []interface{}{ContractParameter{Type: Hash160, Value: to}})
+	sb.EmitPushBytes(to.ToByteArray())
+	sb.EmitSysCall(System_Runtime_CheckWitness.ToInteropMethodHash())
+	sb.EmitPushBytes(p.EncodePoint(true))
+	sb.EmitSysCall(System_Runtime_CheckWitness.ToInteropMethodHash())
+	script, err := sb.ToArray()
+	assert.Nil(t, err)
+
+	checks, err := GetCheckedWitnesses(script)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(checks))
+	assert.Equal(t, *from, checks[0].Account)
+	assert.Equal(t, gas, checks[0].Contract)
+	assert.Equal(t, *to, checks[1].Account)
+	assert.Nil(t, checks[1].Contract)
+	assert.Equal(t, *crypto.BytesToScriptHash(redeemScript), checks[2].Account)
+
+	_, err = GetCheckedWitnesses([]byte{0xff})
+	assert.NotNil(t, err)
+}
+
+func TestEstimateGas(t *testing.T) {
+	scriptHash := helper.UInt160FromBytes(helper.HexToBytes("28b3adab7269f9c2181db3cb741ebf551930e270"))
+	script, err := MakeScript(scriptHash, "balanceOf", []interface{}{helper.UInt160Zero})
+	assert.Nil(t, err)
+	gas, err := EstimateGas(script)
+	assert.Nil(t, err)
+	// (PUSHDATA1 + PUSH1 + PACK + PUSH15 + PUSHDATA1 + PUSHDATA1 + System.Contract.Call) * 30
+	assert.Equal(t, int64((8+1+2048+1+8+8+32768)*30), gas)
+
+	// the witness of a standard account costs 983520 in the network fee
+	p1, _ := crypto.NewECPointFromString("03b209fd4f53a7170ea4444e0cb0a6bb6a53c2bd016926989cf85f9b0fba17a70c")
+	p2, _ := crypto.NewECPointFromString("03b7a7f933199f28cc1c48d22a21c78ac3992cf7fceb038a9c670fe55444426619")
+	p3, _ := crypto.NewECPointFromString("02a7bc55fe8684e0119768d104ba30795bdcc86619e864add26156723ed185cd62")
+	sb := NewScriptBuilder()
+	sigInvocation, _ := sb.EmitPushBytes(make([]byte, 64)).ToArray()
+	verification, err := CreateSignatureRedeemScript(p1)
+	assert.Nil(t, err)
+	gas, err = EstimateGas(append(append([]byte{}, sigInvocation...), verification...))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(983520), gas)
+
+	// the witness of a 2-of-3 multi-sig account costs 2950380
+	sb = NewScriptBuilder()
+	multiInvocation, _ := sb.EmitPushBytes(make([]byte, 64)).EmitPushBytes(make([]byte, 64)).ToArray()
+	verification, err = CreateMultiSigRedeemScript(2, []crypto.ECPoint{*p1, *p2, *p3})
+	assert.Nil(t, err)
+	gas, err = EstimateGas(append(multiInvocation, verification...))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2950380), gas)
+
+	gas, err = EstimateGasWithFactor(verification, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1+8*3+1+3*32768), gas)
+
+	// unknown interop service
+	_, err = EstimateGas([]byte{byte(SYSCALL), 0x01, 0x02, 0x03, 0x04})
+	assert.NotNil(t, err)
+	// a known interop service without price
+	sb = NewScriptBuilder()
+	script, _ = sb.EmitSysCall(System_Contract_IsStandard.ToInteropMethodHash()).ToArray()
+	_, err = EstimateGas(script)
+	assert.NotNil(t, err)
+	// the count of public keys is unknown
+	sb = NewScriptBuilder()
+	script, _ = sb.Emit(DUP).EmitSysCall(System_Crypto_CheckMultisig.ToInteropMethodHash()).ToArray()
+	_, err = EstimateGas(script)
+	assert.NotNil(t, err)
+}
+
+func TestDecodePushInteger(t *testing.T) {
+	max, _ := new(big.Int).SetString("57896044618658097711785492504343953926634992332820282019728792003956564819967", 10) // 2^255-1
+	min := new(big.Int).Neg(new(big.Int).Add(max, big.NewInt(1)))
+	values := []*big.Int{
+		big.NewInt(-1), big.NewInt(0), big.NewInt(16), big.NewInt(17), big.NewInt(-2),
+		big.NewInt(127), big.NewInt(128), big.NewInt(-129), big.NewInt(32768), big.NewInt(100000000),
+		big.NewInt(math.MaxInt64), big.NewInt(math.MinInt64), new(big.Int).Lsh(big.NewInt(1), 100), max, min,
+	}
+	for _, explicit := range []bool{false, true} {
+		for _, v := range values {
+			sb := NewScriptBuilder()
+			sb.SetExplicitPushInt(explicit)
+			sb.EmitPushBigInt(v)
+			sb.Emit(RET)
+			script, err := sb.ToArray()
+			assert.Nil(t, err)
+
+			n, size, err := DecodePushInteger(script)
+			assert.Nil(t, err)
+			assert.Equal(t, 0, v.Cmp(n), v.String())
+			assert.Equal(t, len(script)-1, size)
+		}
+	}
+
+	_, _, err := DecodePushInteger([]byte{})
+	assert.NotNil(t, err)
+	_, _, err = DecodePushInteger([]byte{byte(RET)})
+	assert.NotNil(t, err)
+	_, _, err = DecodePushInteger([]byte{byte(PUSHINT32), 0x01})
+	assert.NotNil(t, err)
+}
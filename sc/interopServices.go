@@ -2,6 +2,7 @@ package sc
 
 import (
 	"encoding/binary"
+	"fmt"
 
 	"github.com/joeqian10/neo3-gogogo/crypto"
 )
@@ -21,6 +22,36 @@ var (
 	System_Contract_NativeOnPersist       InteropService = "System.Contract.NativeOnPersist"
 	System_Contract_NativePostPersist     InteropService = "System.Contract.NativePostPersist"
 
+	// -----Runtime-----
+	System_Runtime_Platform               InteropService = "System.Runtime.Platform"
+	System_Runtime_GetNetwork             InteropService = "System.Runtime.GetNetwork"
+	System_Runtime_GetTrigger             InteropService = "System.Runtime.GetTrigger"
+	System_Runtime_GetTime                InteropService = "System.Runtime.GetTime"
+	System_Runtime_GetScriptContainer     InteropService = "System.Runtime.GetScriptContainer"
+	System_Runtime_GetExecutingScriptHash InteropService = "System.Runtime.GetExecutingScriptHash"
+	System_Runtime_GetCallingScriptHash   InteropService = "System.Runtime.GetCallingScriptHash"
+	System_Runtime_GetEntryScriptHash     InteropService = "System.Runtime.GetEntryScriptHash"
+	System_Runtime_GetInvocationCounter   InteropService = "System.Runtime.GetInvocationCounter"
+	System_Runtime_GetNotifications       InteropService = "System.Runtime.GetNotifications"
+	System_Runtime_GasLeft                InteropService = "System.Runtime.GasLeft"
+	System_Runtime_Log                    InteropService = "System.Runtime.Log"
+	System_Runtime_Notify                 InteropService = "System.Runtime.Notify"
+	System_Runtime_BurnGas                InteropService = "System.Runtime.BurnGas"
+	System_Runtime_CheckWitness           InteropService = "System.Runtime.CheckWitness"
+
+	// -----Storage-----
+	System_Storage_GetContext         InteropService = "System.Storage.GetContext"
+	System_Storage_GetReadOnlyContext InteropService = "System.Storage.GetReadOnlyContext"
+	System_Storage_AsReadOnly         InteropService = "System.Storage.AsReadOnly"
+	System_Storage_Get                InteropService = "System.Storage.Get"
+	System_Storage_Find               InteropService = "System.Storage.Find"
+	System_Storage_Put                InteropService = "System.Storage.Put"
+	System_Storage_Delete             InteropService = "System.Storage.Delete"
+
+	// -----Iterator-----
+	System_Iterator_Next  InteropService = "System.Iterator.Next"
+	System_Iterator_Value InteropService = "System.Iterator.Value"
+
 	// -----Crypto-----
 	System_Crypto_CheckSig      InteropService = "System.Crypto.CheckSig"
 	System_Crypto_CheckMultisig InteropService = "System.Crypto.CheckMultisig"
@@ -32,3 +63,82 @@ func (p InteropService) ToInteropMethodHash() uint {
 	u := binary.LittleEndian.Uint32(temp)
 	return uint(u)
 }
+
+// InteropPrices is the fixed price of each interop service before multiplied by the exec fee factor,
+// System.Crypto.CheckMultisig costs the CheckSig price for each public key. System.Contract.IsStandard
+// of the preview networks has no price, EstimateGas fails on it
+var InteropPrices = map[InteropService]int64{
+	System_Contract_Call:                  1 << 15,
+	System_Contract_CallNative:            0,
+	System_Contract_GetCallFlags:          1 << 10,
+	System_Contract_CreateStandardAccount: 1 << 8,
+	System_Contract_CreateMultisigAccount: 1 << 8,
+	System_Contract_NativeOnPersist:       0,
+	System_Contract_NativePostPersist:     0,
+
+	System_Runtime_Platform:               1 << 3,
+	System_Runtime_GetNetwork:             1 << 3,
+	System_Runtime_GetTrigger:             1 << 3,
+	System_Runtime_GetTime:                1 << 3,
+	System_Runtime_GetScriptContainer:     1 << 3,
+	System_Runtime_GetExecutingScriptHash: 1 << 4,
+	System_Runtime_GetCallingScriptHash:   1 << 4,
+	System_Runtime_GetEntryScriptHash:     1 << 4,
+	System_Runtime_GetInvocationCounter:   1 << 4,
+	System_Runtime_GetNotifications:       1 << 12,
+	System_Runtime_GasLeft:                1 << 4,
+	System_Runtime_Log:                    1 << 15,
+	System_Runtime_Notify:                 1 << 15,
+	System_Runtime_BurnGas:                1 << 4,
+	System_Runtime_CheckWitness:           1 << 10,
+
+	System_Storage_GetContext:         1 << 4,
+	System_Storage_GetReadOnlyContext: 1 << 4,
+	System_Storage_AsReadOnly:         1 << 4,
+	System_Storage_Get:                1 << 15,
+	System_Storage_Find:               1 << 15,
+	System_Storage_Put:                1 << 15,
+	System_Storage_Delete:             1 << 15,
+
+	System_Iterator_Next:  1 << 15,
+	System_Iterator_Value: 1 << 4,
+
+	System_Crypto_CheckSig:      1 << 15,
+	System_Crypto_CheckMultisig: 0,
+}
+
+// interopServices is the registry of the known interop services by name
+var interopServices = map[string]InteropService{}
+
+func init() {
+	for _, p := range []InteropService{
+		System_Contract_Call, System_Contract_CallNative, System_Contract_IsStandard, System_Contract_GetCallFlags,
+		System_Contract_CreateStandardAccount, System_Contract_CreateMultisigAccount,
+		System_Contract_NativeOnPersist, System_Contract_NativePostPersist,
+		System_Runtime_Platform, System_Runtime_GetNetwork, System_Runtime_GetTrigger, System_Runtime_GetTime,
+		System_Runtime_GetScriptContainer, System_Runtime_GetExecutingScriptHash, System_Runtime_GetCallingScriptHash,
+		System_Runtime_GetEntryScriptHash, System_Runtime_GetInvocationCounter, System_Runtime_GetNotifications,
+		System_Runtime_GasLeft, System_Runtime_Log, System_Runtime_Notify, System_Runtime_BurnGas,
+		System_Runtime_CheckWitness,
+		System_Storage_GetContext, System_Storage_GetReadOnlyContext, System_Storage_AsReadOnly,
+		System_Storage_Get, System_Storage_Find, System_Storage_Put, System_Storage_Delete,
+		System_Iterator_Next, System_Iterator_Value,
+		System_Crypto_CheckSig, System_Crypto_CheckMultisig,
+	} {
+		interopServices[string(p)] = p
+		interopServicesByHash[p.ToInteropMethodHash()] = p
+	}
+}
+
+// interopServicesByHash is the registry of the known interop services by hash
+var interopServicesByHash = map[uint]InteropService{}
+
+// GetInteropMethodHash returns the hash of the interop service with the name, e.g. "System.Contract.Call",
+// it returns an error if the name is not a known interop service
+func GetInteropMethodHash(name string) (uint, error) {
+	p, ok := interopServices[name]
+	if !ok {
+		return 0, fmt.Errorf("unknown interop service: %s", name)
+	}
+	return p.ToInteropMethodHash(), nil
+}
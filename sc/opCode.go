@@ -210,6 +210,10 @@ const (
 	ISNULL  OpCode = 0xD8 // Returns "true" if the input is "null"; "false" otherwise.
 	ISTYPE  OpCode = 0xD9 // Operand Size = 1. Returns "true" if the top item of the stack is of the specified type; "false" otherwise.
 	CONVERT OpCode = 0xDB // Operand Size = 1. Converts the top item of the stack to the specified type.
+
+	// Extensions
+	ABORTMSG  OpCode = 0xE0 // Pop the top value of the stack as the message, turn the vm state to FAULT immediately, and cannot be caught.
+	ASSERTMSG OpCode = 0xE1 // Pop the top value of the stack as the message and the next value, if it is false, then exit vm execution and set vm state to FAULT.
 )
 
 var OpCodePrices = map[OpCode]int64{
@@ -407,4 +411,7 @@ var OpCodePrices = map[OpCode]int64{
 	ISNULL:  1 << 1,
 	ISTYPE:  1 << 1,
 	CONVERT: 1 << 13,
+
+	ABORTMSG:  0,
+	ASSERTMSG: 1 << 0,
 }
@@ -3,16 +3,31 @@ package sc
 import (
 	"bytes"
 	"fmt"
+	"github.com/joeqian10/neo3-gogogo/crypto"
 	"github.com/joeqian10/neo3-gogogo/helper"
 	"github.com/joeqian10/neo3-gogogo/io"
 	"go/types"
+	"math"
 	"math/big"
+	"reflect"
+	"sort"
 	"strings"
+	"sync"
 )
 
+// ScriptBuilder builds scripts, the Emit methods return the builder so the calls can be chained,
+// e.g. sb.EmitPushInteger(1).EmitPushString("x").Emit(PACK), and the errors are collected and returned by ToArray
 type ScriptBuilder struct {
-	buff *bytes.Buffer
-	errs []error // new design, put all errors in the array
+	buff            *bytes.Buffer
+	errs            []error // new design, put all errors in the array
+	explicitPushInt bool    // push -1..16 with PUSHINT8 instead of PUSHM1..PUSH16
+}
+
+// SetExplicitPushInt makes the builder push every integer with the minimal PUSHINT width,
+// even the small values which are pushed with PUSHM1..PUSH16 by default
+func (sb *ScriptBuilder) SetExplicitPushInt(value bool) *ScriptBuilder {
+	sb.explicitPushInt = value
+	return sb
 }
 
 func (sb *ScriptBuilder) addError(err error) {
@@ -29,6 +44,37 @@ func NewScriptBuilder() ScriptBuilder {
 	}
 }
 
+// Reset empties the script and the errors so the builder can be reused, the script returned by ToArray
+// before shares the buffer and must be copied if it is still needed
+func (sb *ScriptBuilder) Reset() {
+	sb.buff.Reset()
+	sb.errs = sb.errs[:0]
+	sb.explicitPushInt = false
+}
+
+// ScriptBuilderPool holds the builders to reuse, use GetScriptBuilder and PutScriptBuilder
+var ScriptBuilderPool = sync.Pool{
+	New: func() interface{} {
+		sb := NewScriptBuilder()
+		return &sb
+	},
+}
+
+// GetScriptBuilder returns an empty builder from ScriptBuilderPool
+func GetScriptBuilder() *ScriptBuilder {
+	return ScriptBuilderPool.Get().(*ScriptBuilder)
+}
+
+// PutScriptBuilder resets the builder and returns it to ScriptBuilderPool, the script returned by its
+// ToArray must be copied before since the buffer will be reused
+func PutScriptBuilder(sb *ScriptBuilder) {
+	if sb == nil || sb.buff == nil {
+		return
+	}
+	sb.Reset()
+	ScriptBuilderPool.Put(sb)
+}
+
 // Converts the value of this instance to a byte array, pops out all errors.
 func (sb *ScriptBuilder) ToArray() ([]byte, error) {
 	if len(sb.errs) == 0 {
@@ -43,7 +89,7 @@ func (sb *ScriptBuilder) ToArray() ([]byte, error) {
 }
 
 // Emits an "Instruction" with the specified "OpCode" and operand.
-func (sb *ScriptBuilder) Emit(op OpCode, arg ...byte) {
+func (sb *ScriptBuilder) Emit(op OpCode, arg ...byte) *ScriptBuilder {
 	err := sb.buff.WriteByte(byte(op))
 	sb.addError(err)
 
@@ -51,19 +97,21 @@ func (sb *ScriptBuilder) Emit(op OpCode, arg ...byte) {
 		_, err = sb.buff.Write(arg)
 		sb.addError(err)
 	}
+	return sb
 }
 
 // Emits a call "Instruction" with the specified offset.
-func (sb *ScriptBuilder) EmitCall(offset int) {
+func (sb *ScriptBuilder) EmitCall(offset int) *ScriptBuilder {
 	if offset < -128 || offset > 127 {
 		sb.Emit(CALL_L, helper.IntToBytes(offset)...)
 	} else {
 		sb.Emit(CALL, byte(offset))
 	}
+	return sb
 }
 
 // Emits a jump "Instruction" with the specified offset.
-func (sb *ScriptBuilder) EmitJump(op OpCode, offset int) {
+func (sb *ScriptBuilder) EmitJump(op OpCode, offset int) *ScriptBuilder {
 	if op < JMP || op > JMPLE_L {
 		sb.addError(fmt.Errorf("argument out of range: invalid OpCode"))
 	}
@@ -75,36 +123,116 @@ func (sb *ScriptBuilder) EmitJump(op OpCode, offset int) {
 	} else {
 		sb.Emit(op, helper.IntToBytes(offset)...)
 	}
+	return sb
+}
+
+// EmitPushA emits PUSHA pushing a pointer to a position in the script, the offset is a 4-byte signed integer
+// relative to the start of the PUSHA instruction as for the jumps, not to the end of its operand,
+// e.g. sb.EmitPushA(target - sb.Offset()) points to target
+func (sb *ScriptBuilder) EmitPushA(offset int) *ScriptBuilder {
+	if offset < math.MinInt32 || offset > math.MaxInt32 {
+		sb.addError(fmt.Errorf("argument out of range: PUSHA offset %d", offset))
+		return sb
+	}
+	return sb.Emit(PUSHA, helper.IntToBytes(offset)...)
+}
+
+// Len returns the length of the script emitted so far
+func (sb *ScriptBuilder) Len() int {
+	return sb.buff.Len()
+}
+
+// Offset returns the offset of the next instruction to emit, it is the same as Len
+func (sb *ScriptBuilder) Offset() int {
+	return sb.Len()
+}
+
+// PatchJump rewrites the offset of the jump or call instruction emitted at the position at, so that it
+// transfers control to the position target. The offset must fit the operand of the emitted instruction,
+// e.g. a JMP has 1 byte and a JMP_L has 4 bytes.
+func (sb *ScriptBuilder) PatchJump(at int, target int) error {
+	script := sb.buff.Bytes()
+	if at < 0 || at >= len(script) {
+		return fmt.Errorf("position %d out of range", at)
+	}
+	op := OpCode(script[at])
+	if (op < JMP || op > JMPLE_L) && op != CALL && op != CALL_L {
+		return fmt.Errorf("opcode 0x%02x at position %d is not a jump or call", byte(op), at)
+	}
+	width := operandSizes[op]
+	if at+1+width > len(script) {
+		return fmt.Errorf("truncated operand at position %d", at)
+	}
+	offset := target - at
+	if width == 1 {
+		if offset < -128 || offset > 127 {
+			return fmt.Errorf("offset %d does not fit the 1-byte operand of opcode 0x%02x", offset, byte(op))
+		}
+		script[at+1] = byte(offset)
+		return nil
+	}
+	if offset < math.MinInt32 || offset > math.MaxInt32 {
+		return fmt.Errorf("offset %d does not fit the 4-byte operand of opcode 0x%02x", offset, byte(op))
+	}
+	copy(script[at+1:], helper.IntToBytes(offset))
+	return nil
 }
 
 // Emits a push "Instruction" with the specified number.
-func (sb *ScriptBuilder) EmitPushBigInt(number *big.Int) {
-	if number.Cmp(big.NewInt(-1)) >= 0 && number.Cmp(big.NewInt(16)) <= 0 { // >=-1 || <=16
+func (sb *ScriptBuilder) EmitPushBigInt(number *big.Int) *ScriptBuilder {
+	if !IsPushableInteger(number) {
+		sb.addError(fmt.Errorf("argument out of range: number"))
+		return sb
+	}
+	if !sb.explicitPushInt && number.Cmp(big.NewInt(-1)) >= 0 && number.Cmp(big.NewInt(16)) <= 0 { // >=-1 || <=16
 		var b = byte(number.Int64())
 		sb.Emit(PUSH0 + OpCode(b))
-		return
+		return sb
 	}
 	// need little endian
 	data := helper.BigIntToNeoBytes(number) // ToByteArray() returns big-endian
-	if len(data) == 1 {
-		sb.Emit(PUSHINT8, data...)
+	if len(data) <= 1 {
+		sb.Emit(PUSHINT8, padNeoInteger(data, 1)...)
 	} else if len(data) == 2 {
 		sb.Emit(PUSHINT16, data...)
 	} else if len(data) <= 4 {
-		sb.Emit(PUSHINT32, helper.PadRight(data, 4)...)
+		sb.Emit(PUSHINT32, padNeoInteger(data, 4)...)
 	} else if len(data) <= 8 {
-		sb.Emit(PUSHINT64, helper.PadRight(data, 8)...)
+		sb.Emit(PUSHINT64, padNeoInteger(data, 8)...)
 	} else if len(data) <= 16 {
-		sb.Emit(PUSHINT128, helper.PadRight(data, 16)...)
-	} else if len(data) <= 32 {
-		sb.Emit(PUSHINT256, helper.PadRight(data, 32)...)
+		sb.Emit(PUSHINT128, padNeoInteger(data, 16)...)
 	} else {
-		sb.addError(fmt.Errorf("argument out of range: number"))
+		sb.Emit(PUSHINT256, padNeoInteger(data, 32)...)
+	}
+	return sb
+}
+
+// IsPushableInteger checks the number fits in the 256 bits of PUSHINT256, the largest integer of the VM,
+// i.e. -2^255 <= number < 2^255
+func IsPushableInteger(number *big.Int) bool {
+	return number != nil && len(helper.BigIntToNeoBytes(number)) <= 32
+}
+
+// padNeoInteger extends the little-endian two's complement bytes to size, with 0x00 for a
+// non-negative value and 0xff for a negative one, so the value is kept
+func padNeoInteger(data []byte, size int) []byte {
+	if len(data) >= size {
+		return data
+	}
+	var pad byte = 0x00
+	if len(data) > 0 && data[len(data)-1]&0x80 != 0 {
+		pad = 0xff
+	}
+	result := make([]byte, size)
+	copy(result, data)
+	for i := len(data); i < size; i++ {
+		result[i] = pad
 	}
+	return result
 }
 
 // Emits a push "Instruction" with the specified integer type.
-func (sb *ScriptBuilder) EmitPushInteger(num interface{}) {
+func (sb *ScriptBuilder) EmitPushInteger(num interface{}) *ScriptBuilder {
 	switch num.(type) {
 	case int8:
 		sb.EmitPushBigInt(big.NewInt(int64(num.(int8))))
@@ -139,114 +267,319 @@ func (sb *ScriptBuilder) EmitPushInteger(num interface{}) {
 	default:
 		sb.addError(fmt.Errorf("param is not of integer type"))
 	}
+	return sb
 }
 
 // Emits a push "Instruction" with the specified boolean value.
-func (sb *ScriptBuilder) EmitPushBool(data bool) {
+func (sb *ScriptBuilder) EmitPushBool(data bool) *ScriptBuilder {
 	if data {
 		sb.Emit(PUSH1)
 	} else {
 		sb.Emit(PUSH0)
 	}
+	return sb
 }
 
 // Emits a push "Instruction" with the specified data.
-func (sb *ScriptBuilder) EmitPushBytes(data []byte) {
+func (sb *ScriptBuilder) EmitPushBytes(data []byte) *ScriptBuilder {
 	if data == nil {
 		sb.addError(fmt.Errorf("data is empty"))
-		return
+		return sb
+	}
+	if sb.emitPushDataPrefix(len(data)) {
+		sb.buff.Write(data)
+	}
+	return sb
+}
+
+// emitPushDataPrefix emits the PUSHDATA opcode with the data length l, it returns false
+// and adds an error if l does not fit in the uint32 length of PUSHDATA4
+func (sb *ScriptBuilder) emitPushDataPrefix(l int) bool {
+	if uint64(l) > math.MaxUint32 {
+		sb.addError(fmt.Errorf("data length %d exceeds the max length %d", l, uint64(math.MaxUint32)))
+		return false
 	}
-	l := len(data)
 	if l < int(0x100) {
 		sb.Emit(PUSHDATA1)
 		sb.buff.WriteByte(byte(l))
-		sb.buff.Write(data)
 	} else if l < int(0x10000) {
 		sb.Emit(PUSHDATA2)
 		sb.buff.Write(helper.UInt16ToBytes(uint16(l)))
-		sb.buff.Write(data)
 	} else {
 		sb.Emit(PUSHDATA4)
 		sb.buff.Write(helper.UInt32ToBytes(uint32(l)))
-		sb.buff.Write(data)
 	}
+	return true
 }
 
 // Emits a push "Instruction" with the specified "string".
-func (sb *ScriptBuilder) EmitPushString(data string) {
+func (sb *ScriptBuilder) EmitPushString(data string) *ScriptBuilder {
 	sb.EmitPushBytes([]byte(data))
+	return sb
 }
 
 // Emits raw script.
-func (sb *ScriptBuilder) EmitRaw(arg []byte) {
+func (sb *ScriptBuilder) EmitRaw(arg []byte) *ScriptBuilder {
 	if arg != nil {
 		sb.buff.Write(arg)
 	}
+	return sb
 }
 
 // Emits an "Instruction" with "OpCode.SYSCALL".
-func (sb *ScriptBuilder) EmitSysCall(api uint) {
+func (sb *ScriptBuilder) EmitSysCall(api uint) *ScriptBuilder {
 	sb.Emit(SYSCALL, helper.UInt32ToBytes(uint32(api))...)
+	return sb
+}
+
+// EmitSysCallByName emits SYSCALL with the hash of the interop service with the name, e.g. "System.Runtime.CheckWitness",
+// nothing is emitted if the name is not a known interop service
+func (sb *ScriptBuilder) EmitSysCallByName(name string) error {
+	api, err := GetInteropMethodHash(name)
+	if err != nil {
+		return err
+	}
+	sb.EmitSysCall(api)
+	return nil
 }
 
 // below methods are from the extension helper in VM.Helper.cs
 
-func (sb *ScriptBuilder) CreateArray(list []interface{}) {
+func (sb *ScriptBuilder) CreateArray(list []interface{}) *ScriptBuilder {
 	if len(list) == 0 {
 		sb.Emit(NEWARRAY0)
-		return
+		return sb
 	}
 	for i := len(list) - 1; i >= 0; i-- {
 		sb.EmitPushObject(list[i])
 	}
 	sb.EmitPushInteger(len(list))
 	sb.Emit(PACK)
+	return sb
 }
 
-func (sb *ScriptBuilder) CreateMap(m map[interface{}]interface{}) {
+// CreateMap emits a map with the entries sorted by key, so that the same map always gives the same script:
+// booleans first, then integers by numeric value, strings by UTF-8 byte order and byte arrays (including
+// serializable values such as *helper.UInt160) lexicographically. Equal keys of different types are ordered
+// by type name. Keys of any other type, or equal keys of one type, are an error
+func (sb *ScriptBuilder) CreateMap(m map[interface{}]interface{}) *ScriptBuilder {
 	sb.Emit(NEWMAP)
 	if m != nil {
-		for k, v := range m {
+		keys, err := sortMapKeys(m)
+		if err != nil {
+			sb.addError(err)
+			return sb
+		}
+		for _, k := range keys {
 			sb.Emit(DUP)
 			sb.EmitPushObject(k)
-			sb.EmitPushObject(v)
+			sb.EmitPushObject(m[k])
 			sb.Emit(SETITEM)
 		}
 	}
+	return sb
+}
+
+// mapKey is the sort key of a map key, rank orders the kinds of keys, num or raw orders keys of the same kind
+// and typ orders equal keys
+type mapKey struct {
+	key  interface{}
+	rank int
+	num  *big.Int
+	raw  []byte
+	typ  string
+}
+
+func newMapKey(k interface{}) (mapKey, error) {
+	// a parameter is ordered by its value, e.g. the keys of a Map parameter decoded from json
+	switch p := k.(type) {
+	case ContractParameter:
+		return unwrapMapKey(k, p)
+	case *ContractParameter:
+		if p != nil {
+			return unwrapMapKey(k, *p)
+		}
+	}
+	typ := fmt.Sprintf("%T", k)
+	switch v := k.(type) {
+	case bool:
+		if v {
+			return mapKey{key: k, rank: 0, num: big.NewInt(1), typ: typ}, nil
+		}
+		return mapKey{key: k, rank: 0, num: big.NewInt(0), typ: typ}, nil
+	case *big.Int:
+		if v != nil {
+			return mapKey{key: k, rank: 1, num: v, typ: typ}, nil
+		}
+	case string:
+		return mapKey{key: k, rank: 2, raw: []byte(v), typ: typ}, nil
+	case io.ISerializable:
+		if b, err := io.ToArray(v); err == nil {
+			return mapKey{key: k, rank: 3, raw: b, typ: typ}, nil
+		}
+	}
+	rv := reflect.ValueOf(k)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return mapKey{key: k, rank: 1, num: big.NewInt(rv.Int()), typ: typ}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return mapKey{key: k, rank: 1, num: new(big.Int).SetUint64(rv.Uint()), typ: typ}, nil
+	case reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, rv.Len())
+			reflect.Copy(reflect.ValueOf(b), rv)
+			return mapKey{key: k, rank: 3, raw: b, typ: typ}, nil
+		}
+	}
+	return mapKey{}, fmt.Errorf("map key of type %s is not supported", typ)
+}
+
+func unwrapMapKey(k interface{}, p ContractParameter) (mapKey, error) {
+	var key mapKey
+	if b, ok := p.Value.([]byte); ok {
+		key = mapKey{rank: 3, raw: b}
+	} else {
+		var err error
+		if key, err = newMapKey(p.Value); err != nil {
+			return mapKey{}, err
+		}
+	}
+	key.key, key.typ = k, fmt.Sprintf("%T", k)
+	return key, nil
+}
+
+// compareMapKeys compares a and b in the order documented on CreateMap
+func compareMapKeys(a, b mapKey) int {
+	if a.rank != b.rank {
+		if a.rank < b.rank {
+			return -1
+		}
+		return 1
+	}
+	if a.num != nil {
+		if c := a.num.Cmp(b.num); c != 0 {
+			return c
+		}
+	} else if c := bytes.Compare(a.raw, b.raw); c != 0 {
+		return c
+	}
+	return strings.Compare(a.typ, b.typ)
+}
+
+// sortMapKeys returns the keys of m in the order documented on CreateMap
+func sortMapKeys(m map[interface{}]interface{}) ([]interface{}, error) {
+	keys := make([]mapKey, 0, len(m))
+	for k := range m {
+		key, err := newMapKey(k)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	sort.SliceStable(keys, func(i, j int) bool {
+		return compareMapKeys(keys[i], keys[j]) < 0
+	})
+	result := make([]interface{}, len(keys))
+	for i := range keys {
+		if i > 0 && compareMapKeys(keys[i-1], keys[i]) == 0 {
+			return nil, fmt.Errorf("duplicate map key %v of type %s", keys[i].key, keys[i].typ)
+		}
+		result[i] = keys[i].key
+	}
+	return result, nil
 }
 
-func (sb *ScriptBuilder) EmitOpCodes(ops ...OpCode) {
+func (sb *ScriptBuilder) EmitOpCodes(ops ...OpCode) *ScriptBuilder {
 	if ops == nil {
-		return
+		return sb
 	}
 	for _, op := range ops {
 		sb.Emit(op)
 	}
+	return sb
+}
+
+// EmitAssert emits ASSERT, so the script faults if the top item, e.g. the result of a call, is false.
+// If message is not empty, it is pushed and ASSERTMSG is emitted instead, the message is in the fault exception
+func (sb *ScriptBuilder) EmitAssert(message string) *ScriptBuilder {
+	if len(message) == 0 {
+		return sb.Emit(ASSERT)
+	}
+	return sb.EmitPushString(message).Emit(ASSERTMSG)
 }
 
-// Emits an "Instruction" to call a contract.
-func (sb *ScriptBuilder) EmitDynamicCall(scriptHash *helper.UInt160, operation string, args []interface{}) {
-	sb.EmitDynamicCallObj(scriptHash, operation, All, args)
+// Emits an "Instruction" to call a contract with CallFlags All.
+func (sb *ScriptBuilder) EmitDynamicCall(scriptHash *helper.UInt160, operation string, args []interface{}) *ScriptBuilder {
+	sb.EmitDynamicCallWithFlags(scriptHash, operation, All, args)
+	return sb
+}
+
+// MaxMethodNameLength is the length limit of a method name to call
+const MaxMethodNameLength = 255
+
+// EmitDynamicCallObj is the same as EmitDynamicCallWithFlags
+func (sb *ScriptBuilder) EmitDynamicCallObj(scriptHash *helper.UInt160, operation string, flags CallFlags, args []interface{}) *ScriptBuilder {
+	sb.EmitDynamicCallWithFlags(scriptHash, operation, flags, args)
+	return sb
 }
 
-func (sb *ScriptBuilder) EmitDynamicCallObj(scriptHash *helper.UInt160, operation string, flags CallFlags, args []interface{}) {
+// EmitDynamicCallWithFlags emits an "Instruction" to call a contract with the flags, e.g. ReadOnly for a safe method
+// or None to forbid the called contract from calling others
+func (sb *ScriptBuilder) EmitDynamicCallWithFlags(scriptHash *helper.UInt160, operation string, flags CallFlags, args []interface{}) *ScriptBuilder {
+	if err := validateMethodName(operation); err != nil {
+		sb.addError(err)
+		return sb
+	}
 	sb.CreateArray(args)
 	sb.EmitPushObject(flags)
 	sb.EmitPushString(operation)
 	sb.EmitPushSerializable(scriptHash)
 	sb.EmitSysCall(System_Contract_Call.ToInteropMethodHash())
+	return sb
+}
+
+// validateMethodName checks the method name can be called, System.Contract.Call faults on an empty name
+// and a name starting with "_", which is reserved for the methods called by the system
+func validateMethodName(operation string) error {
+	if len(operation) == 0 {
+		return fmt.Errorf("method name is empty")
+	}
+	if len(operation) > MaxMethodNameLength {
+		return fmt.Errorf("method name is too long: %d", len(operation))
+	}
+	if strings.HasPrefix(operation, "_") {
+		return fmt.Errorf("method name starting with _ can not be called: %s", operation)
+	}
+	return nil
 }
 
-func (sb *ScriptBuilder) EmitPushSerializable(data io.ISerializable) {
+func (sb *ScriptBuilder) EmitPushSerializable(data io.ISerializable) *ScriptBuilder {
 	b, e := io.ToArray(data)
 	sb.addError(e)
 	sb.EmitPushBytes(b)
+	return sb
+}
+
+// EmitPushUInt160 pushes the 20 bytes of the hash in little endian, or PUSHNULL if it is nil
+func (sb *ScriptBuilder) EmitPushUInt160(h *helper.UInt160) *ScriptBuilder {
+	if h == nil {
+		return sb.Emit(PUSHNULL)
+	}
+	return sb.EmitPushBytes(h.ToByteArray())
 }
 
-func (sb *ScriptBuilder) EmitPushParameter(param ContractParameter) {
+// EmitPushUInt256 pushes the 32 bytes of the hash in little endian, or PUSHNULL if it is nil
+func (sb *ScriptBuilder) EmitPushUInt256(h *helper.UInt256) *ScriptBuilder {
+	if h == nil {
+		return sb.Emit(PUSHNULL)
+	}
+	return sb.EmitPushBytes(h.ToByteArray())
+}
+
+func (sb *ScriptBuilder) EmitPushParameter(param ContractParameter) *ScriptBuilder {
 	if param.Value == nil {
 		sb.Emit(PUSHNULL)
-		return
+		return sb
 	}
 	switch param.Type {
 	case Signature, ByteArray:
@@ -265,7 +598,11 @@ func (sb *ScriptBuilder) EmitPushParameter(param ContractParameter) {
 		sb.EmitPushSerializable(param.Value.(*helper.UInt256))
 		break
 	case PublicKey:
-		sb.EmitPushBytes(param.Value.([]byte))
+		if point, ok := param.Value.(*crypto.ECPoint); ok {
+			sb.EmitPushBytes(point.EncodePoint(true))
+		} else {
+			sb.EmitPushBytes(param.Value.([]byte))
+		}
 		break
 	case String:
 		sb.EmitPushString(param.Value.(string))
@@ -286,9 +623,17 @@ func (sb *ScriptBuilder) EmitPushParameter(param ContractParameter) {
 		sb.addError(fmt.Errorf("invalid param type"))
 		break
 	}
+	return sb
 }
 
-func (sb *ScriptBuilder) EmitPushObject(obj interface{}) {
+// NullType is the type of Null
+type NullType struct{}
+
+// Null is pushed as PUSHNULL by EmitPushObject, e.g. to pass a null argument to a contract.
+// It replaces go/types.Nil, which is still accepted but deprecated.
+var Null = NullType{}
+
+func (sb *ScriptBuilder) EmitPushObject(obj interface{}) *ScriptBuilder {
 	switch obj.(type) {
 	case CallFlags:
 		sb.EmitPushBigInt(big.NewInt(int64(obj.(CallFlags))))
@@ -302,6 +647,9 @@ func (sb *ScriptBuilder) EmitPushObject(obj interface{}) {
 	case string:
 		sb.EmitPushString(obj.(string))
 		break
+	case []rune:
+		sb.EmitPushString(string(obj.([]rune)))
+		break
 	case big.Int:
 		bi := obj.(big.Int)
 		sb.EmitPushBigInt(&bi)
@@ -309,38 +657,179 @@ func (sb *ScriptBuilder) EmitPushObject(obj interface{}) {
 	case *big.Int:
 		sb.EmitPushBigInt(obj.(*big.Int))
 		break
+	case big.Float, *big.Float:
+		sb.addError(fmt.Errorf("big.Float is not supported, scale the amount by the token decimals to a big.Int, e.g. with big.Float.Int"))
+		break
+	case *helper.UInt160:
+		sb.EmitPushUInt160(obj.(*helper.UInt160))
+		break
+	case *helper.UInt256:
+		sb.EmitPushUInt256(obj.(*helper.UInt256))
+		break
 	case io.ISerializable:
 		sb.EmitPushSerializable(obj.(io.ISerializable))
 		break
 	case int8, uint8, int16, uint16,
 	     int32, uint32, int64, uint64,
-	     int, uint:
+	     int, uint: // rune is int32, so pushed as an integer
 		sb.EmitPushInteger(obj)
 		break
 	case ContractParameter:
 		sb.EmitPushParameter(obj.(ContractParameter))
 		break
-	case types.Nil:
+	case *ContractParameter:
+		sb.EmitPushParameter(*obj.(*ContractParameter))
+		break
+	case ScriptBuilder:
+		other := obj.(ScriptBuilder)
+		sb.emitScriptBuilder(&other)
+		break
+	case *ScriptBuilder:
+		sb.emitScriptBuilder(obj.(*ScriptBuilder))
+		break
+	case NullType:
+		sb.Emit(PUSHNULL)
+		break
+	case types.Nil: // deprecated, pass Null instead
 		sb.Emit(PUSHNULL)
 		break
 	default:
-		sb.addError(fmt.Errorf("invalid argument type"))
+		if !sb.emitPushSerializableSlice(obj) {
+			sb.addError(fmt.Errorf("invalid argument type"))
+		}
 		break
 	}
+	return sb
+}
+
+// emitScriptBuilder splices the script of another builder raw and takes over its errors,
+// a zero value builder is an empty script
+func (sb *ScriptBuilder) emitScriptBuilder(other *ScriptBuilder) {
+	if other == nil {
+		sb.addError(fmt.Errorf("script builder is nil"))
+		return
+	}
+	sb.errs = append(sb.errs, other.errs...)
+	if other.buff != nil {
+		sb.EmitRaw(other.buff.Bytes())
+	}
+}
+
+var serializableType = reflect.TypeOf((*io.ISerializable)(nil)).Elem()
+
+// emitPushSerializableSlice pushes a slice whose elements implement io.ISerializable as an array,
+// it returns false if obj is not such a slice. A nil element is an error
+func (sb *ScriptBuilder) emitPushSerializableSlice(obj interface{}) bool {
+	v := reflect.ValueOf(obj)
+	if v.Kind() != reflect.Slice {
+		return false
+	}
+	elemType := v.Type().Elem()
+	byPointer := false
+	if !elemType.Implements(serializableType) {
+		if !reflect.PtrTo(elemType).Implements(serializableType) {
+			return false
+		}
+		byPointer = true
+	}
+	if v.Len() == 0 {
+		sb.Emit(NEWARRAY0)
+		return true
+	}
+	for i := v.Len() - 1; i >= 0; i-- {
+		e := v.Index(i)
+		if byPointer {
+			e = e.Addr()
+		}
+		if isNilValue(e) {
+			sb.addError(fmt.Errorf("element %d of %s is nil", i, v.Type()))
+			continue
+		}
+		sb.EmitPushSerializable(e.Interface().(io.ISerializable))
+	}
+	sb.EmitPushInteger(v.Len())
+	sb.Emit(PACK)
+	return true
 }
 
-func (sb *ScriptBuilder) EmitSysCallObj(method uint, args ...interface{}) {
+// isNilValue returns whether v is a nil pointer or interface, or an interface holding a nil pointer
+func isNilValue(v reflect.Value) bool {
+	if v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return true
+		}
+		v = v.Elem()
+	}
+	return v.Kind() == reflect.Ptr && v.IsNil()
+}
+
+func (sb *ScriptBuilder) EmitSysCallObj(method uint, args ...interface{}) *ScriptBuilder {
 	if args != nil {
 		for i := len(args) - 1; i >= 0; i-- {
 			sb.EmitPushObject(args[i])
 		}
 	}
 	sb.EmitSysCall(method)
+	return sb
+}
+
+// EmitNotify emits System.Runtime.Notify with the event name and the state packed into an array
+func (sb *ScriptBuilder) EmitNotify(eventName string, state []interface{}) *ScriptBuilder {
+	sb.CreateArray(state)
+	sb.EmitPushString(eventName)
+	sb.EmitSysCall(System_Runtime_Notify.ToInteropMethodHash())
+	return sb
 }
 
-// Generate scripts to call a specific method from a specific contract.
+// EmitBurnGas emits System.Runtime.BurnGas burning the amount of GAS in the smallest unit
+func (sb *ScriptBuilder) EmitBurnGas(amount *big.Int) *ScriptBuilder {
+	if amount == nil || amount.Sign() <= 0 {
+		sb.addError(fmt.Errorf("amount to burn must be positive"))
+		return sb
+	}
+	sb.EmitPushBigInt(amount)
+	sb.EmitSysCall(System_Runtime_BurnGas.ToInteropMethodHash())
+	return sb
+}
+
+// EmitCreateStandardAccount emits System.Contract.CreateStandardAccount, which pushes the script hash of the
+// signature account of the public key
+func (sb *ScriptBuilder) EmitCreateStandardAccount(publicKey *crypto.ECPoint) *ScriptBuilder {
+	if publicKey == nil {
+		sb.addError(fmt.Errorf("public key is nil"))
+		return sb
+	}
+	sb.EmitPushBytes(publicKey.EncodePoint(true))
+	sb.EmitSysCall(System_Contract_CreateStandardAccount.ToInteropMethodHash())
+	return sb
+}
+
+// EmitCreateMultisigAccount emits System.Contract.CreateMultisigAccount, which pushes the script hash of the
+// multi-signature account of m out of the public keys. The public keys are packed into an array in the order
+// given, the account does not depend on it
+func (sb *ScriptBuilder) EmitCreateMultisigAccount(m int, publicKeys []crypto.ECPoint) *ScriptBuilder {
+	if !(m >= 1 && m <= len(publicKeys) && len(publicKeys) <= 1024) {
+		sb.addError(fmt.Errorf("argument exception: %v, %v", m, len(publicKeys)))
+		return sb
+	}
+	for i := len(publicKeys) - 1; i >= 0; i-- {
+		sb.EmitPushBytes(publicKeys[i].EncodePoint(true))
+	}
+	sb.EmitPushInteger(len(publicKeys))
+	sb.Emit(PACK)
+	sb.EmitPushInteger(m)
+	sb.EmitSysCall(System_Contract_CreateMultisigAccount.ToInteropMethodHash())
+	return sb
+}
+
+// Generate scripts to call a specific method from a specific contract with CallFlags All.
 func MakeScript(scriptHash *helper.UInt160, operation string, args []interface{}) ([]byte, error) {
+	return MakeScriptWithFlags(scriptHash, operation, All, args)
+}
+
+// MakeScriptWithFlags generates scripts to call a specific method from a specific contract with the flags.
+func MakeScriptWithFlags(scriptHash *helper.UInt160, operation string, flags CallFlags, args []interface{}) ([]byte, error) {
 	sb := NewScriptBuilder()
-	sb.EmitDynamicCall(scriptHash, operation, args)
+	sb.EmitDynamicCallWithFlags(scriptHash, operation, flags, args)
 	return sb.ToArray()
 }
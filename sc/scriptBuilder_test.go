@@ -2,10 +2,15 @@ package sc
 
 import (
 	"bytes"
+	"math"
 	"math/big"
+	"strconv"
+	"strings"
 	"testing"
 
+	"github.com/joeqian10/neo3-gogogo/crypto"
 	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/io"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -193,6 +198,25 @@ func TestScriptBuilder_EmitPushBytes(t *testing.T) {
 	assert.Equal(t, true, bytes.Equal(expected, b))
 }
 
+func TestScriptBuilder_emitPushDataPrefix(t *testing.T) {
+	sb := NewScriptBuilder()
+	assert.True(t, sb.emitPushDataPrefix(0x10000))
+	b, err := sb.ToArray()
+	assert.Nil(t, err)
+	assert.Equal(t, append([]byte{byte(PUSHDATA4)}, helper.UInt32ToBytes(0x10000)...), b)
+
+	if strconv.IntSize == 32 {
+		t.Skip("int cannot exceed math.MaxUint32")
+	}
+	// the length of a slice larger than 4GiB, no need to allocate it
+	var l uint64 = math.MaxUint32
+	l++
+	sb = NewScriptBuilder()
+	assert.False(t, sb.emitPushDataPrefix(int(l)))
+	_, err = sb.ToArray()
+	assert.NotNil(t, err)
+}
+
 func TestScriptBuilder_EmitPushString(t *testing.T) {
 	// length = 0x4c
 	bs, err := helper.GenerateRandomBytes(0x4c)
@@ -338,6 +362,70 @@ func TestScriptBuilder_CreateMap(t *testing.T) {
 	assert.Equal(t, s1, s2)
 }
 
+func TestScriptBuilder_CreateMap_Deterministic(t *testing.T) {
+	hash := helper.UInt160FromBytes(bytes.Repeat([]byte{0x01}, 20))
+	entries := []struct {
+		k interface{}
+		v interface{}
+	}{
+		{"b", 1}, {"a", 2}, {"ab", 3}, {big.NewInt(-1), 4}, {10, 5}, {uint8(2), 6},
+		{helper.UInt160Zero, 7}, {true, 8}, {"\u00e9", 9}, {hash, 10},
+	}
+	build := func(order []int) []byte {
+		m := map[interface{}]interface{}{}
+		for _, i := range order {
+			m[entries[i].k] = entries[i].v
+		}
+		sb := NewScriptBuilder()
+		sb.CreateMap(m)
+		b, err := sb.ToArray()
+		assert.Nil(t, err)
+		return b
+	}
+	b1 := build([]int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9})
+	for i := 0; i < 20; i++ {
+		assert.Equal(t, b1, build([]int{9, 3, 7, 1, 5, 0, 8, 2, 6, 4}))
+	}
+
+	sorted := []interface{}{true, big.NewInt(-1), uint8(2), 10, "a", "ab", "b", "\u00e9", helper.UInt160Zero, hash}
+	m := map[interface{}]interface{}{}
+	for _, e := range entries {
+		m[e.k] = e.v
+	}
+	keys, err := sortMapKeys(m)
+	assert.Nil(t, err)
+	assert.Equal(t, sorted, keys)
+}
+
+func TestScriptBuilder_CreateMap_EqualKeys(t *testing.T) {
+	// equal keys of different types are ordered by type name
+	m := map[interface{}]interface{}{int64(1): 1, 1: 2, uint8(1): 3, "a": 4, ContractParameter{Type: String, Value: "a"}: 5}
+	keys, err := sortMapKeys(m)
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{1, int64(1), uint8(1), ContractParameter{Type: String, Value: "a"}, "a"}, keys)
+
+	// equal keys of one type have no order
+	_, err = sortMapKeys(map[interface{}]interface{}{big.NewInt(1): 1, big.NewInt(1): 2})
+	assert.NotNil(t, err)
+
+	// keys which can not be ordered by value are rejected
+	type point struct{ x *int }
+	sb := NewScriptBuilder()
+	sb.CreateMap(map[interface{}]interface{}{point{}: 1})
+	_, err = sb.ToArray()
+	assert.NotNil(t, err)
+}
+
+func TestScriptBuilder_EmitPushObject_Null(t *testing.T) {
+	sb := NewScriptBuilder()
+	sb.EmitPushObject(Null)
+	sb.EmitDynamicCall(helper.UInt160Zero, "test", []interface{}{Null, 1})
+	b, err := sb.ToArray()
+	assert.Nil(t, err)
+	assert.Equal(t, byte(PUSHNULL), b[0])
+	assert.Equal(t, []byte{byte(PUSH1), byte(PUSHNULL), byte(PUSH2), byte(PACK)}, b[1:5])
+}
+
 func TestMakeScript(t *testing.T) {
 	b, err := MakeScript(helper.UInt160FromBytes(helper.HexToBytes("28b3adab7269f9c2181db3cb741ebf551930e270")), "balanceOf", []interface{}{helper.UInt160Zero})
 	assert.Nil(t, err)
@@ -345,3 +433,454 @@ func TestMakeScript(t *testing.T) {
 	actual :=  helper.BytesToHex(b)
 	assert.Equal(t, expected, actual)
 }
+
+func TestScriptBuilder_EmitNotify(t *testing.T) {
+	sb := NewScriptBuilder()
+	sb.EmitNotify("Transfer", []interface{}{big.NewInt(1), "a"})
+	b, err := sb.ToArray()
+	assert.Nil(t, err)
+	expected := []byte{byte(PUSHDATA1), 0x01, 'a', byte(PUSH1), byte(PUSH2), byte(PACK), byte(PUSHDATA1), 0x08}
+	expected = append(expected, []byte("Transfer")...)
+	expected = append(expected, byte(SYSCALL))
+	expected = append(expected, helper.UInt32ToBytes(uint32(System_Runtime_Notify.ToInteropMethodHash()))...)
+	assert.Equal(t, expected, b)
+	assert.Equal(t, uint(0x616f0195), System_Runtime_Notify.ToInteropMethodHash())
+}
+
+func TestScriptBuilder_EmitCreateStandardAccount(t *testing.T) {
+	p, _ := crypto.NewECPointFromString("03b209fd4f53a7170ea4444e0cb0a6bb6a53c2bd016926989cf85f9b0fba17a70c")
+	sb := NewScriptBuilder()
+	sb.EmitCreateStandardAccount(p)
+	b, err := sb.ToArray()
+	assert.Nil(t, err)
+	expected := append([]byte{byte(PUSHDATA1), 0x21}, p.EncodePoint(true)...)
+	expected = append(expected, byte(SYSCALL))
+	expected = append(expected, helper.UInt32ToBytes(uint32(System_Contract_CreateStandardAccount.ToInteropMethodHash()))...)
+	assert.Equal(t, expected, b)
+	assert.Equal(t, uint(0x28799cf), System_Contract_CreateStandardAccount.ToInteropMethodHash())
+
+	sb = NewScriptBuilder()
+	sb.EmitCreateStandardAccount(nil)
+	_, err = sb.ToArray()
+	assert.NotNil(t, err)
+}
+
+func TestScriptBuilder_EmitCreateMultisigAccount(t *testing.T) {
+	p1, _ := crypto.NewECPointFromString("03b209fd4f53a7170ea4444e0cb0a6bb6a53c2bd016926989cf85f9b0fba17a70c")
+	p2, _ := crypto.NewECPointFromString("02a7bc55fe8684e0119768d104ba30795bdcc86619e864add26156723ed185cd62")
+	sb := NewScriptBuilder()
+	sb.EmitCreateMultisigAccount(1, []crypto.ECPoint{*p1, *p2})
+	b, err := sb.ToArray()
+	assert.Nil(t, err)
+	expected := append([]byte{byte(PUSHDATA1), 0x21}, p2.EncodePoint(true)...)
+	expected = append(expected, byte(PUSHDATA1), 0x21)
+	expected = append(expected, p1.EncodePoint(true)...)
+	expected = append(expected, byte(PUSH2), byte(PACK), byte(PUSH1), byte(SYSCALL))
+	expected = append(expected, helper.UInt32ToBytes(uint32(System_Contract_CreateMultisigAccount.ToInteropMethodHash()))...)
+	assert.Equal(t, expected, b)
+	assert.Equal(t, uint(0x09e9336a), System_Contract_CreateMultisigAccount.ToInteropMethodHash())
+
+	for _, m := range []int{0, 3} {
+		sb = NewScriptBuilder()
+		sb.EmitCreateMultisigAccount(m, []crypto.ECPoint{*p1, *p2})
+		_, err = sb.ToArray()
+		assert.NotNil(t, err)
+	}
+}
+
+func TestScriptBuilder_EmitPushObject_SerializableSlice(t *testing.T) {
+	u1 := helper.UInt256FromBytes(bytes.Repeat([]byte{0x01}, 32))
+	u2 := helper.UInt256FromBytes(bytes.Repeat([]byte{0x02}, 32))
+	expected := []byte{byte(PUSHDATA1), 0x20}
+	expected = append(expected, u2.ToByteArray()...)
+	expected = append(expected, byte(PUSHDATA1), 0x20)
+	expected = append(expected, u1.ToByteArray()...)
+	expected = append(expected, byte(PUSH2), byte(PACK))
+
+	sb := NewScriptBuilder()
+	sb.EmitPushObject([]*helper.UInt256{u1, u2})
+	b, err := sb.ToArray()
+	assert.Nil(t, err)
+	assert.Equal(t, expected, b)
+
+	sb = NewScriptBuilder()
+	sb.EmitPushObject([]helper.UInt256{*u1, *u2})
+	b, err = sb.ToArray()
+	assert.Nil(t, err)
+	assert.Equal(t, expected, b)
+
+	sb = NewScriptBuilder()
+	sb.EmitPushObject([]*helper.UInt256{})
+	b, err = sb.ToArray()
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{byte(NEWARRAY0)}, b)
+
+	sb = NewScriptBuilder()
+	sb.EmitPushObject([]float64{1})
+	_, err = sb.ToArray()
+	assert.NotNil(t, err)
+
+	// nil elements
+	sb = NewScriptBuilder()
+	sb.EmitPushObject([]*helper.UInt256{u1, nil})
+	_, err = sb.ToArray()
+	assert.NotNil(t, err)
+
+	var nilHash *helper.UInt160
+	sb = NewScriptBuilder()
+	sb.EmitPushObject([]io.ISerializable{u1, nil, nilHash})
+	_, err = sb.ToArray()
+	assert.NotNil(t, err)
+}
+
+func TestScriptBuilder_EmitPushObject_ScriptBuilder(t *testing.T) {
+	sub := NewScriptBuilder()
+	sub.EmitPushInteger(1)
+	sub.EmitPushInteger(2)
+	sub.Emit(ADD)
+
+	sb := NewScriptBuilder()
+	sb.Emit(NOP)
+	sb.EmitPushObject(sub)
+	sb.EmitPushObject(&sub)
+	b, err := sb.ToArray()
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{byte(NOP), byte(PUSH1), byte(PUSH2), byte(ADD), byte(PUSH1), byte(PUSH2), byte(ADD)}, b)
+
+	// errors of the sub builder are merged
+	bad := NewScriptBuilder()
+	bad.EmitPushBytes(nil)
+	sb = NewScriptBuilder()
+	sb.EmitPushObject(&bad)
+	_, err = sb.ToArray()
+	assert.NotNil(t, err)
+
+	// a zero value builder is empty, a nil one is an error
+	sb = NewScriptBuilder()
+	sb.Emit(NOP)
+	sb.EmitPushObject(ScriptBuilder{})
+	sb.EmitPushObject(&ScriptBuilder{})
+	b, err = sb.ToArray()
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{byte(NOP)}, b)
+
+	var nilBuilder *ScriptBuilder
+	sb = NewScriptBuilder()
+	sb.EmitPushObject(nilBuilder)
+	_, err = sb.ToArray()
+	assert.NotNil(t, err)
+}
+
+func TestScriptBuilder_EmitPushObject_BigFloat(t *testing.T) {
+	sb := NewScriptBuilder()
+	sb.EmitPushObject(big.NewFloat(1.5))
+	_, err := sb.ToArray()
+	assert.EqualError(t, err, "big.Float is not supported, scale the amount by the token decimals to a big.Int, e.g. with big.Float.Int")
+
+	sb = NewScriptBuilder()
+	sb.EmitPushObject(*big.NewFloat(1.5))
+	_, err = sb.ToArray()
+	assert.NotNil(t, err)
+}
+
+func TestScriptBuilder_EmitPushObject_Rune(t *testing.T) {
+	sb := NewScriptBuilder()
+	sb.EmitPushObject('a')
+	b, err := sb.ToArray()
+	assert.Nil(t, err)
+	expected := NewScriptBuilder()
+	expected.EmitPushInteger(97)
+	e, _ := expected.ToArray()
+	assert.Equal(t, e, b)
+
+	sb = NewScriptBuilder()
+	sb.EmitPushObject([]rune("héllo"))
+	b, err = sb.ToArray()
+	assert.Nil(t, err)
+	expected = NewScriptBuilder()
+	expected.EmitPushString("héllo")
+	e, _ = expected.ToArray()
+	assert.Equal(t, e, b)
+}
+
+func TestScriptBuilder_SetExplicitPushInt(t *testing.T) {
+	sb := NewScriptBuilder()
+	sb.EmitPushInteger(5)
+	b, err := sb.ToArray()
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{byte(PUSH5)}, b)
+
+	sb = NewScriptBuilder()
+	sb.SetExplicitPushInt(true)
+	sb.EmitPushInteger(5)
+	sb.EmitPushInteger(0)
+	sb.EmitPushInteger(-1)
+	sb.EmitPushInteger(1000)
+	b, err = sb.ToArray()
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{byte(PUSHINT8), 0x05, byte(PUSHINT8), 0x00, byte(PUSHINT8), 0xff, byte(PUSHINT16), 0xe8, 0x03}, b)
+}
+
+func TestScriptBuilder_EmitDynamicCall_InvalidOperation(t *testing.T) {
+	hash, _ := helper.UInt160FromString("0xd2a4cff31913016155e38e474a2c06d08be276cf")
+	for _, operation := range []string{"", "_deploy", strings.Repeat("a", MaxMethodNameLength+1)} {
+		sb := NewScriptBuilder()
+		sb.EmitDynamicCall(hash, operation, nil)
+		b, err := sb.ToArray()
+		assert.NotNil(t, err)
+		assert.Equal(t, 0, len(b))
+	}
+	_, err := MakeScript(hash, "", nil)
+	assert.EqualError(t, err, "method name is empty")
+}
+
+func TestScriptBuilder_EmitPushBigInt_Negative(t *testing.T) {
+	bits200, _ := new(big.Int).SetString("-1606938044258990275541962092341162602522202993782792835301376", 10) // -2^200
+	bits200.Add(bits200, big.NewInt(12345))
+	cases := []struct {
+		value  *big.Int
+		opCode OpCode
+		size   int
+	}{
+		{big.NewInt(-1), PUSHINT8, 1},
+		{big.NewInt(-128), PUSHINT8, 1},
+		{big.NewInt(-129), PUSHINT16, 2},
+		{big.NewInt(-8388609), PUSHINT32, 4}, // 3 bytes padded to 4
+		{big.NewInt(-549755813889), PUSHINT64, 8},
+		{big.NewInt(math.MinInt64), PUSHINT64, 8},
+		{new(big.Int).Sub(big.NewInt(math.MinInt64), big.NewInt(1)), PUSHINT128, 16},
+		{bits200, PUSHINT256, 32},
+		{big.NewInt(8388608), PUSHINT32, 4},
+	}
+	for _, c := range cases {
+		sb := NewScriptBuilder()
+		sb.SetExplicitPushInt(true)
+		sb.EmitPushBigInt(c.value)
+		b, err := sb.ToArray()
+		assert.Nil(t, err)
+		assert.Equal(t, 1+c.size, len(b), c.value.String())
+		assert.Equal(t, byte(c.opCode), b[0], c.value.String())
+		assert.Equal(t, 0, c.value.Cmp(helper.BigIntFromNeoBytes(b[1:])), c.value.String())
+	}
+}
+
+func TestIsPushableInteger(t *testing.T) {
+	max := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 255), big.NewInt(1)) // 2^255-1
+	min := new(big.Int).Neg(new(big.Int).Lsh(big.NewInt(1), 255))                // -2^255
+	assert.True(t, IsPushableInteger(max))
+	assert.True(t, IsPushableInteger(min))
+	assert.False(t, IsPushableInteger(new(big.Int).Add(max, big.NewInt(1))))
+	assert.False(t, IsPushableInteger(new(big.Int).Sub(min, big.NewInt(1))))
+	assert.False(t, IsPushableInteger(nil))
+
+	push := func(n *big.Int) ([]byte, error) {
+		sb := NewScriptBuilder()
+		return sb.EmitPushBigInt(n).ToArray()
+	}
+	b, err := push(max)
+	assert.Nil(t, err)
+	assert.Equal(t, byte(PUSHINT256), b[0])
+	assert.Equal(t, 0, max.Cmp(helper.BigIntFromNeoBytes(b[1:])))
+	b, err = push(min)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, min.Cmp(helper.BigIntFromNeoBytes(b[1:])))
+
+	_, err = push(new(big.Int).Add(max, big.NewInt(1)))
+	assert.NotNil(t, err)
+	_, err = push(nil)
+	assert.NotNil(t, err)
+}
+
+func TestPadNeoInteger(t *testing.T) {
+	assert.Equal(t, []byte{0x00}, padNeoInteger([]byte{}, 1))
+	assert.Equal(t, []byte{0x7f, 0xff, 0xff, 0xff}, padNeoInteger([]byte{0x7f, 0xff}, 4))
+	assert.Equal(t, []byte{0x01, 0x00, 0x00, 0x00}, padNeoInteger([]byte{0x01}, 4))
+	assert.Equal(t, []byte{0x01, 0x02}, padNeoInteger([]byte{0x01, 0x02}, 2))
+}
+
+func TestScriptBuilder_PatchJump(t *testing.T) {
+	sb := NewScriptBuilder()
+	// loop: PUSH0 is the loop start, the forward jump skips the backward one
+	start := sb.Offset()
+	sb.Emit(PUSH0)
+	forward := sb.Offset()
+	sb.EmitJump(JMPIF, 0)
+	backward := sb.Offset()
+	sb.EmitJump(JMP_L, 0)
+	end := sb.Len()
+	sb.Emit(RET)
+
+	assert.Nil(t, sb.PatchJump(forward, end))
+	assert.Nil(t, sb.PatchJump(backward, start))
+	b, err := sb.ToArray()
+	assert.Nil(t, err)
+	expected := []byte{byte(PUSH0), byte(JMPIF), 0x07, byte(JMP_L), 0xfd, 0xff, 0xff, 0xff, byte(RET)}
+	assert.Equal(t, expected, b)
+
+	// not a jump
+	assert.NotNil(t, sb.PatchJump(start, end))
+	// out of range
+	assert.NotNil(t, sb.PatchJump(100, end))
+	// the offset does not fit a 1-byte operand
+	assert.NotNil(t, sb.PatchJump(forward, 200))
+	assert.Nil(t, sb.PatchJump(backward, 200))
+}
+
+func TestMakeScriptWithFlags(t *testing.T) {
+	scriptHash := helper.UInt160FromBytes(helper.HexToBytes("28b3adab7269f9c2181db3cb741ebf551930e270"))
+	args := []interface{}{helper.UInt160Zero}
+	all, err := MakeScript(scriptHash, "balanceOf", args)
+	assert.Nil(t, err)
+	readOnly, err := MakeScriptWithFlags(scriptHash, "balanceOf", ReadOnly, args)
+	assert.Nil(t, err)
+	none, err := MakeScriptWithFlags(scriptHash, "balanceOf", None, args)
+	assert.Nil(t, err)
+
+	// the flags are pushed after the packed arguments
+	flagsAt := 1 + 1 + helper.UINT160SIZE + 2
+	assert.Equal(t, byte(PUSH15), all[flagsAt])
+	assert.Equal(t, byte(PUSH5), readOnly[flagsAt])
+	assert.Equal(t, byte(PUSH0), none[flagsAt])
+	assert.Equal(t, all[:flagsAt], readOnly[:flagsAt])
+	assert.Equal(t, all[flagsAt+1:], readOnly[flagsAt+1:])
+
+	sb := NewScriptBuilder()
+	sb.EmitDynamicCallWithFlags(scriptHash, "balanceOf", ReadOnly, args)
+	b, err := sb.ToArray()
+	assert.Nil(t, err)
+	assert.Equal(t, readOnly, b)
+}
+
+func TestScriptBuilder_Chained(t *testing.T) {
+	sb := NewScriptBuilder()
+	b, err := sb.EmitPushInteger(1).EmitPushString("x").EmitPushInteger(2).Emit(PACK).
+		EmitPushString("event").EmitSysCall(System_Runtime_Notify.ToInteropMethodHash()).ToArray()
+	assert.Nil(t, err)
+
+	expected := NewScriptBuilder()
+	expected.EmitNotify("event", []interface{}{"x", 1})
+	e, err := expected.ToArray()
+	assert.Nil(t, err)
+	assert.Equal(t, e, b)
+
+	// the errors of the chained calls are returned by ToArray
+	sb = NewScriptBuilder()
+	_, err = sb.EmitPushObject(1.5).EmitPushInteger(1).EmitDynamicCall(helper.UInt160Zero, "", nil).ToArray()
+	assert.NotNil(t, err)
+	assert.Equal(t, 2, len(strings.Split(err.Error(), "\n")))
+}
+
+func TestScriptBuilder_EmitSysCallByName(t *testing.T) {
+	sb := NewScriptBuilder()
+	assert.Nil(t, sb.EmitSysCallByName("System.Contract.Call"))
+	b, err := sb.ToArray()
+	assert.Nil(t, err)
+
+	expected := NewScriptBuilder()
+	expected.EmitSysCall(System_Contract_Call.ToInteropMethodHash())
+	e, err := expected.ToArray()
+	assert.Nil(t, err)
+	assert.Equal(t, e, b)
+	assert.Equal(t, "41627d5b52", helper.BytesToHex(b))
+
+	api, err := GetInteropMethodHash("System.Runtime.CheckWitness")
+	assert.Nil(t, err)
+	assert.Equal(t, System_Runtime_CheckWitness.ToInteropMethodHash(), api)
+
+	sb = NewScriptBuilder()
+	assert.NotNil(t, sb.EmitSysCallByName("System.Contract.Unknown"))
+	assert.Equal(t, 0, sb.Len())
+}
+
+func TestScriptBuilder_EmitPushUInt160(t *testing.T) {
+	h := helper.UInt160FromBytes(helper.HexToBytes("28b3adab7269f9c2181db3cb741ebf551930e270"))
+	sb := NewScriptBuilder()
+	b, err := sb.EmitPushUInt160(h).EmitPushUInt160(nil).ToArray()
+	assert.Nil(t, err)
+	assert.Equal(t, "0c14"+"28b3adab7269f9c2181db3cb741ebf551930e270"+"0b", helper.BytesToHex(b))
+
+	expected := NewScriptBuilder()
+	e, _ := expected.EmitPushSerializable(h).ToArray()
+	assert.Equal(t, e, b[:22])
+
+	// a nil hash passed as an object is pushed as null too
+	var nilHash *helper.UInt160
+	sb = NewScriptBuilder()
+	b, err = sb.EmitPushObject(nilHash).ToArray()
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{byte(PUSHNULL)}, b)
+}
+
+func TestScriptBuilder_EmitPushUInt256(t *testing.T) {
+	h, _ := helper.UInt256FromString("0x8e8e8b80e20cb1aa55b0e9a3e6bd3e7e5f46e2d6b2c2dc5f24bd1b5ecb5d9a3a")
+	sb := NewScriptBuilder()
+	b, err := sb.EmitPushUInt256(h).EmitPushUInt256(nil).ToArray()
+	assert.Nil(t, err)
+	assert.Equal(t, 1+1+32+1, len(b))
+	assert.Equal(t, []byte{byte(PUSHDATA1), 32}, b[:2])
+	assert.Equal(t, h.ToByteArray(), b[2:34])
+	assert.Equal(t, byte(PUSHNULL), b[34])
+}
+
+func TestScriptBuilder_EmitPushA(t *testing.T) {
+	sb := NewScriptBuilder()
+	sb.Emit(NOP)
+	b, err := sb.EmitPushA(-1).EmitPushA(6).Emit(RET).ToArray()
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{byte(NOP), byte(PUSHA), 0xff, 0xff, 0xff, 0xff, byte(PUSHA), 0x06, 0x00, 0x00, 0x00, byte(RET)}, b)
+
+	instructions, err := Disassemble(b)
+	assert.Nil(t, err)
+	assert.Equal(t, 5, instructions[1].Size())
+
+	sb = NewScriptBuilder()
+	_, err = sb.EmitPushA(math.MaxInt32 + 1).ToArray()
+	assert.NotNil(t, err)
+	assert.Equal(t, 0, sb.Len())
+}
+
+func TestScriptBuilder_Reset(t *testing.T) {
+	sb := NewScriptBuilder()
+	sb.SetExplicitPushInt(true)
+	sb.EmitPushInteger(1).EmitPushObject(1.5)
+	_, err := sb.ToArray()
+	assert.NotNil(t, err)
+
+	sb.Reset()
+	assert.Equal(t, 0, sb.Len())
+	b, err := sb.EmitPushInteger(1).ToArray()
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{byte(PUSH1)}, b)
+
+	pooled := GetScriptBuilder()
+	b, err = pooled.EmitPushInteger(2).ToArray()
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{byte(PUSH2)}, b)
+	PutScriptBuilder(pooled)
+	pooled = GetScriptBuilder()
+	assert.Equal(t, 0, pooled.Len())
+	PutScriptBuilder(pooled)
+}
+
+func benchmarkScript(sb *ScriptBuilder) {
+	sb.EmitDynamicCall(helper.UInt160Zero, "balanceOf", []interface{}{helper.UInt160Zero})
+}
+
+func BenchmarkNewScriptBuilder(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		sb := NewScriptBuilder()
+		benchmarkScript(&sb)
+		_, _ = sb.ToArray()
+	}
+}
+
+func BenchmarkScriptBuilderPool(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		sb := GetScriptBuilder()
+		benchmarkScript(sb)
+		_, _ = sb.ToArray()
+		PutScriptBuilder(sb)
+	}
+}
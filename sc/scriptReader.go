@@ -0,0 +1,36 @@
+package sc
+
+import (
+	"io"
+)
+
+// ScriptReader reads the instructions of a compiled script one by one
+type ScriptReader struct {
+	script []byte
+	offset int
+}
+
+// NewScriptReader creates a ScriptReader reading from the start of the script
+func NewScriptReader(script []byte) *ScriptReader {
+	return &ScriptReader{script: script}
+}
+
+// GetOffset returns the offset of the next instruction to read
+func (r *ScriptReader) GetOffset() int {
+	return r.offset
+}
+
+// ReadNext returns the next opcode with its operand, the length prefix of PUSHDATA operands is stripped and
+// the operands of PUSHINT, JMP and CALL opcodes are returned raw in little endian. It returns io.EOF at the
+// end of the script, and an error on unknown opcodes or truncated operands.
+func (r *ScriptReader) ReadNext() (OpCode, []byte, error) {
+	if r.offset >= len(r.script) {
+		return 0, nil, io.EOF
+	}
+	i, err := readInstruction(r.script, r.offset)
+	if err != nil {
+		return 0, nil, err
+	}
+	r.offset += i.Size()
+	return i.OpCode, i.Operand, nil
+}
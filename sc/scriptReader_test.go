@@ -0,0 +1,70 @@
+package sc
+
+import (
+	"bytes"
+	"io"
+	"math/big"
+	"testing"
+
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDisassemble_MakeScript(t *testing.T) {
+	scriptHash := helper.UInt160FromBytes(helper.HexToBytes("28b3adab7269f9c2181db3cb741ebf551930e270"))
+	script, err := MakeScript(scriptHash, "balanceOf", []interface{}{helper.UInt160Zero})
+	assert.Nil(t, err)
+
+	instructions, err := Disassemble(script)
+	assert.Nil(t, err)
+	assert.Equal(t, 7, len(instructions))
+	ops := make([]OpCode, len(instructions))
+	for i, ins := range instructions {
+		ops[i] = ins.OpCode
+	}
+	assert.Equal(t, []OpCode{PUSHDATA1, PUSH1, PACK, PUSH15, PUSHDATA1, PUSHDATA1, SYSCALL}, ops)
+	assert.Equal(t, helper.UInt160Zero.ToByteArray(), instructions[0].Operand)
+	assert.Equal(t, []byte("balanceOf"), instructions[4].Operand)
+	assert.Equal(t, scriptHash.ToByteArray(), instructions[5].Operand)
+	assert.Equal(t, helper.HexToBytes("627d5b52"), instructions[6].Operand)
+	assert.Equal(t, 0, instructions[0].Offset)
+	assert.Equal(t, 22, instructions[1].Offset)
+	assert.Equal(t, len(script), instructions[6].Offset+instructions[6].Size())
+}
+
+func TestScriptReader_ReadNext(t *testing.T) {
+	sb := NewScriptBuilder()
+	sb.EmitPushBigInt(big.NewInt(1000))
+	sb.EmitPushBytes(bytes.Repeat([]byte{0x01}, 300))
+	sb.EmitRaw([]byte{byte(JMP), 0xfe, byte(CALL_L), 0x10, 0x00, 0x00, 0x00})
+	script, err := sb.ToArray()
+	assert.Nil(t, err)
+
+	reader := NewScriptReader(script)
+	op, operand, err := reader.ReadNext()
+	assert.Nil(t, err)
+	assert.Equal(t, PUSHINT16, op)
+	assert.Equal(t, []byte{0xe8, 0x03}, operand)
+
+	op, operand, err = reader.ReadNext()
+	assert.Nil(t, err)
+	assert.Equal(t, PUSHDATA2, op)
+	assert.Equal(t, 300, len(operand))
+	assert.Equal(t, 3+3+300, reader.GetOffset())
+
+	op, operand, err = reader.ReadNext()
+	assert.Nil(t, err)
+	assert.Equal(t, JMP, op)
+	assert.Equal(t, []byte{0xfe}, operand)
+
+	op, operand, err = reader.ReadNext()
+	assert.Nil(t, err)
+	assert.Equal(t, CALL_L, op)
+	assert.Equal(t, []byte{0x10, 0x00, 0x00, 0x00}, operand)
+
+	_, _, err = reader.ReadNext()
+	assert.Equal(t, io.EOF, err)
+
+	_, _, err = NewScriptReader([]byte{byte(PUSHINT32), 0x01}).ReadNext()
+	assert.NotNil(t, err)
+}
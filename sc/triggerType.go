@@ -0,0 +1,47 @@
+package sc
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TriggerType is the trigger of an execution, as the "trigger" in the application log
+type TriggerType byte
+
+const (
+	OnPersist    TriggerType = 0x01 // Indicates that the contract is triggered by the system to execute the OnPersist method of the native contracts.
+	PostPersist  TriggerType = 0x02 // Indicates that the contract is triggered by the system to execute the PostPersist method of the native contracts.
+	Verification TriggerType = 0x20 // Indicates that the contract is triggered by the verification of a witness.
+	Application  TriggerType = 0x40 // Indicates that the contract is triggered by the execution of transactions.
+)
+
+// NewTriggerTypeFromString parses the trigger type, the name is case insensitive
+func NewTriggerTypeFromString(s string) (TriggerType, error) {
+	switch strings.ToLower(s) {
+	case "onpersist":
+		return OnPersist, nil
+	case "postpersist":
+		return PostPersist, nil
+	case "verification":
+		return Verification, nil
+	case "application":
+		return Application, nil
+	default:
+		return 0, fmt.Errorf("unknown trigger type: %s", s)
+	}
+}
+
+func (t TriggerType) String() string {
+	switch t {
+	case OnPersist:
+		return "OnPersist"
+	case PostPersist:
+		return "PostPersist"
+	case Verification:
+		return "Verification"
+	case Application:
+		return "Application"
+	default:
+		return ""
+	}
+}
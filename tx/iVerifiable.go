@@ -1,9 +1,14 @@
 package tx
 
 import (
+	"bytes"
+	"fmt"
+	"reflect"
+
 	"github.com/joeqian10/neo3-gogogo/crypto"
 	"github.com/joeqian10/neo3-gogogo/helper"
 	"github.com/joeqian10/neo3-gogogo/io"
+	"github.com/joeqian10/neo3-gogogo/keys"
 )
 
 type IVerifiable interface {
@@ -39,3 +44,40 @@ func CalculateHash(verifiable IVerifiable) *helper.UInt256 {
 
 	return helper.UInt256FromBytes(crypto.Sha256(buf.Bytes()))
 }
+
+// DetectMagic returns the network magic among the candidates under which the public key has signed the
+// verifiable, the signatures are taken from the witnesses whose verification script contains the public key
+func DetectMagic(verifiable IVerifiable, publicKey *crypto.ECPoint, magics []uint32) (uint32, error) {
+	if v := reflect.ValueOf(verifiable); !v.IsValid() || (v.Kind() == reflect.Ptr && v.IsNil()) {
+		return 0, fmt.Errorf("verifiable is nil")
+	}
+	if publicKey == nil {
+		return 0, fmt.Errorf("public key is nil")
+	}
+	signatures := make([][]byte, 0)
+	encoded := publicKey.EncodePoint(true)
+	for _, w := range verifiable.GetWitnesses() {
+		if !bytes.Contains(w.VerificationScript, encoded) {
+			continue
+		}
+		// each signature is pushed by PUSHDATA1 0x40
+		for i := 0; i+66 <= len(w.InvocationScript); i += 66 {
+			if w.InvocationScript[i] != 0x0c || w.InvocationScript[i+1] != 0x40 {
+				break
+			}
+			signatures = append(signatures, w.InvocationScript[i+2:i+66])
+		}
+	}
+	if len(signatures) == 0 {
+		return 0, fmt.Errorf("no signature found for public key %s", publicKey.String())
+	}
+	for _, magic := range magics {
+		data := GetSignData(verifiable, magic)
+		for _, signature := range signatures {
+			if keys.VerifySignature(data, signature, publicKey) {
+				return magic, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("signature does not verify under any of the magics")
+}
@@ -0,0 +1,47 @@
+package tx
+
+import (
+	"testing"
+
+	"github.com/joeqian10/neo3-gogogo/crypto"
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/keys"
+	"github.com/joeqian10/neo3-gogogo/sc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectMagic(t *testing.T) {
+	pair, err := keys.NewKeyPair(helper.HexToBytes(keys.KeyCases[0].PrivateKey))
+	assert.Nil(t, err)
+	script, err := sc.CreateSignatureRedeemScript(pair.PublicKey)
+	assert.Nil(t, err)
+	account := helper.UInt160FromBytes(crypto.Hash160(script))
+
+	trx := NewTransaction()
+	trx.SetScript([]byte{byte(sc.PUSH1)})
+	trx.SetSigners([]Signer{{Account: account, Scopes: CalledByEntry}})
+	witness, err := CreateSignatureWitness(GetSignData(trx, helper.N3Magic_TestNet), pair)
+	assert.Nil(t, err)
+	trx.SetWitnesses([]Witness{*witness})
+
+	magics := []uint32{helper.N3Magic_MainNet, helper.N3Magic_TestNet}
+	magic, err := DetectMagic(trx, pair.PublicKey, magics)
+	assert.Nil(t, err)
+	assert.Equal(t, helper.N3Magic_TestNet, magic)
+
+	_, err = DetectMagic(trx, pair.PublicKey, magics[:1])
+	assert.NotNil(t, err)
+
+	other, err := keys.NewKeyPair(helper.HexToBytes(keys.KeyCases[1].PrivateKey))
+	assert.Nil(t, err)
+	_, err = DetectMagic(trx, other.PublicKey, magics)
+	assert.NotNil(t, err)
+
+	_, err = DetectMagic(trx, nil, magics)
+	assert.NotNil(t, err)
+	_, err = DetectMagic(nil, pair.PublicKey, magics)
+	assert.NotNil(t, err)
+	var nilTrx *Transaction
+	_, err = DetectMagic(nilTrx, pair.PublicKey, magics)
+	assert.NotNil(t, err)
+}
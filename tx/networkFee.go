@@ -0,0 +1,150 @@
+package tx
+
+import (
+	"fmt"
+
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/sc"
+)
+
+// SignerFee is the part of the network fee caused by one signer
+type SignerFee struct {
+	Account *helper.UInt160
+	Size    int   // bytes of the signer and its witness in the transaction
+	ExecFee int64 // fee for executing the verification script
+	SizeFee int64 // fee for the bytes above
+}
+
+// Total returns the sum of the execution fee and the size fee
+func (f SignerFee) Total() int64 {
+	return f.ExecFee + f.SizeFee
+}
+
+// GetWitnessCost returns the size of a fully signed witness and the execution fee of its verification,
+// only standard signature and multi-signature verification scripts are supported
+func GetWitnessCost(verificationScript []byte, execFeeFactor int64) (int, int64, error) {
+	if sc.IsSignatureContract(verificationScript) {
+		size := 67 + sc.ByteSlice(verificationScript).GetVarSize()
+		fee := execFeeFactor * (sc.OpCodePrices[sc.PUSHDATA1] + sc.OpCodePrices[sc.PUSHDATA1] + sc.OpCodePrices[sc.SYSCALL] + ECDsaVerifyPrice)
+		return size, fee, nil
+	}
+	if b, m, n, _ := sc.IsMultiSigContract(verificationScript); b {
+		sizeInv := 66 * m
+		size := helper.GetVarSize(sizeInv) + sizeInv + sc.ByteSlice(verificationScript).GetVarSize()
+
+		fee := execFeeFactor * sc.OpCodePrices[sc.PUSHDATA1] * int64(m)
+		fee += execFeeFactor * sc.OpCodePrices[pushIntegerOpCode(m)]
+		fee += execFeeFactor * sc.OpCodePrices[sc.PUSHDATA1] * int64(n)
+		fee += execFeeFactor * sc.OpCodePrices[pushIntegerOpCode(n)]
+		fee += execFeeFactor * (sc.OpCodePrices[sc.SYSCALL] + int64(ECDsaVerifyPrice*n))
+		return size, fee, nil
+	}
+	return 0, 0, fmt.Errorf("verification script is neither a signature nor a multi-signature contract")
+}
+
+// EstimateSignerFees estimates how much of the network fee each signer of trx causes, which can be used to
+// split the fee among the signers. The node charges the whole network fee to the sender, so this is only
+// an approximation: the bytes shared by all signers (header, attributes, script) are not included.
+// The witnesses of trx must be in the same order as the signers, their verification scripts are used to
+// determine the cost and their invocation scripts may still be empty.
+func EstimateSignerFees(trx *Transaction, execFeeFactor int64, feePerByte int64) ([]SignerFee, error) {
+	signers := trx.GetSigners()
+	witnesses := trx.GetWitnesses()
+	if len(witnesses) != len(signers) {
+		return nil, fmt.Errorf("the count of witnesses %d does not match the count of signers %d", len(witnesses), len(signers))
+	}
+	result := make([]SignerFee, len(signers))
+	for i := range signers {
+		size, execFee, err := GetWitnessCost(witnesses[i].VerificationScript, execFeeFactor)
+		if err != nil {
+			return nil, fmt.Errorf("signer %s: %v", signers[i].Account.String(), err)
+		}
+		size += signers[i].Size()
+		result[i] = SignerFee{
+			Account: signers[i].Account,
+			Size:    size,
+			ExecFee: execFee,
+			SizeFee: int64(size) * feePerByte,
+		}
+	}
+	return result, nil
+}
+
+// FeePolicy is the fee settings of the Policy native contract
+type FeePolicy struct {
+	FeePerByte    int64
+	ExecFeeFactor int64
+	StoragePrice  int64
+}
+
+// DefaultFeePolicy is the fee policy of the Policy native contract when it is not changed by the committee
+var DefaultFeePolicy = FeePolicy{FeePerByte: FeePerByte, ExecFeeFactor: ExecFeeFactor, StoragePrice: StoragePrice}
+
+// FeeEstimate is the breakdown of the fees of a transaction
+type FeeEstimate struct {
+	SystemFee  int64
+	Size       int   // bytes of the fully signed transaction
+	SizeFee    int64 // Size * FeePerByte
+	WitnessFee int64 // fee for executing the verification scripts
+}
+
+// NetworkFee returns the network fee, the sum of the size fee and the witness fee
+func (f FeeEstimate) NetworkFee() int64 {
+	return f.SizeFee + f.WitnessFee
+}
+
+// Total returns the sum of the system fee and the network fee
+func (f FeeEstimate) Total() int64 {
+	return f.SystemFee + f.NetworkFee()
+}
+
+// EstimateTotalFee estimates the fees of trx under the policy, the system fee is the one already set in trx,
+// e.g. the gas consumed by invokescript. The witnesses of trx must be in the same order as the signers, their
+// verification scripts are used to determine the cost and their invocation scripts may still be empty.
+func EstimateTotalFee(trx *Transaction, policy FeePolicy) (*FeeEstimate, error) {
+	signers := trx.GetSigners()
+	witnesses := trx.GetWitnesses()
+	if len(witnesses) != len(signers) {
+		return nil, fmt.Errorf("the count of witnesses %d does not match the count of signers %d", len(witnesses), len(signers))
+	}
+	size := trx.HeaderSize() +
+		SignerSlice(signers).GetVarSize() +
+		TransactionAttributeSlice(trx.GetAttributes()).GetVarSize() +
+		sc.ByteSlice(trx.GetScript()).GetVarSize() +
+		helper.GetVarSize(len(witnesses))
+	witnessFee := int64(0)
+	for i := range witnesses {
+		witnessSize, execFee, err := GetWitnessCost(witnesses[i].VerificationScript, policy.ExecFeeFactor)
+		if err != nil {
+			return nil, fmt.Errorf("signer %s: %v", signers[i].Account.String(), err)
+		}
+		size += witnessSize
+		witnessFee += execFee
+	}
+	return &FeeEstimate{
+		SystemFee:  trx.GetSystemFee(),
+		Size:       size,
+		SizeFee:    int64(size) * policy.FeePerByte,
+		WitnessFee: witnessFee,
+	}, nil
+}
+
+// CalculateNetworkFee calculates the network fee of trx offline, the size fee with feePerByte plus the
+// fee for executing the verification scripts with the default ExecFeeFactor. It agrees with the
+// calculatenetworkfee RPC method when the witnesses of trx hold the verification scripts of the signers,
+// which must be standard signature or multi-signature accounts, and the exec fee factor of the Policy
+// contract is not changed. The node also supports contract accounts, so prefer the RPC method for them
+func CalculateNetworkFee(trx *Transaction, feePerByte int64) (int64, error) {
+	estimate, err := EstimateTotalFee(trx, FeePolicy{FeePerByte: feePerByte, ExecFeeFactor: ExecFeeFactor})
+	if err != nil {
+		return 0, err
+	}
+	return estimate.NetworkFee(), nil
+}
+
+func pushIntegerOpCode(n int) sc.OpCode {
+	sb := sc.NewScriptBuilder()
+	sb.EmitPushInteger(n)
+	script, _ := sb.ToArray()
+	return sc.OpCode(script[0])
+}
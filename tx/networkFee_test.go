@@ -0,0 +1,89 @@
+package tx
+
+import (
+	"testing"
+
+	"github.com/joeqian10/neo3-gogogo/crypto"
+	"github.com/joeqian10/neo3-gogogo/keys"
+	"github.com/joeqian10/neo3-gogogo/sc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEstimateSignerFees(t *testing.T) {
+	points := make([]crypto.ECPoint, 3)
+	for i := 0; i < 3; i++ {
+		p, err := crypto.NewECPointFromString(keys.KeyCases[i].PublicKey)
+		assert.Nil(t, err)
+		points[i] = *p
+	}
+	sigScript, err := sc.CreateSignatureRedeemScript(&points[0])
+	assert.Nil(t, err)
+	multiSigScript, err := sc.CreateMultiSigRedeemScript(2, points)
+	assert.Nil(t, err)
+
+	sigWitness := Witness{InvocationScript: []byte{}, VerificationScript: sigScript}
+	multiSigWitness := Witness{InvocationScript: []byte{}, VerificationScript: multiSigScript}
+
+	trx := NewTransaction()
+	trx.SetScript([]byte{byte(sc.PUSH1)})
+	trx.SetSigners([]Signer{
+		{Account: sigWitness.GetScriptHash(), Scopes: CalledByEntry},
+		{Account: multiSigWitness.GetScriptHash(), Scopes: CalledByEntry},
+	})
+	trx.SetWitnesses([]Witness{sigWitness, multiSigWitness})
+
+	fees, err := EstimateSignerFees(trx, ExecFeeFactor, FeePerByte)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(fees))
+
+	assert.Equal(t, 129, fees[0].Size)
+	assert.Equal(t, int64(983520), fees[0].ExecFee)
+	assert.Equal(t, int64(129000), fees[0].SizeFee)
+	assert.Equal(t, int64(1112520), fees[0].Total())
+
+	assert.Equal(t, 267, fees[1].Size)
+	assert.Equal(t, int64(2950380), fees[1].ExecFee)
+	assert.Equal(t, int64(3217380), fees[1].Total())
+
+	trx.SetWitnesses([]Witness{sigWitness})
+	_, err = EstimateSignerFees(trx, ExecFeeFactor, FeePerByte)
+	assert.NotNil(t, err)
+}
+
+func TestEstimateTotalFee(t *testing.T) {
+	pair, err := keys.NewKeyPairFromWIF(keys.KeyCases[0].Wif)
+	assert.Nil(t, err)
+	sigScript, err := sc.CreateSignatureRedeemScript(pair.PublicKey)
+	assert.Nil(t, err)
+	witness := Witness{InvocationScript: []byte{}, VerificationScript: sigScript}
+
+	trx := NewTransaction()
+	trx.SetScript([]byte{byte(sc.PUSH1)})
+	trx.SetSystemFee(997780)
+	trx.SetSigners([]Signer{{Account: witness.GetScriptHash(), Scopes: CalledByEntry}})
+	trx.SetWitnesses([]Witness{witness})
+
+	estimate, err := EstimateTotalFee(trx, DefaultFeePolicy)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(997780), estimate.SystemFee)
+	assert.Equal(t, 159, estimate.Size)
+	assert.Equal(t, int64(159000), estimate.SizeFee)
+	assert.Equal(t, int64(983520), estimate.WitnessFee)
+	assert.Equal(t, int64(1142520), estimate.NetworkFee())
+	assert.Equal(t, int64(2140300), estimate.Total())
+
+	estimate, err = EstimateTotalFee(trx, FeePolicy{FeePerByte: 20, ExecFeeFactor: 1})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3180), estimate.SizeFee)
+	assert.Equal(t, int64(32784), estimate.WitnessFee)
+
+	// the estimated size is the size after signing
+	signed, err := CreateSignatureWitness(trx.GetHash().ToByteArray(), pair)
+	assert.Nil(t, err)
+	trx.SetWitnesses([]Witness{*signed})
+	assert.Equal(t, trx.GetSize(), estimate.Size)
+
+	trx.SetWitnesses([]Witness{})
+	_, err = EstimateTotalFee(trx, DefaultFeePolicy)
+	assert.NotNil(t, err)
+}
@@ -49,7 +49,7 @@ func (o *OracleResponseAttribute) GetAttributeSize() int {
 
 func (o *OracleResponseAttribute) Deserialize(br *io.BinaryReader) {
 	if br.ReadByte() != byte(OracleResponse) {
-		br.Err = fmt.Errorf("format error: not HighPriority")
+		br.Err = fmt.Errorf("format error: not OracleResponse")
 	}
 	o.DeserializeWithoutType(br)
 }
@@ -1,5 +1,7 @@
 package tx
 
+import "fmt"
+
 type OracleResponseCode byte
 
 const (
@@ -27,3 +29,38 @@ func (code OracleResponseCode) IsDefined() bool {
 		return false
 	}
 }
+
+func (code OracleResponseCode) String() string {
+	switch code {
+	case Success:
+		return "Success"
+	case ProtocolNotSupported:
+		return "ProtocolNotSupported"
+	case ConsensusUnreachable:
+		return "ConsensusUnreachable"
+	case NotFound:
+		return "NotFound"
+	case Timeout:
+		return "Timeout"
+	case Forbidden:
+		return "Forbidden"
+	case ResponseTooLarge:
+		return "ResponseTooLarge"
+	case InsufficientFunds:
+		return "InsufficientFunds"
+	case Error:
+		return "Error"
+	default:
+		return "Not Defined"
+	}
+}
+
+func NewOracleResponseCodeFromString(s string) (OracleResponseCode, error) {
+	for _, code := range []OracleResponseCode{Success, ProtocolNotSupported, ConsensusUnreachable, NotFound,
+		Timeout, Forbidden, ResponseTooLarge, InsufficientFunds, Error} {
+		if code.String() == s {
+			return code, nil
+		}
+	}
+	return Error, fmt.Errorf("not supported oracle response code: %s", s)
+}
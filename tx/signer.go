@@ -1,9 +1,12 @@
 package tx
 
 import (
+	"fmt"
+
 	"github.com/joeqian10/neo3-gogogo/crypto"
 	"github.com/joeqian10/neo3-gogogo/helper"
 	"github.com/joeqian10/neo3-gogogo/io"
+	"github.com/joeqian10/neo3-gogogo/sc"
 )
 
 const (
@@ -52,6 +55,14 @@ func (c *Signer) CompareTo(d *Signer) int {
 func (c *Signer) Deserialize(br *io.BinaryReader) {
 	br.ReadLE(c.Account)
 	br.ReadLE(&c.Scopes)
+	if c.Scopes&^(CalledByEntry|CustomContracts|CustomGroups|Global) != 0 {
+		br.Err = fmt.Errorf("format error: invalid witness scope %d", c.Scopes)
+		return
+	}
+	if c.Scopes&Global != 0 && c.Scopes != Global {
+		br.Err = fmt.Errorf("format error: global scope cannot be combined with other scopes")
+		return
+	}
 	if c.Scopes&CustomContracts != 0 {
 		length := br.ReadVarUIntWithMaxLimit(uint64(MaxSubitems))
 		c.AllowedContracts = make([]helper.UInt160, length)
@@ -66,9 +77,11 @@ func (c *Signer) Deserialize(br *io.BinaryReader) {
 		length := br.ReadVarUIntWithMaxLimit(uint64(MaxSubitems))
 		c.AllowedGroups = make([]crypto.ECPoint, length)
 		for i := 0; i < int(length); i++ {
-			c.AllowedGroups[i] = crypto.ECPoint{}
+			c.AllowedGroups[i] = crypto.ECPoint{Curve: crypto.P256}
 			c.AllowedGroups[i].Deserialize(br)
 		}
+	} else {
+		c.AllowedGroups = []crypto.ECPoint{}
 	}
 }
 
@@ -81,7 +94,7 @@ func (c *Signer) Serialize(bw *io.BinaryWriter) {
 			bw.WriteLE(ac)
 		}
 	}
-	if c.Scopes&CustomContracts != 0 {
+	if c.Scopes&CustomGroups != 0 {
 		bw.WriteVarUInt(uint64(len(c.AllowedGroups)))
 		for _, ag := range c.AllowedGroups {
 			ag.Serialize(bw)
@@ -89,8 +102,67 @@ func (c *Signer) Serialize(bw *io.BinaryWriter) {
 	}
 }
 
+// ValidateAllowedContracts checks every contract called by the script is in AllowedContracts when
+// the signer only has CustomContracts scope. Signers with CalledByEntry or Global already cover the
+// called contracts, and the contracts covered by CustomGroups cannot be known from the script, so
+// they are not checked
+func (c *Signer) ValidateAllowedContracts(script []byte) error {
+	if c.Scopes&CustomContracts == 0 || c.Scopes&(CalledByEntry|Global|CustomGroups) != 0 {
+		return nil
+	}
+	called, err := sc.GetCalledContracts(script)
+	if err != nil {
+		return err
+	}
+	for i := range called {
+		if !containsUInt160(c.AllowedContracts, &called[i]) {
+			return fmt.Errorf("contract %s called by the script is not allowed by signer %s", called[i].String(), c.Account.String())
+		}
+	}
+	return nil
+}
+
+// Validate checks the scopes of the signer are not conflicting and the allowed contracts and groups
+// match the scopes
+func (c *Signer) Validate() error {
+	if c.Account == nil {
+		return fmt.Errorf("signer has no account")
+	}
+	if c.Scopes&^(CalledByEntry|CustomContracts|CustomGroups|Global) != 0 {
+		return fmt.Errorf("invalid witness scope %d of signer %s", c.Scopes, c.Account.String())
+	}
+	if c.Scopes&Global != 0 && c.Scopes != Global {
+		return fmt.Errorf("global scope of signer %s cannot be combined with other scopes", c.Account.String())
+	}
+	if c.Scopes&CustomContracts == 0 && len(c.AllowedContracts) != 0 {
+		return fmt.Errorf("signer %s has allowed contracts without CustomContracts scope", c.Account.String())
+	}
+	if c.Scopes&CustomGroups == 0 && len(c.AllowedGroups) != 0 {
+		return fmt.Errorf("signer %s has allowed groups without CustomGroups scope", c.Account.String())
+	}
+	if len(c.AllowedContracts) > MaxSubitems || len(c.AllowedGroups) > MaxSubitems {
+		return fmt.Errorf("too many allowed contracts or groups for signer %s", c.Account.String())
+	}
+	return nil
+}
+
 type SignerSlice []Signer
 
+// Validate checks every signer and that no account signs twice
+func (cs SignerSlice) Validate() error {
+	seen := make(map[helper.UInt160]bool, len(cs))
+	for i := range cs {
+		if err := cs[i].Validate(); err != nil {
+			return err
+		}
+		if seen[*cs[i].Account] {
+			return fmt.Errorf("duplicate signer %s", cs[i].Account.String())
+		}
+		seen[*cs[i].Account] = true
+	}
+	return nil
+}
+
 func (cs SignerSlice) GetVarSize() int {
 	size := 0
 	for _, c := range cs {
@@ -1,6 +1,7 @@
 package tx
 
 import (
+	"github.com/joeqian10/neo3-gogogo/crypto"
 	"github.com/joeqian10/neo3-gogogo/helper"
 	"github.com/joeqian10/neo3-gogogo/io"
 	"github.com/stretchr/testify/assert"
@@ -43,7 +44,7 @@ func TestSigner_Serialize(t *testing.T) {
 	bbw := io.NewBufBinaryWriter()
 	cs.Serialize(bbw.BinaryWriter)
 	b := bbw.Bytes()
-	assert.Equal(t, "ae716cd8bf248c38b601723ac4be2dc7979baeed"+"10"+"01"+"2b2e74b24ffc5599761499bbe67843abcb4413ad"+"00", helper.BytesToHex(b))
+	assert.Equal(t, "ae716cd8bf248c38b601723ac4be2dc7979baeed"+"10"+"01"+"2b2e74b24ffc5599761499bbe67843abcb4413ad", helper.BytesToHex(b))
 }
 
 func TestSigner_Size(t *testing.T) {
@@ -85,3 +86,76 @@ func TestSigner_Deserialize2(t *testing.T) {
 	signer1.Deserialize(br)
 	assert.Equal(t, 0, signer.CompareTo(signer1))
 }
+
+func TestSigner_ValidateAllowedContracts(t *testing.T) {
+	account, _ := helper.UInt160FromString("0x2916eba24e652fa006f3e5eb8f9892d2c3b00399")
+	spender, _ := helper.UInt160FromString("0x8c23f196d8a1bfd103a9dcb1f9ccf0c611377d3b")
+	script, err := MakeMultiCallScript([]ContractCall{
+		{ScriptHash: GasToken, Operation: "approve", Args: []interface{}{account, spender, 100}},
+		{ScriptHash: spender, Operation: "transfer", Args: []interface{}{account, 100}},
+	})
+	assert.Nil(t, err)
+
+	signer := Signer{Account: account, Scopes: CustomContracts, AllowedContracts: []helper.UInt160{*GasToken}}
+	err = signer.ValidateAllowedContracts(script)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), spender.String())
+
+	signer.AllowedContracts = append(signer.AllowedContracts, *spender)
+	assert.Nil(t, signer.ValidateAllowedContracts(script))
+
+	// CalledByEntry covers the contracts called by the script
+	signer = Signer{Account: account, Scopes: CalledByEntry | CustomContracts}
+	assert.Nil(t, signer.ValidateAllowedContracts(script))
+}
+
+func TestSigner_Serialize_Scopes(t *testing.T) {
+	account, _ := helper.UInt160FromString("edae9b97c72dbec43a7201b6388c24bfd86c71ae")
+	contract, _ := helper.UInt160FromString("ad1344cbab4378e6bb9914769955fc4fb2742e2b")
+	group, _ := crypto.NewECPointFromString("03b209fd4f53a7170ea4444e0cb0a6bb6a53c2bd016926989cf85f9b0fba17a70c")
+	cases := []struct {
+		signer   Signer
+		expected string
+	}{
+		{
+			Signer{Account: account, Scopes: CalledByEntry},
+			"ae716cd8bf248c38b601723ac4be2dc7979baeed" + "01",
+		},
+		{
+			Signer{Account: account, Scopes: CustomGroups, AllowedGroups: []crypto.ECPoint{*group}},
+			"ae716cd8bf248c38b601723ac4be2dc7979baeed" + "20" +
+				"01" + "03b209fd4f53a7170ea4444e0cb0a6bb6a53c2bd016926989cf85f9b0fba17a70c",
+		},
+		{
+			Signer{Account: account, Scopes: CalledByEntry | CustomContracts | CustomGroups,
+				AllowedContracts: []helper.UInt160{*contract}, AllowedGroups: []crypto.ECPoint{*group}},
+			"ae716cd8bf248c38b601723ac4be2dc7979baeed" + "31" +
+				"01" + "2b2e74b24ffc5599761499bbe67843abcb4413ad" +
+				"01" + "03b209fd4f53a7170ea4444e0cb0a6bb6a53c2bd016926989cf85f9b0fba17a70c",
+		},
+	}
+	for _, c := range cases {
+		bw := io.NewBufBinaryWriter()
+		c.signer.Serialize(bw.BinaryWriter)
+		b := bw.Bytes()
+		assert.Equal(t, c.expected, helper.BytesToHex(b))
+		assert.Equal(t, c.signer.Size(), len(b))
+
+		br := io.NewBinaryReaderFromBuf(helper.HexToBytes(c.expected))
+		signer := NewDefaultSigner()
+		signer.Deserialize(br)
+		assert.Nil(t, br.Err)
+		assert.Equal(t, c.signer.Scopes, signer.Scopes)
+		assert.Equal(t, len(c.signer.AllowedContracts), len(signer.AllowedContracts))
+		assert.Equal(t, len(c.signer.AllowedGroups), len(signer.AllowedGroups))
+	}
+}
+
+func TestSigner_Deserialize_InvalidScopes(t *testing.T) {
+	for _, scopes := range []string{"81", "02", "40"} {
+		br := io.NewBinaryReaderFromBuf(helper.HexToBytes("ae716cd8bf248c38b601723ac4be2dc7979baeed" + scopes))
+		signer := NewDefaultSigner()
+		signer.Deserialize(br)
+		assert.NotNil(t, br.Err)
+	}
+}
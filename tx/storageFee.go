@@ -0,0 +1,26 @@
+package tx
+
+import "fmt"
+
+// StorageWrite is a key and value a contract puts into its storage
+type StorageWrite struct {
+	Key   []byte
+	Value []byte
+}
+
+// EstimateStorageFee returns the GAS in the smallest unit charged for the writes, each new item costs
+// (len(Key) + len(Value)) * storagePrice, where storagePrice is from Policy.getStoragePrice. Updating an
+// existing item costs less, so the result is an upper bound for the writes that overwrite items.
+func EstimateStorageFee(writes []StorageWrite, storagePrice int64) (int64, error) {
+	if storagePrice < 0 {
+		return 0, fmt.Errorf("invalid storage price: %d", storagePrice)
+	}
+	fee := int64(0)
+	for i, w := range writes {
+		if len(w.Key) == 0 {
+			return 0, fmt.Errorf("the key of write %d is empty", i)
+		}
+		fee += int64(len(w.Key)+len(w.Value)) * storagePrice
+	}
+	return fee, nil
+}
@@ -0,0 +1,31 @@
+package tx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEstimateStorageFee(t *testing.T) {
+	writes := []StorageWrite{
+		{Key: []byte("owner"), Value: make([]byte, 20)},
+		{Key: []byte{0x01, 0x02}, Value: []byte{0x64}},
+	}
+	fee, err := EstimateStorageFee(writes, DefaultFeePolicy.StoragePrice)
+	assert.Nil(t, err)
+	// (5 + 20 + 2 + 1) * 100000
+	assert.Equal(t, int64(2800000), fee)
+
+	fee, err = EstimateStorageFee(writes, 1000)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(28000), fee)
+
+	fee, err = EstimateStorageFee(nil, StoragePrice)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), fee)
+
+	_, err = EstimateStorageFee([]StorageWrite{{Value: []byte{0x01}}}, StoragePrice)
+	assert.NotNil(t, err)
+	_, err = EstimateStorageFee(writes, -1)
+	assert.NotNil(t, err)
+}
@@ -21,6 +21,7 @@ const GasTokenId = "0xd2a4cff31913016155e38e474a2c06d08be276cf"
 const GasFactor = 100000000
 const ExecFeeFactor = 30
 const FeePerByte = 1000
+const StoragePrice = 100000 // the default GAS per byte of storage in the smallest unit
 const ECDsaVerifyPrice = 1 << 15
 
 var NeoToken, _ = helper.UInt160FromString(NeoTokenId)
@@ -206,8 +207,16 @@ func (tx *Transaction) ToByteArray() []byte {
 
 // Deserialize implements Serializable interface.
 func (tx *Transaction) Deserialize(br *io.BinaryReader) {
+	tx._hash = nil
+	tx._size = 0
 	tx.DeserializeUnsigned(br)
+	if br.Err != nil {
+		return
+	}
 	tx.DeserializeWitnesses(br)
+	if br.Err == nil && len(tx.witnesses) != len(tx.signers) {
+		br.Err = fmt.Errorf("format error: witness count %d does not match signer count %d", len(tx.witnesses), len(tx.signers))
+	}
 }
 
 func (tx *Transaction) DeserializeUnsigned(br *io.BinaryReader) {
@@ -251,16 +260,17 @@ func (tx *Transaction) DeserializeUnsigned(br *io.BinaryReader) {
 func deserializeAttributes(br *io.BinaryReader, maxCount int) []ITransactionAttribute {
 	count := int(br.ReadVarUIntWithMaxLimit(uint64(maxCount)))
 	result := make([]ITransactionAttribute, count)
-	m := make(map[TransactionAttributeType]ITransactionAttribute)
+	m := make(map[TransactionAttributeType]bool)
 	for i := 0; i < count; i++ {
 		attribute := DeserializeFrom(br)
 		if attribute == nil {
 			return nil
 		}
-		if !attribute.AllowMultiple() && m[attribute.GetAttributeType()] == attribute {
+		if !attribute.AllowMultiple() && m[attribute.GetAttributeType()] {
 			br.Err = fmt.Errorf("format error: duplicate attribute")
 			return nil
 		}
+		m[attribute.GetAttributeType()] = true
 		result[i] = attribute
 	}
 	return result
@@ -273,14 +283,18 @@ func deserializeSigners(br *io.BinaryReader, maxCount int) []Signer {
 		return nil
 	}
 	result := make([]Signer, count)
-	m := make(map[helper.UInt160]Signer)
+	m := make(map[helper.UInt160]bool)
 	for i := 0; i < count; i++ {
 		signer := NewDefaultSigner()
 		signer.Deserialize(br)
-		if t, ok := m[*signer.Account]; ok && (&t).CompareTo(signer) == 0 {
+		if br.Err != nil {
+			return nil
+		}
+		if m[*signer.Account] {
 			br.Err = fmt.Errorf("format error: duplicate signer")
 			return nil
 		}
+		m[*signer.Account] = true
 		result[i] = *signer
 	}
 	return result
@@ -1,5 +1,7 @@
 package tx
 
+import "fmt"
+
 // Transaction attribute type
 type TransactionAttributeType byte
 
@@ -26,3 +28,14 @@ func (u TransactionAttributeType) IsDefined() bool {
 	}
 	return false
 }
+
+func NewTransactionAttributeTypeFromString(s string) (TransactionAttributeType, error) {
+	switch s {
+	case "HighPriority":
+		return HighPriority, nil
+	case "OracleResponse":
+		return OracleResponse, nil
+	default:
+		return 0, fmt.Errorf("not supported transaction attribute type: %s", s)
+	}
+}
@@ -18,3 +18,28 @@ func TestDeserializeFrom(t *testing.T) {
 	a := DeserializeFrom(br)
 	assert.Equal(t, OracleResponse, a.GetAttributeType())
 }
+
+func TestTransactionAttribute_Serialize(t *testing.T) {
+	cases := []struct {
+		attribute ITransactionAttribute
+		expected  string
+	}{
+		{&HighPriorityAttribute{}, "01"},
+		{&OracleResponseAttribute{Id: 1, Code: Success, Result: []byte{0x01, 0x02}},
+			"11" + "0100000000000000" + "00" + "020102"},
+		{&OracleResponseAttribute{Id: 0x0102, Code: NotFound},
+			"11" + "0201000000000000" + "14" + "00"},
+	}
+	for _, c := range cases {
+		bw := io.NewBufBinaryWriter()
+		c.attribute.Serialize(bw.BinaryWriter)
+		b := bw.Bytes()
+		assert.Equal(t, c.expected, helper.BytesToHex(b))
+		assert.Equal(t, c.attribute.GetAttributeSize(), len(b))
+
+		br := io.NewBinaryReaderFromBuf(helper.HexToBytes(c.expected))
+		a := DeserializeFrom(br)
+		assert.Nil(t, br.Err)
+		assert.Equal(t, c.attribute.GetAttributeType(), a.GetAttributeType())
+	}
+}
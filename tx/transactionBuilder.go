@@ -0,0 +1,251 @@
+package tx
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/joeqian10/neo3-gogogo/crypto"
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/keys"
+	"github.com/joeqian10/neo3-gogogo/sc"
+)
+
+// SignFunc signs the sha256 hash of the sign data of a transaction and returns the signature
+// with the encoded public key, so keys kept outside of the process (HSM, remote signer) can be used
+type SignFunc func(hash []byte) (signature []byte, publicKey []byte, err error)
+
+// TransactionBuilder collects the fields of a transaction and builds it
+type TransactionBuilder struct {
+	version         uint8
+	nonce           uint32
+	sysfee          int64
+	netfee          int64
+	validUntilBlock uint32
+	signers         []Signer
+	attributes      []ITransactionAttribute
+	script          []byte
+	signFuncs       []SignFunc
+}
+
+// NewTransactionBuilder creates a builder with a random nonce
+func NewTransactionBuilder() *TransactionBuilder {
+	b := &TransactionBuilder{
+		version:    TransactionVersion,
+		signers:    []Signer{},
+		attributes: []ITransactionAttribute{},
+		script:     []byte{},
+	}
+	rb, err := helper.GenerateRandomBytes(4)
+	if err == nil {
+		b.nonce = binary.LittleEndian.Uint32(rb)
+	}
+	return b
+}
+
+func (b *TransactionBuilder) SetNonce(value uint32) *TransactionBuilder {
+	b.nonce = value
+	return b
+}
+
+func (b *TransactionBuilder) SetSystemFee(value int64) *TransactionBuilder {
+	b.sysfee = value
+	return b
+}
+
+func (b *TransactionBuilder) SetNetworkFee(value int64) *TransactionBuilder {
+	b.netfee = value
+	return b
+}
+
+func (b *TransactionBuilder) SetValidUntilBlock(value uint32) *TransactionBuilder {
+	b.validUntilBlock = value
+	return b
+}
+
+func (b *TransactionBuilder) SetSigners(value []Signer) *TransactionBuilder {
+	b.signers = value
+	return b
+}
+
+func (b *TransactionBuilder) SetAttributes(value []ITransactionAttribute) *TransactionBuilder {
+	b.attributes = value
+	return b
+}
+
+// GetScript returns the script of the transaction to build
+func (b *TransactionBuilder) GetScript() []byte {
+	return b.script
+}
+
+// GetSystemFee returns the system fee of the transaction to build
+func (b *TransactionBuilder) GetSystemFee() int64 {
+	return b.sysfee
+}
+
+func (b *TransactionBuilder) SetScript(value []byte) *TransactionBuilder {
+	b.script = value
+	return b
+}
+
+// AddSignFunc adds a signing callback used to create a signature witness in BuildAndSign
+func (b *TransactionBuilder) AddSignFunc(f SignFunc) *TransactionBuilder {
+	b.signFuncs = append(b.signFuncs, f)
+	return b
+}
+
+// Build creates the unsigned transaction, duplicate signers or signers with conflicting scopes are rejected
+func (b *TransactionBuilder) Build() (*Transaction, error) {
+	if len(b.script) == 0 {
+		return nil, fmt.Errorf("script is empty")
+	}
+	if len(b.signers) == 0 {
+		return nil, fmt.Errorf("no signers")
+	}
+	if len(b.signers)+len(b.attributes) > MaxTransactionAttributes {
+		return nil, fmt.Errorf("too many signers and attributes")
+	}
+	if err := SignerSlice(b.signers).Validate(); err != nil {
+		return nil, err
+	}
+	trx := NewTransaction()
+	trx.SetVersion(b.version)
+	trx.SetNonce(b.nonce)
+	trx.SetSystemFee(b.sysfee)
+	trx.SetNetworkFee(b.netfee)
+	trx.SetValidUntilBlock(b.validUntilBlock)
+	trx.SetSigners(b.signers)
+	trx.SetAttributes(b.attributes)
+	trx.SetScript(b.script)
+	return trx, nil
+}
+
+// BuildAndSign creates the transaction and assembles a signature witness for every signer
+// with the signing callbacks, each signer must be the standard account of one callback
+func (b *TransactionBuilder) BuildAndSign(magic uint32) (*Transaction, error) {
+	trx, err := b.Build()
+	if err != nil {
+		return nil, err
+	}
+	hash := sha256.Sum256(GetSignData(trx, magic))
+	witnesses := make(map[helper.UInt160]Witness)
+	for _, f := range b.signFuncs {
+		signature, publicKey, err := f(hash[:])
+		if err != nil {
+			return nil, err
+		}
+		if len(signature) != 64 {
+			return nil, fmt.Errorf("invalid signature length: %d", len(signature))
+		}
+		p, err := crypto.NewECPointFromBytes(publicKey)
+		if err != nil {
+			return nil, err
+		}
+		verificationScript, err := sc.CreateSignatureRedeemScript(p)
+		if err != nil {
+			return nil, err
+		}
+		sb := sc.NewScriptBuilder()
+		sb.EmitPushBytes(signature)
+		invocationScript, err := sb.ToArray()
+		if err != nil {
+			return nil, err
+		}
+		w, err := CreateWitness(invocationScript, verificationScript)
+		if err != nil {
+			return nil, err
+		}
+		witnesses[*w.GetScriptHash()] = *w
+	}
+	result := make([]Witness, len(trx.GetSigners()))
+	for i, signer := range trx.GetSigners() {
+		w, ok := witnesses[*signer.Account]
+		if !ok {
+			return nil, fmt.Errorf("no signature for signer %s", signer.Account.String())
+		}
+		result[i] = w
+	}
+	trx.SetWitnesses(result)
+	return trx, nil
+}
+
+// BuildSignSerialize builds the transaction, signs it with the private key and returns the
+// serialized transaction in hex. The builder is not changed. sendrawtransaction takes base64,
+// so convert it before sending, e.g. crypto.Base64Encode(helper.HexToBytes(raw))
+func BuildSignSerialize(builder *TransactionBuilder, privateKey []byte, magic uint32) (string, error) {
+	pair, err := keys.NewKeyPair(privateKey)
+	if err != nil {
+		return "", err
+	}
+	b := *builder
+	b.signFuncs = append(append([]SignFunc{}, builder.signFuncs...), func(hash []byte) ([]byte, []byte, error) {
+		signature, err := pair.SignHash(hash)
+		return signature, pair.PublicKey.EncodePoint(true), err
+	})
+	trx, err := b.BuildAndSign(magic)
+	if err != nil {
+		return "", err
+	}
+	return helper.BytesToHex(trx.ToByteArray()), nil
+}
+
+// ContractCall is one dynamic call in a script. With Assert, the call must return true, otherwise
+// the transaction faults, AssertMessage is the optional fault message
+type ContractCall struct {
+	ScriptHash    *helper.UInt160
+	Operation     string
+	Args          []interface{}
+	Assert        bool
+	AssertMessage string
+}
+
+// MakeMultiCallScript makes a script doing the calls in sequence, the results of all calls not asserted are left on the stack
+func MakeMultiCallScript(calls []ContractCall) ([]byte, error) {
+	if len(calls) == 0 {
+		return nil, fmt.Errorf("no calls")
+	}
+	sb := sc.NewScriptBuilder()
+	for _, call := range calls {
+		sb.EmitDynamicCall(call.ScriptHash, call.Operation, call.Args)
+		if call.Assert {
+			sb.EmitAssert(call.AssertMessage)
+		}
+	}
+	return sb.ToArray()
+}
+
+// NewMultiCallTransactionBuilder creates a builder for a transaction doing all the calls atomically,
+// signers with CustomContracts scope get the called contracts added to their allowed contracts
+func NewMultiCallTransactionBuilder(calls []ContractCall, signers []Signer) (*TransactionBuilder, error) {
+	script, err := MakeMultiCallScript(calls)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]Signer, len(signers))
+	for i, signer := range signers {
+		if signer.Scopes&CustomContracts != 0 {
+			allowed := make([]helper.UInt160, len(signer.AllowedContracts))
+			copy(allowed, signer.AllowedContracts)
+			for _, call := range calls {
+				if !containsUInt160(allowed, call.ScriptHash) {
+					allowed = append(allowed, *call.ScriptHash)
+				}
+			}
+			if len(allowed) > MaxSubitems {
+				return nil, fmt.Errorf("too many allowed contracts for signer %s", signer.Account.String())
+			}
+			signer.AllowedContracts = allowed
+		}
+		result[i] = signer
+	}
+	return NewTransactionBuilder().SetScript(script).SetSigners(result), nil
+}
+
+func containsUInt160(hashes []helper.UInt160, hash *helper.UInt160) bool {
+	for i := range hashes {
+		if hashes[i].Equals(hash) {
+			return true
+		}
+	}
+	return false
+}
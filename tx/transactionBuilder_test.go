@@ -0,0 +1,160 @@
+package tx
+
+import (
+	"testing"
+
+	"github.com/joeqian10/neo3-gogogo/crypto"
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/io"
+	"github.com/joeqian10/neo3-gogogo/keys"
+	"github.com/joeqian10/neo3-gogogo/sc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransactionBuilder_Build(t *testing.T) {
+	_, err := NewTransactionBuilder().SetScript([]byte{byte(sc.PUSH1)}).Build()
+	assert.NotNil(t, err)
+
+	trx, err := NewTransactionBuilder().
+		SetNonce(1).
+		SetSystemFee(100).
+		SetValidUntilBlock(10).
+		SetScript([]byte{byte(sc.PUSH1)}).
+		SetSigners([]Signer{{Account: helper.UInt160Zero, Scopes: CalledByEntry}}).
+		Build()
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(1), trx.GetNonce())
+	assert.Equal(t, int64(100), trx.GetSystemFee())
+	assert.Equal(t, uint32(10), trx.GetValidUntilBlock())
+	assert.Equal(t, 0, len(trx.GetWitnesses()))
+}
+
+func TestTransactionBuilder_Build_InvalidSigners(t *testing.T) {
+	account, _ := helper.UInt160FromString("0x2916eba24e652fa006f3e5eb8f9892d2c3b00399")
+	other, _ := helper.UInt160FromString("0x8c23f196d8a1bfd103a9dcb1f9ccf0c611377d3b")
+	build := func(signers ...Signer) error {
+		_, err := NewTransactionBuilder().SetScript([]byte{byte(sc.PUSH1)}).SetSigners(signers).Build()
+		return err
+	}
+
+	assert.Nil(t, build(Signer{Account: account, Scopes: CalledByEntry}, Signer{Account: other, Scopes: Global}))
+	// the same account twice, even with different scopes
+	assert.NotNil(t, build(Signer{Account: account, Scopes: CalledByEntry}, Signer{Account: account, Scopes: CalledByEntry}))
+	assert.NotNil(t, build(Signer{Account: account, Scopes: CalledByEntry}, Signer{Account: account, Scopes: Global}))
+	// conflicting scopes
+	assert.NotNil(t, build(Signer{Account: account, Scopes: Global | CalledByEntry}))
+	assert.NotNil(t, build(Signer{Account: account, Scopes: CalledByEntry, AllowedContracts: []helper.UInt160{*other}}))
+	assert.NotNil(t, build(Signer{Account: account, Scopes: 0x02}))
+	assert.NotNil(t, build(Signer{Scopes: CalledByEntry}))
+}
+
+func TestTransactionBuilder_BuildAndSign(t *testing.T) {
+	pair, err := keys.NewKeyPair(helper.HexToBytes(keys.KeyCases[0].PrivateKey))
+	assert.Nil(t, err)
+	script, err := sc.CreateSignatureRedeemScript(pair.PublicKey)
+	assert.Nil(t, err)
+	account := helper.UInt160FromBytes(crypto.Hash160(script))
+
+	// a software signer standing in for an HSM
+	callback := func(hash []byte) ([]byte, []byte, error) {
+		signature, err := pair.SignHash(hash)
+		return signature, pair.PublicKey.EncodePoint(true), err
+	}
+
+	builder := NewTransactionBuilder().
+		SetScript([]byte{byte(sc.PUSH1)}).
+		SetSigners([]Signer{{Account: account, Scopes: CalledByEntry}})
+	_, err = builder.BuildAndSign(helper.N3Magic_MainNet)
+	assert.NotNil(t, err) // no signer callback
+
+	trx, err := builder.AddSignFunc(callback).BuildAndSign(helper.N3Magic_MainNet)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(trx.GetWitnesses()))
+	assert.Equal(t, script, trx.GetWitnesses()[0].VerificationScript)
+	assert.True(t, VerifySignatureWitness(GetSignData(trx, helper.N3Magic_MainNet), &trx.GetWitnesses()[0]))
+
+	// the witness is the same as the one created with the private key
+	local, err := CreateSignatureWitness(GetSignData(trx, helper.N3Magic_MainNet), pair)
+	assert.Nil(t, err)
+	assert.Equal(t, local.VerificationScript, trx.GetWitnesses()[0].VerificationScript)
+	assert.Equal(t, len(local.InvocationScript), len(trx.GetWitnesses()[0].InvocationScript))
+
+	trx2 := NewTransaction()
+	br := io.NewBinaryReaderFromBuf(trx.ToByteArray())
+	trx2.Deserialize(br)
+	assert.Nil(t, br.Err)
+}
+
+func TestBuildSignSerialize(t *testing.T) {
+	privateKey := helper.HexToBytes(keys.KeyCases[0].PrivateKey)
+	pair, err := keys.NewKeyPair(privateKey)
+	assert.Nil(t, err)
+	script, err := sc.CreateSignatureRedeemScript(pair.PublicKey)
+	assert.Nil(t, err)
+	account := helper.UInt160FromBytes(crypto.Hash160(script))
+
+	builder := NewTransactionBuilder().
+		SetNonce(1).
+		SetValidUntilBlock(100).
+		SetScript([]byte{byte(sc.PUSH1)}).
+		SetSigners([]Signer{{Account: account, Scopes: CalledByEntry}})
+	raw, err := BuildSignSerialize(builder, privateKey, helper.N3Magic_MainNet)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(builder.signFuncs))
+
+	trx := NewTransaction()
+	br := io.NewBinaryReaderFromBuf(helper.HexToBytes(raw))
+	trx.Deserialize(br)
+	assert.Nil(t, br.Err)
+	assert.Equal(t, uint32(1), trx.GetNonce())
+	assert.Equal(t, account, trx.GetSigners()[0].Account)
+	assert.Equal(t, 1, len(trx.GetWitnesses()))
+	assert.True(t, VerifySignatureWitness(GetSignData(trx, helper.N3Magic_MainNet), &trx.GetWitnesses()[0]))
+
+	_, err = BuildSignSerialize(builder, []byte{0x01}, helper.N3Magic_MainNet)
+	assert.NotNil(t, err)
+}
+
+func TestNewMultiCallTransactionBuilder(t *testing.T) {
+	account, _ := helper.UInt160FromString("0x2916eba24e652fa006f3e5eb8f9892d2c3b00399")
+	spender, _ := helper.UInt160FromString("0x8c23f196d8a1bfd103a9dcb1f9ccf0c611377d3b")
+	calls := []ContractCall{
+		{ScriptHash: GasToken, Operation: "approve", Args: []interface{}{account, spender, 100}},
+		{ScriptHash: spender, Operation: "transfer", Args: []interface{}{account, 100}},
+	}
+	signers := []Signer{{Account: account, Scopes: CustomContracts, AllowedContracts: []helper.UInt160{*GasToken}}}
+
+	b, err := NewMultiCallTransactionBuilder(calls, signers)
+	assert.Nil(t, err)
+	trx, err := b.Build()
+	assert.Nil(t, err)
+
+	approve, _ := sc.MakeScript(GasToken, "approve", calls[0].Args)
+	transfer, _ := sc.MakeScript(spender, "transfer", calls[1].Args)
+	assert.Equal(t, append(approve, transfer...), trx.GetScript())
+
+	// the called contracts are allowed once, the input signers are not changed
+	assert.Equal(t, []helper.UInt160{*GasToken, *spender}, trx.GetSigners()[0].AllowedContracts)
+	assert.Equal(t, 1, len(signers[0].AllowedContracts))
+
+	_, err = NewMultiCallTransactionBuilder(nil, signers)
+	assert.NotNil(t, err)
+}
+
+func TestMakeMultiCallScript_Assert(t *testing.T) {
+	account, _ := helper.UInt160FromString("0x2916eba24e652fa006f3e5eb8f9892d2c3b00399")
+	to, _ := helper.UInt160FromString("0x8c23f196d8a1bfd103a9dcb1f9ccf0c611377d3b")
+	args := []interface{}{account, to, 100, sc.Null}
+	transfer, err := sc.MakeScript(GasToken, "transfer", args)
+	assert.Nil(t, err)
+
+	script, err := MakeMultiCallScript([]ContractCall{{ScriptHash: GasToken, Operation: "transfer", Args: args, Assert: true}})
+	assert.Nil(t, err)
+	assert.Equal(t, append(transfer, byte(sc.ASSERT)), script)
+
+	script, err = MakeMultiCallScript([]ContractCall{{ScriptHash: GasToken, Operation: "transfer", Args: args, Assert: true, AssertMessage: "transfer failed"}})
+	assert.Nil(t, err)
+	assert.Equal(t, transfer, script[:len(transfer)])
+	assert.Equal(t, append([]byte{byte(sc.PUSHDATA1), 15}, []byte("transfer failed")...), script[len(transfer):len(script)-1])
+	assert.Equal(t, byte(sc.ASSERTMSG), script[len(script)-1])
+}
@@ -0,0 +1,104 @@
+package tx
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/joeqian10/neo3-gogogo/crypto"
+	"github.com/joeqian10/neo3-gogogo/helper"
+)
+
+// TransactionJson is the json of a transaction in the same shape as getrawtransaction verbose returns
+type TransactionJson struct {
+	Hash            string                   `json:"hash"` // 0x prefixed
+	Size            int                      `json:"size"`
+	Version         uint8                    `json:"version"`
+	Nonce           uint32                   `json:"nonce"`
+	Sender          string                   `json:"sender"` // address
+	SysFee          string                   `json:"sysfee"` // in the smallest unit of GAS
+	NetFee          string                   `json:"netfee"` // in the smallest unit of GAS
+	ValidUntilBlock uint32                   `json:"validuntilblock"`
+	Signers         []SignerJson             `json:"signers"`
+	Attributes      []map[string]interface{} `json:"attributes"`
+	Script          string                   `json:"script"` // base64
+	Witnesses       []WitnessJson            `json:"witnesses"`
+}
+
+type SignerJson struct {
+	Account          string   `json:"account"` // 0x prefixed script hash
+	Scopes           string   `json:"scopes"`
+	AllowedContracts []string `json:"allowedcontracts,omitempty"`
+	AllowedGroups    []string `json:"allowedgroups,omitempty"`
+}
+
+type WitnessJson struct {
+	Invocation   string `json:"invocation"`   // base64
+	Verification string `json:"verification"` // base64
+}
+
+// ToJSON outputs the transaction in the same json as the node, using the default address version
+func (tx *Transaction) ToJSON() ([]byte, error) {
+	j, err := tx.ToTransactionJson(helper.DefaultAddressVersion)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(j)
+}
+
+// ToTransactionJson converts the transaction to the json shape of the node
+func (tx *Transaction) ToTransactionJson(addressVersion byte) (*TransactionJson, error) {
+	if len(tx.signers) == 0 {
+		return nil, fmt.Errorf("transaction has no signers")
+	}
+	signers := make([]SignerJson, len(tx.signers))
+	for i, s := range tx.signers {
+		signers[i] = SignerJson{
+			Account: "0x" + s.Account.String(),
+			Scopes:  s.Scopes.String(),
+		}
+		if s.Scopes&CustomContracts != 0 {
+			signers[i].AllowedContracts = make([]string, len(s.AllowedContracts))
+			for j := range s.AllowedContracts {
+				signers[i].AllowedContracts[j] = "0x" + s.AllowedContracts[j].String()
+			}
+		}
+		if s.Scopes&CustomGroups != 0 {
+			signers[i].AllowedGroups = make([]string, len(s.AllowedGroups))
+			for j := range s.AllowedGroups {
+				signers[i].AllowedGroups[j] = s.AllowedGroups[j].String()
+			}
+		}
+	}
+	attributes := make([]map[string]interface{}, len(tx.attributes))
+	for i, a := range tx.attributes {
+		attribute := map[string]interface{}{"type": a.GetAttributeType().String()}
+		if oracle, ok := a.(*OracleResponseAttribute); ok {
+			attribute["id"] = oracle.Id
+			attribute["code"] = oracle.Code.String()
+			attribute["result"] = crypto.Base64Encode(oracle.Result)
+		}
+		attributes[i] = attribute
+	}
+	witnesses := make([]WitnessJson, len(tx.witnesses))
+	for i, w := range tx.witnesses {
+		witnesses[i] = WitnessJson{
+			Invocation:   crypto.Base64Encode(w.InvocationScript),
+			Verification: crypto.Base64Encode(w.VerificationScript),
+		}
+	}
+	return &TransactionJson{
+		Hash:            "0x" + tx.GetHash().String(),
+		Size:            tx.GetSize(),
+		Version:         tx.version,
+		Nonce:           tx.nonce,
+		Sender:          crypto.ScriptHashToAddress(tx.GetSender(), addressVersion),
+		SysFee:          strconv.FormatInt(tx.sysfee, 10),
+		NetFee:          strconv.FormatInt(tx.netfee, 10),
+		ValidUntilBlock: tx.validUntilBlock,
+		Signers:         signers,
+		Attributes:      attributes,
+		Script:          crypto.Base64Encode(tx.script),
+		Witnesses:       witnesses,
+	}, nil
+}
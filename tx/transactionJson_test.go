@@ -0,0 +1,74 @@
+package tx
+
+import (
+	"testing"
+
+	"github.com/joeqian10/neo3-gogogo/crypto"
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransaction_ToJSON(t *testing.T) {
+	script, _ := crypto.Base64Decode("AoCWmAAMFGklqlVHEkOanGE7oRTvo/rCPdvKDBSZA7DD0pKYj+vl8wagL2VOousWKRPADAh0cmFuc2ZlcgwUiXcg2M129PAKv6N8Dt2InCCP3ptBYn1bUjk=")
+	invocation, _ := crypto.Base64Decode("DEDcGjmiHJ22R4LjUuXOF83UDtJB3FUZPy4t8Ol+dSpQovI9KAfVVOrtz/NZBmEuVGXiALkJU6vklZ9XzzDrz0PJ")
+	verification, _ := crypto.Base64Decode("EQwhA6oFL7y45bM6Tu/WYlNvhoRkHwQQnx1eac3abwhIkChqEQtBMHOzuw==")
+	account, _ := helper.UInt160FromString("0x2916eba24e652fa006f3e5eb8f9892d2c3b00399")
+
+	tx := NewTransaction()
+	tx.SetNonce(1233336052)
+	tx.SetSystemFee(100000000)
+	tx.SetNetworkFee(1270450)
+	tx.SetValidUntilBlock(2102808)
+	tx.SetSigners([]Signer{*NewSigner(account, CalledByEntry)})
+	tx.SetScript(script)
+	tx.SetWitnesses([]Witness{{InvocationScript: invocation, VerificationScript: verification}})
+
+	b, err := tx.ToJSON()
+	assert.Nil(t, err)
+	// recorded from getrawtransaction verbose, without the block related fields
+	assert.JSONEq(t, `{
+		"hash": "0x5d4d8a9e4be36ed625ab65fb3632fcc4a73ef306040b6883d2917aedf761385a",
+		"size": 250,
+		"version": 0,
+		"nonce": 1233336052,
+		"sender": "NZs2zXSPuuv9ZF6TDGSWT1RBmE8rfGj7UW",
+		"sysfee": "100000000",
+		"netfee": "1270450",
+		"validuntilblock": 2102808,
+		"signers": [
+			{
+				"account": "0x2916eba24e652fa006f3e5eb8f9892d2c3b00399",
+				"scopes": "CalledByEntry"
+			}
+		],
+		"attributes": [],
+		"script": "AoCWmAAMFGklqlVHEkOanGE7oRTvo/rCPdvKDBSZA7DD0pKYj+vl8wagL2VOousWKRPADAh0cmFuc2ZlcgwUiXcg2M129PAKv6N8Dt2InCCP3ptBYn1bUjk=",
+		"witnesses": [
+			{
+				"invocation": "DEDcGjmiHJ22R4LjUuXOF83UDtJB3FUZPy4t8Ol+dSpQovI9KAfVVOrtz/NZBmEuVGXiALkJU6vklZ9XzzDrz0PJ",
+				"verification": "EQwhA6oFL7y45bM6Tu/WYlNvhoRkHwQQnx1eac3abwhIkChqEQtBMHOzuw=="
+			}
+		]
+	}`, string(b))
+}
+
+func TestTransaction_ToTransactionJson(t *testing.T) {
+	account, _ := helper.UInt160FromString("0x2916eba24e652fa006f3e5eb8f9892d2c3b00399")
+	contract, _ := helper.UInt160FromString("0xd2a4cff31913016155e38e474a2c06d08be276cf")
+	signer := NewSigner(account, CalledByEntry|CustomContracts)
+	signer.AllowedContracts = []helper.UInt160{*contract}
+
+	tx := NewTransaction()
+	tx.SetSigners([]Signer{*signer})
+	tx.SetAttributes([]ITransactionAttribute{&OracleResponseAttribute{Id: 1, Code: Success, Result: []byte{0x01}}})
+	j, err := tx.ToTransactionJson(helper.DefaultAddressVersion)
+	assert.Nil(t, err)
+	assert.Equal(t, "CalledByEntry, CustomContracts", j.Signers[0].Scopes)
+	assert.Equal(t, []string{"0xd2a4cff31913016155e38e474a2c06d08be276cf"}, j.Signers[0].AllowedContracts)
+	assert.Equal(t, "OracleResponse", j.Attributes[0]["type"])
+	assert.Equal(t, "Success", j.Attributes[0]["code"])
+	assert.Equal(t, "AQ==", j.Attributes[0]["result"])
+
+	_, err = NewTransaction().ToTransactionJson(helper.DefaultAddressVersion)
+	assert.NotNil(t, err)
+}
@@ -1,8 +1,10 @@
 package tx
 
 import (
+	"github.com/joeqian10/neo3-gogogo/crypto"
 	"github.com/joeqian10/neo3-gogogo/helper"
 	"github.com/joeqian10/neo3-gogogo/io"
+	"github.com/joeqian10/neo3-gogogo/keys"
 	"github.com/joeqian10/neo3-gogogo/sc"
 	"github.com/stretchr/testify/assert"
 	"testing"
@@ -220,3 +222,106 @@ func TestTransaction_SerializeWitnesses(t *testing.T) {
 
 	assert.Equal(t, expected, helper.BytesToHex(b))
 }
+
+func TestTransaction_Serialize_RoundTrip(t *testing.T) {
+	pair1, err := keys.NewKeyPair(helper.HexToBytes(keys.KeyCases[0].PrivateKey))
+	assert.Nil(t, err)
+	pair2, err := keys.NewKeyPair(helper.HexToBytes(keys.KeyCases[1].PrivateKey))
+	assert.Nil(t, err)
+	script1, _ := sc.CreateSignatureRedeemScript(pair1.PublicKey)
+	script2, _ := sc.CreateSignatureRedeemScript(pair2.PublicKey)
+
+	trx := NewTransaction()
+	trx.SetNonce(0x01020304)
+	trx.SetSystemFee(GasFactor)
+	trx.SetNetworkFee(2000000)
+	trx.SetValidUntilBlock(100)
+	trx.SetScript([]byte{byte(sc.PUSH1)})
+	trx.SetSigners([]Signer{
+		{Account: helper.UInt160FromBytes(crypto.Hash160(script1)), Scopes: CalledByEntry},
+		{Account: helper.UInt160FromBytes(crypto.Hash160(script2)), Scopes: Global},
+	})
+	trx.SetAttributes([]ITransactionAttribute{&HighPriorityAttribute{}})
+	msg := GetSignData(trx, helper.N3Magic_MainNet)
+	w1, err := CreateSignatureWitness(msg, pair1)
+	assert.Nil(t, err)
+	w2, err := CreateSignatureWitness(msg, pair2)
+	assert.Nil(t, err)
+	trx.SetWitnesses([]Witness{*w1, *w2})
+	raw := trx.ToByteArray()
+	assert.NotNil(t, raw)
+
+	trx2 := NewTransaction()
+	br := io.NewBinaryReaderFromBuf(raw)
+	trx2.Deserialize(br)
+	assert.Nil(t, br.Err)
+	assert.Equal(t, 2, len(trx2.GetWitnesses()))
+	assert.Equal(t, w2.InvocationScript, trx2.GetWitnesses()[1].InvocationScript)
+	assert.Equal(t, raw, trx2.ToByteArray())
+	assert.Equal(t, trx.GetHash().String(), trx2.GetHash().String())
+	assert.True(t, VerifySignatureWitness(GetSignData(trx2, helper.N3Magic_MainNet), &trx2.GetWitnesses()[0]))
+}
+
+func TestTransaction_Deserialize_WitnessCountMismatch(t *testing.T) {
+	s := "00" + // version
+		"04030201" + // nonce
+		"00e1f50500000000" + // system fee (1 GAS)
+		"0100000000000000" + // network fee (1 satoshi)
+		"04030201" + // timelimit
+		"01000000000000000000000000000000000000000000" + // empty signer
+		"00" + // no attributes
+		"0111" + // push1 script
+		"02" + "0000" + "0000" // two empty witnesses
+
+	br := io.NewBinaryReaderFromBuf(helper.HexToBytes(s))
+	tx := NewTransaction()
+	tx.Deserialize(br)
+	assert.NotNil(t, br.Err)
+}
+
+func TestTransaction_SerializeUnsigned_Signers(t *testing.T) {
+	s := "00" + // version
+		"04030201" + // nonce
+		"00e1f50500000000" + // system fee (1 GAS)
+		"0100000000000000" + // network fee (1 satoshi)
+		"04030201" + // valid until block
+		"02" + // 2 signers
+		"ae716cd8bf248c38b601723ac4be2dc7979baeed" + "10" + "01" + "2b2e74b24ffc5599761499bbe67843abcb4413ad" + // custom contracts
+		"2b2e74b24ffc5599761499bbe67843abcb4413ad" + "01" + // called by entry
+		"02" + // 2 attributes
+		"01" + // high priority
+		"11" + "0100000000000000" + "00" + "0101" + // oracle response
+		"0111" // push1 script
+
+	br := io.NewBinaryReaderFromBuf(helper.HexToBytes(s))
+	trx := NewTransaction()
+	trx.DeserializeUnsigned(br)
+	assert.Nil(t, br.Err)
+	assert.Equal(t, 2, len(trx.GetSigners()))
+	assert.Equal(t, 2, len(trx.GetAttributes()))
+
+	bw := io.NewBufBinaryWriter()
+	trx.SerializeUnsigned(bw.BinaryWriter)
+	assert.Equal(t, s, helper.BytesToHex(bw.Bytes()))
+	assert.Equal(t, len(s)/2+1, trx.GetSize()) // plus the empty witnesses
+}
+
+func TestTransaction_DeserializeUnsigned_Duplicates(t *testing.T) {
+	header := "00" + "04030201" + "00e1f50500000000" + "0100000000000000" + "04030201"
+
+	// the same account twice, even with different scopes
+	s := header + "02" +
+		"ae716cd8bf248c38b601723ac4be2dc7979baeed" + "01" +
+		"ae716cd8bf248c38b601723ac4be2dc7979baeed" + "80" +
+		"00" + "0111"
+	br := io.NewBinaryReaderFromBuf(helper.HexToBytes(s))
+	NewTransaction().DeserializeUnsigned(br)
+	assert.NotNil(t, br.Err)
+
+	// high priority twice
+	s = header + "01" + "ae716cd8bf248c38b601723ac4be2dc7979baeed" + "01" +
+		"02" + "01" + "01" + "0111"
+	br = io.NewBinaryReaderFromBuf(helper.HexToBytes(s))
+	NewTransaction().DeserializeUnsigned(br)
+	assert.NotNil(t, br.Err)
+}
@@ -208,3 +208,32 @@ func VerifyMultiSignatureWitness(msg []byte, witness *Witness) bool {
 	}
 	return keys.VerifyMultiSig(msg, signatures, pubKeys)
 }
+
+// DecodeWitness returns the signatures pushed by the invocation script and the public keys
+// in the verification script of a standard signature or multi-signature witness
+func DecodeWitness(witness *Witness) ([][]byte, []crypto.ECPoint, error) {
+	invocationScript := witness.InvocationScript
+	if len(invocationScript)%66 != 0 {
+		return nil, nil, fmt.Errorf("invalid invocation script length: %d", len(invocationScript))
+	}
+	signatures := make([][]byte, len(invocationScript)/66)
+	for i := range signatures {
+		if invocationScript[i*66] != byte(sc.PUSHDATA1) || invocationScript[i*66+1] != 64 {
+			return nil, nil, fmt.Errorf("invocation script is not a sequence of signatures")
+		}
+		signatures[i] = invocationScript[i*66+2 : i*66+66]
+	}
+
+	verificationScript := witness.VerificationScript
+	if sc.IsSignatureContract(verificationScript) {
+		publicKey, err := crypto.NewECPointFromBytes(verificationScript[2:35])
+		if err != nil {
+			return nil, nil, err
+		}
+		return signatures, []crypto.ECPoint{*publicKey}, nil
+	}
+	if ok, _, _, publicKeys := sc.IsMultiSigContract(verificationScript); ok {
+		return signatures, publicKeys, nil
+	}
+	return nil, nil, fmt.Errorf("verification script is not a standard contract")
+}
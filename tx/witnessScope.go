@@ -1,5 +1,7 @@
 package tx
 
+import "strings"
+
 type WitnessScope byte
 
 const (
@@ -30,6 +32,7 @@ func (w WitnessScope) CompareTo(other WitnessScope) int {
 	return -1
 }
 
+// String returns the name of the scope, combined flags are joined by ", " as neo does, e.g. "CalledByEntry, CustomContracts"
 func (w WitnessScope) String() string {
 	b := byte(w)
 	switch b {
@@ -43,7 +46,16 @@ func (w WitnessScope) String() string {
 		return "CustomGroups"
 	case 0x80:
 		return "Global"
-	default:
+	}
+	names := make([]string, 0)
+	for _, flag := range []WitnessScope{CalledByEntry, CustomContracts, CustomGroups, Global} {
+		if w&flag != 0 {
+			names = append(names, flag.String())
+			w &^= flag
+		}
+	}
+	if w != 0 {
 		return ""
 	}
+	return strings.Join(names, ", ")
 }
@@ -102,3 +102,42 @@ func TestVerifyMultiSignatureWitness(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, true, b)
 }
+
+func TestDecodeWitness(t *testing.T) {
+	msg := []byte("sample")
+	pair, _ := keys.NewKeyPairFromWIF(keys.KeyCases[0].Wif)
+	witness, err := CreateSignatureWitness(msg, pair)
+	assert.Nil(t, err)
+	signatures, publicKeys, err := DecodeWitness(witness)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(signatures))
+	assert.Equal(t, 1, len(publicKeys))
+	assert.True(t, publicKeys[0].Equals(pair.PublicKey))
+	assert.True(t, keys.VerifySignature(msg, signatures[0], &publicKeys[0]))
+
+	pairs := make([]keys.KeyPair, caseLen)
+	pubKeys := make([]crypto.ECPoint, caseLen)
+	for i := 0; i < caseLen; i++ {
+		pair, _ := keys.NewKeyPairFromWIF(keys.KeyCases[i].Wif)
+		pairs[i] = *pair
+		pubKeys[i] = *pair.PublicKey
+	}
+	witness, err = CreateMultiSignatureWitness(msg, pairs[:caseLen-1], caseLen-1, pubKeys)
+	assert.Nil(t, err)
+	signatures, publicKeys, err = DecodeWitness(witness)
+	assert.Nil(t, err)
+	assert.Equal(t, caseLen-1, len(signatures))
+	assert.Equal(t, caseLen, len(publicKeys))
+	for _, signature := range signatures {
+		verified := false
+		for i := range publicKeys {
+			verified = verified || keys.VerifySignature(msg, signature, &publicKeys[i])
+		}
+		assert.True(t, verified)
+	}
+
+	_, _, err = DecodeWitness(&Witness{InvocationScript: []byte{0x01}, VerificationScript: witness.VerificationScript})
+	assert.NotNil(t, err)
+	_, _, err = DecodeWitness(&Witness{InvocationScript: witness.InvocationScript, VerificationScript: []byte{0x11}})
+	assert.NotNil(t, err)
+}
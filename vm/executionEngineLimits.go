@@ -0,0 +1,17 @@
+package vm
+
+// ExecutionEngineLimits represents the restrictions on the VM
+type ExecutionEngineLimits struct {
+	MaxStackSize    int // The maximum number of items that can be contained in the VM's evaluation stacks and slots.
+	MaxItemSize     int // The maximum size of an item in the VM.
+	MaxNestingDepth int // The maximum nesting depth of a compound item when it is decoded from json.
+}
+
+// DefaultExecutionEngineLimits returns the default limits of the VM
+func DefaultExecutionEngineLimits() ExecutionEngineLimits {
+	return ExecutionEngineLimits{
+		MaxStackSize:    2 * 1024,
+		MaxItemSize:     1024 * 1024,
+		MaxNestingDepth: 64,
+	}
+}
@@ -0,0 +1,47 @@
+package vm
+
+import (
+	"fmt"
+	"strings"
+)
+
+// VMState is the state of the VM, as the "vmstate" in the application log or the "state" of an invoke result
+type VMState byte
+
+const (
+	NONE  VMState = 0 // Indicates that the execution is in progress or has not yet begun.
+	HALT  VMState = 1 // Indicates that the execution has been completed successfully.
+	FAULT VMState = 2 // Indicates that the execution has ended, and an exception that cannot be caught is thrown.
+	BREAK VMState = 4 // Indicates that a breakpoint is currently being hit.
+)
+
+// NewVMStateFromString parses the vm state, the name is case insensitive
+func NewVMStateFromString(s string) (VMState, error) {
+	switch strings.ToUpper(s) {
+	case "NONE":
+		return NONE, nil
+	case "HALT":
+		return HALT, nil
+	case "FAULT":
+		return FAULT, nil
+	case "BREAK":
+		return BREAK, nil
+	default:
+		return NONE, fmt.Errorf("unknown vm state: %s", s)
+	}
+}
+
+func (s VMState) String() string {
+	switch s {
+	case NONE:
+		return "NONE"
+	case HALT:
+		return "HALT"
+	case FAULT:
+		return "FAULT"
+	case BREAK:
+		return "BREAK"
+	default:
+		return ""
+	}
+}
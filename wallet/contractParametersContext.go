@@ -1,9 +1,12 @@
 package wallet
 
 import (
+	"encoding/json"
 	"fmt"
 	"github.com/joeqian10/neo3-gogogo/crypto"
 	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/io"
+	"github.com/joeqian10/neo3-gogogo/keys"
 	"github.com/joeqian10/neo3-gogogo/sc"
 	"github.com/joeqian10/neo3-gogogo/tx"
 	"sort"
@@ -49,6 +52,7 @@ func (s signatureHelperSlice) Swap(i, j int) {
 type ContractParametersContext struct {
 	Verifiable   tx.IVerifiable // transaction ?
 	ContextItems map[helper.UInt160]*ContextItem
+	Network      uint32 // the magic of the network the signatures are made for
 
 	scriptHashes []helper.UInt160
 }
@@ -61,7 +65,7 @@ func NewContractParametersContract(verifiable tx.IVerifiable) *ContractParameter
 }
 
 func (c *ContractParametersContext) GetCompleted() bool {
-	if len(c.ContextItems) < len(c.scriptHashes) {
+	if len(c.ContextItems) < len(c.GetScriptHashes()) {
 		return false
 	}
 	for k, v := range c.ContextItems {
@@ -152,7 +156,7 @@ func (c *ContractParametersContext) AddSignature(contract *sc.Contract, pubKey *
 			sort.Sort(sort.Reverse(signatureHelperSlice(signatureHelpers)))
 
 			for i := 0; i < len(signatureHelpers); i++ {
-				if !c.AddItemWithIndex(contract, i, signatureHelpers[i]) {
+				if !c.AddItemWithIndex(contract, i, signatureHelpers[i].Signature) {
 					return false, fmt.Errorf("invalid operation when adding item")
 				}
 			}
@@ -188,12 +192,77 @@ func (c *ContractParametersContext) AddSignature(contract *sc.Contract, pubKey *
 	}
 }
 
+// AddSignatureWithKey signs the verifiable for the Network with the key pair and inserts the signature into
+// the item of the standard account or the multi-sig account the key belongs to, the multi-sig signatures are
+// put in the order of the public keys once enough are collected. It returns whether the item has all the
+// signatures it needs, i.e. the threshold of a multi-sig account is met. The Network must be set.
+func (c *ContractParametersContext) AddSignatureWithKey(pair *keys.KeyPair) (bool, error) {
+	if pair == nil {
+		return false, fmt.Errorf("key pair is nil")
+	}
+	if c.Network == 0 {
+		return false, fmt.Errorf("network is not set")
+	}
+	contract, err := c.findContract(pair.PublicKey)
+	if err != nil {
+		return false, err
+	}
+	signature, err := Sign(c.Verifiable, pair, c.Network)
+	if err != nil {
+		return false, err
+	}
+	added, err := c.AddSignature(contract, pair.PublicKey, signature)
+	if err != nil {
+		return false, err
+	}
+	if !added {
+		return false, fmt.Errorf("the signature of %s is not added", pair.PublicKey.String())
+	}
+	return c.ContextItems[*contract.GetScriptHash()].isCompleted(), nil
+}
+
+// findContract returns the contract of the context the public key signs for, a multi-sig contract is
+// found by the scripts of the items, a standard contract by the script hashes for verifying
+func (c *ContractParametersContext) findContract(pubKey *crypto.ECPoint) (*sc.Contract, error) {
+	for _, scriptHash := range c.GetScriptHashes() {
+		item, ok := c.ContextItems[scriptHash]
+		if !ok || item.Script == nil {
+			continue
+		}
+		if b, _, points := sc.ByteSlice(item.Script).IsMultiSigContractWithPoints(); b && pubKey.ExistsIn(points) {
+			types := make([]sc.ContractParameterType, len(item.Parameters))
+			for i := range item.Parameters {
+				types[i] = item.Parameters[i].Type
+			}
+			return sc.CreateContract(types, item.Script), nil
+		}
+	}
+	contract, err := sc.CreateSignatureContract(pubKey)
+	if err != nil {
+		return nil, err
+	}
+	if !contract.GetScriptHash().ExistsIn(c.GetScriptHashes()) {
+		return nil, fmt.Errorf("the key %s is not needed by the context", pubKey.String())
+	}
+	return contract, nil
+}
+
+// isCompleted returns true if all the parameters of the item are set
+func (item *ContextItem) isCompleted() bool {
+	for _, param := range item.Parameters {
+		if param.Value == nil {
+			return false
+		}
+	}
+	return true
+}
+
 func (c *ContractParametersContext) createItem(contract *sc.Contract) *ContextItem {
 	scriptHash := *contract.GetScriptHash()
 	if item, ok := c.ContextItems[scriptHash]; ok {
 		return item
 	}
-	if !contract.GetScriptHash().ExistsIn(c.scriptHashes) {
+	if !contract.GetScriptHash().ExistsIn(c.GetScriptHashes()) {
 		return nil
 	}
 	item := NewContextItem(contract)
@@ -256,3 +325,104 @@ func (c *ContractParametersContext) GetWitnesses() ([]tx.Witness, error) {
 	}
 	return witnesses, nil
 }
+
+// transactionContextType is the type of the verifiable in the json of a context for a transaction
+const transactionContextType = "Neo.Network.P2P.Payloads.Transaction"
+
+type contextItemJson struct {
+	Script     string                 `json:"script"` // base64
+	Parameters []sc.ContractParameter `json:"parameters"`
+	Signatures map[string]string      `json:"signatures"` // public key in hex to signature in base64
+}
+
+type contractParametersContextJson struct {
+	Type    string                     `json:"type"`
+	Hash    string                     `json:"hash"`
+	Data    string                     `json:"data"` // the unsigned verifiable in base64
+	Items   map[string]contextItemJson `json:"items"`
+	Network uint32                     `json:"network"`
+}
+
+// MarshalJSON exports the context in the same format as neo-cli, so it can be passed to other signers,
+// only the context of a transaction is supported
+func (c *ContractParametersContext) MarshalJSON() ([]byte, error) {
+	trx, ok := c.Verifiable.(*tx.Transaction)
+	if !ok {
+		return nil, fmt.Errorf("only the context of a transaction is supported")
+	}
+	bw := io.NewBufBinaryWriter()
+	trx.SerializeUnsigned(bw.BinaryWriter)
+	if bw.Err != nil {
+		return nil, bw.Err
+	}
+	r := contractParametersContextJson{
+		Type:    transactionContextType,
+		Hash:    "0x" + trx.GetHash().String(),
+		Data:    crypto.Base64Encode(bw.Bytes()),
+		Items:   make(map[string]contextItemJson, len(c.ContextItems)),
+		Network: c.Network,
+	}
+	for scriptHash, item := range c.ContextItems {
+		signatures := make(map[string]string, len(item.Signatures))
+		for k, v := range item.Signatures {
+			signatures[k] = crypto.Base64Encode(v)
+		}
+		r.Items["0x"+scriptHash.String()] = contextItemJson{
+			Script:     crypto.Base64Encode(item.Script),
+			Parameters: item.Parameters,
+			Signatures: signatures,
+		}
+	}
+	return json.Marshal(r)
+}
+
+// UnmarshalJSON imports the context exported by MarshalJSON or neo-cli
+func (c *ContractParametersContext) UnmarshalJSON(data []byte) error {
+	var r contractParametersContextJson
+	if err := json.Unmarshal(data, &r); err != nil {
+		return err
+	}
+	if r.Type != transactionContextType {
+		return fmt.Errorf("not supported verifiable type: %s", r.Type)
+	}
+	b, err := crypto.Base64Decode(r.Data)
+	if err != nil {
+		return fmt.Errorf("invalid data: %v", err)
+	}
+	trx := tx.NewTransaction()
+	br := io.NewBinaryReaderFromBuf(b)
+	trx.DeserializeUnsigned(br)
+	if br.Err != nil {
+		return fmt.Errorf("invalid data: %v", br.Err)
+	}
+	if len(r.Hash) != 0 {
+		hash, err := helper.UInt256FromString(r.Hash)
+		if err != nil || !hash.Equals(trx.GetHash()) {
+			return fmt.Errorf("hash mismatch: %s", r.Hash)
+		}
+	}
+	items := make(map[helper.UInt160]*ContextItem, len(r.Items))
+	for k, v := range r.Items {
+		scriptHash, err := helper.UInt160FromString(k)
+		if err != nil {
+			return fmt.Errorf("invalid script hash: %s", k)
+		}
+		script, err := crypto.Base64Decode(v.Script)
+		if err != nil {
+			return fmt.Errorf("invalid script of %s: %v", k, err)
+		}
+		signatures := make(map[string][]byte, len(v.Signatures))
+		for pubKey, sig := range v.Signatures {
+			signatures[pubKey], err = crypto.Base64Decode(sig)
+			if err != nil {
+				return fmt.Errorf("invalid signature of %s: %v", pubKey, err)
+			}
+		}
+		items[*scriptHash] = &ContextItem{Script: script, Parameters: v.Parameters, Signatures: signatures}
+	}
+	c.Verifiable = trx
+	c.ContextItems = items
+	c.Network = r.Network
+	c.scriptHashes = trx.GetScriptHashesForVerifying()
+	return nil
+}
@@ -0,0 +1,133 @@
+package wallet
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/joeqian10/neo3-gogogo/crypto"
+	"github.com/joeqian10/neo3-gogogo/helper"
+	"github.com/joeqian10/neo3-gogogo/keys"
+	"github.com/joeqian10/neo3-gogogo/sc"
+	"github.com/joeqian10/neo3-gogogo/tx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContractParametersContext_AddSignatureWithKey(t *testing.T) {
+	pair1, err := keys.NewKeyPairFromWIF(keys.KeyCases[0].Wif)
+	assert.Nil(t, err)
+	pair2, err := keys.NewKeyPairFromWIF(keys.KeyCases[1].Wif)
+	assert.Nil(t, err)
+	multi, err := sc.CreateMultiSigContract(2, []crypto.ECPoint{*pair1.PublicKey, *pair2.PublicKey})
+	assert.Nil(t, err)
+
+	trx := tx.NewTransaction()
+	trx.SetNonce(1)
+	trx.SetValidUntilBlock(100)
+	trx.SetScript([]byte{byte(sc.PUSH1)})
+	trx.SetSigners([]tx.Signer{{Account: multi.GetScriptHash(), Scopes: tx.CalledByEntry}})
+
+	// the initiator adds the multi-sig contract and exports the context
+	ctx := NewContractParametersContract(trx)
+	ctx.Network = helper.N3Magic_TestNet
+	assert.True(t, ctx.AddItemWithParams(multi, nil))
+	exported, err := json.Marshal(ctx)
+	assert.Nil(t, err)
+
+	// the first cosigner imports, signs and exports
+	ctx1 := new(ContractParametersContext)
+	assert.Nil(t, json.Unmarshal(exported, ctx1))
+	completed, err := ctx1.AddSignatureWithKey(pair2)
+	assert.Nil(t, err)
+	assert.False(t, completed)
+	assert.False(t, ctx1.GetCompleted())
+	_, err = ctx1.AddSignatureWithKey(pair2)
+	assert.NotNil(t, err)
+	exported, err = json.Marshal(ctx1)
+	assert.Nil(t, err)
+
+	// the second cosigner imports and completes
+	ctx2 := new(ContractParametersContext)
+	assert.Nil(t, json.Unmarshal(exported, ctx2))
+	assert.Equal(t, helper.N3Magic_TestNet, ctx2.Network)
+	assert.Equal(t, 1, len(ctx2.GetSignatures(multi.GetScriptHash())))
+	completed, err = ctx2.AddSignatureWithKey(pair1)
+	assert.Nil(t, err)
+	assert.True(t, completed)
+	assert.True(t, ctx2.GetCompleted())
+
+	witnesses, err := ctx2.GetWitnesses()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(witnesses))
+	assert.Equal(t, multi.Script, witnesses[0].VerificationScript)
+	signatures, points, err := tx.DecodeWitness(&witnesses[0])
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(signatures))
+	message := tx.GetSignData(ctx2.Verifiable, helper.N3Magic_TestNet)
+	assert.True(t, keys.VerifyMultiSig(message, signatures, points))
+
+	// a key not needed by the context
+	pair3, err := keys.NewKeyPairFromWIF(keys.KeyCases[2].Wif)
+	assert.Nil(t, err)
+	_, err = ctx2.AddSignatureWithKey(pair3)
+	assert.NotNil(t, err)
+}
+
+func TestContractParametersContext_AddSignatureWithKey_Standard(t *testing.T) {
+	pair, err := keys.NewKeyPairFromWIF(keys.KeyCases[0].Wif)
+	assert.Nil(t, err)
+	contract, err := sc.CreateSignatureContract(pair.PublicKey)
+	assert.Nil(t, err)
+
+	trx := tx.NewTransaction()
+	trx.SetScript([]byte{byte(sc.PUSH1)})
+	trx.SetSigners([]tx.Signer{{Account: contract.GetScriptHash(), Scopes: tx.CalledByEntry}})
+	ctx := NewContractParametersContract(trx)
+	_, err = ctx.AddSignatureWithKey(pair)
+	assert.NotNil(t, err)
+	ctx.Network = helper.N3Magic_TestNet
+	completed, err := ctx.AddSignatureWithKey(pair)
+	assert.Nil(t, err)
+	assert.True(t, completed)
+	witnesses, err := ctx.GetWitnesses()
+	assert.Nil(t, err)
+	assert.Equal(t, contract.Script, witnesses[0].VerificationScript)
+}
+
+func TestContractParametersContext_UnmarshalJSON_MissingItem(t *testing.T) {
+	pair1, err := keys.NewKeyPairFromWIF(keys.KeyCases[0].Wif)
+	assert.Nil(t, err)
+	pair2, err := keys.NewKeyPairFromWIF(keys.KeyCases[1].Wif)
+	assert.Nil(t, err)
+	contract1, err := sc.CreateSignatureContract(pair1.PublicKey)
+	assert.Nil(t, err)
+	contract2, err := sc.CreateSignatureContract(pair2.PublicKey)
+	assert.Nil(t, err)
+
+	trx := tx.NewTransaction()
+	trx.SetScript([]byte{byte(sc.PUSH1)})
+	trx.SetSigners([]tx.Signer{
+		{Account: contract1.GetScriptHash(), Scopes: tx.CalledByEntry},
+		{Account: contract2.GetScriptHash(), Scopes: tx.CalledByEntry},
+	})
+	ctx := NewContractParametersContract(trx)
+	ctx.Network = helper.N3Magic_TestNet
+	_, err = ctx.AddSignatureWithKey(pair1)
+	assert.Nil(t, err)
+	exported, err := json.Marshal(ctx)
+	assert.Nil(t, err)
+
+	// the item of the second signer is missing
+	imported := new(ContractParametersContext)
+	assert.Nil(t, json.Unmarshal(exported, imported))
+	assert.False(t, imported.GetCompleted())
+	_, err = imported.GetWitnesses()
+	assert.NotNil(t, err)
+	assert.Equal(t, 2, len(imported.GetScriptHashes()))
+
+	_, err = imported.AddSignatureWithKey(pair2)
+	assert.Nil(t, err)
+	assert.True(t, imported.GetCompleted())
+	witnesses, err := imported.GetWitnesses()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(witnesses))
+}
@@ -1,6 +1,7 @@
 package wallet
 
 import (
+	"encoding/json"
 	"github.com/joeqian10/neo3-gogogo/crypto"
 	"github.com/joeqian10/neo3-gogogo/helper"
 	"github.com/joeqian10/neo3-gogogo/keys"
@@ -156,6 +157,45 @@ func GenerateNEP6Account(password string) (*NEP6Account, error) {
 	return acc, nil
 }
 
+// PrivateKeyToNEP6AccountJson produces the NEP-6 account json of the standard signature account of the private key,
+// the key is encrypted to NEP-2 with the passphrase and the scrypt parameters
+func PrivateKeyToNEP6AccountJson(privateKey []byte, passphrase string, label string, isDefault bool, settings *helper.ProtocolSettings, scrypt *ScryptParameters) ([]byte, error) {
+	if settings == nil {
+		settings = &helper.DefaultProtocolSettings
+	}
+	if scrypt == nil {
+		scrypt = DefaultScryptParameters
+	}
+	pair, err := keys.NewKeyPair(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	script, err := sc.CreateSignatureRedeemScript(pair.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	contract, err := NewNEP6Contract(script, []sc.ContractParameterType{sc.Signature}, []string{"signature"}, false)
+	if err != nil {
+		return nil, err
+	}
+	nep2Key, err := pair.ExportWithPassword(passphrase, settings.AddressVersion, scrypt.N, scrypt.R, scrypt.P)
+	if err != nil {
+		return nil, err
+	}
+	account := NEP6Account{
+		protocolSettings: settings,
+		scriptHash:       contract.GetScriptHash(),
+		Address:          crypto.ScriptHashToAddress(contract.GetScriptHash(), settings.AddressVersion),
+		IsDefault:        isDefault,
+		Nep2Key:          &nep2Key,
+		Contract:         contract,
+	}
+	if len(label) != 0 {
+		account.Label = &label
+	}
+	return json.Marshal(account)
+}
+
 func (a *NEP6Account) VerifyPassword(password string) bool {
 	_, err := GetPrivateKeyFromNEP2(*a.Nep2Key, password, a.protocolSettings.AddressVersion, a.wallet.Scrypt.N, a.wallet.Scrypt.R, a.wallet.Scrypt.P)
 	if err != nil {
@@ -1,6 +1,8 @@
 package wallet
 
 import (
+	"encoding/json"
+	"github.com/joeqian10/neo3-gogogo/crypto"
 	"github.com/joeqian10/neo3-gogogo/helper"
 	"github.com/joeqian10/neo3-gogogo/sc"
 	"testing"
@@ -69,3 +71,22 @@ func TestNEP6Account_VerifyPassword(t *testing.T) {
 	assert.Equal(t, true, account.VerifyPassword(password))
 	assert.Equal(t, false, account.VerifyPassword("b"))
 }
+
+func TestPrivateKeyToNEP6AccountJson(t *testing.T) {
+	b, err := PrivateKeyToNEP6AccountJson(privateKey, password, "test", true, &helper.DefaultProtocolSettings, NewScryptParameters(2, 1, 1))
+	assert.Nil(t, err)
+
+	account := NEP6Account{}
+	err = json.Unmarshal(b, &account)
+	assert.Nil(t, err)
+	assert.Equal(t, crypto.ScriptHashToAddress(testScriptHash, helper.DefaultAddressVersion), account.Address)
+	assert.Equal(t, "test", account.GetLabel())
+	assert.Equal(t, true, account.IsDefault)
+	assert.Equal(t, testContract.Script, account.Contract.GetScript())
+	assert.Equal(t, "signature", account.Contract.Parameters[0].Name)
+	assert.Equal(t, "Signature", account.Contract.Parameters[0].Type)
+
+	priKey, err := GetPrivateKeyFromNEP2(*account.Nep2Key, password, helper.DefaultAddressVersion, 2, 1, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, privateKey, priKey)
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/joeqian10/neo3-gogogo/sc"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/joeqian10/neo3-gogogo/keys"
 )
@@ -326,6 +327,57 @@ func (w *NEP6Wallet) VerifyPassword(password string) bool {
 	}
 }
 
+// AccountAddress is the address and the label of an account
+type AccountAddress struct {
+	Address string
+	Label   string
+}
+
+// Addresses lists the address and the label of every account in the wallet,
+// it reads the stored account data only, so the wallet does not need to be unlocked
+func (w *NEP6Wallet) Addresses() []AccountAddress {
+	result := make([]AccountAddress, len(w.Accounts))
+	for i := range w.Accounts {
+		result[i] = AccountAddress{
+			Address: w.Accounts[i].GetAddress(),
+			Label:   w.Accounts[i].GetLabel(),
+		}
+	}
+	return result
+}
+
+// Validate checks the wallet version and the scrypt parameters are supported,
+// so a third-party wallet can be rejected before decrypting any key
+func (w *NEP6Wallet) Validate() error {
+	major, err := parseWalletMajorVersion(w.Version)
+	if err != nil {
+		return err
+	}
+	if major != 3 {
+		return fmt.Errorf("unsupported wallet version: %s, only %s is supported", w.Version, Neo3WalletVersion)
+	}
+	if w.Scrypt == nil {
+		return fmt.Errorf("scrypt parameters are missing")
+	}
+	return w.Scrypt.Validate()
+}
+
+// parseWalletMajorVersion parses the major part of a "major.minor" version string
+func parseWalletMajorVersion(version string) (int, error) {
+	parts := strings.Split(version, ".")
+	if len(parts) != 2 {
+		return 0, fmt.Errorf("invalid wallet version: %s", version)
+	}
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, fmt.Errorf("invalid wallet version: %s", version)
+	}
+	if _, err = strconv.Atoi(parts[1]); err != nil {
+		return 0, fmt.Errorf("invalid wallet version: %s", version)
+	}
+	return major, nil
+}
+
 // JSON outputs a pretty JSON representation of the wallet.
 func (w *NEP6Wallet) JSON() ([]byte, error) {
 	return json.Marshal(w)
@@ -55,6 +55,37 @@ func TestNewNEP6Wallet(t *testing.T) {
 	assert.Equal(t, DefaultScryptParameters.P, wallet.Scrypt.P)
 }
 
+func TestNEP6Wallet_Addresses(t *testing.T) {
+	wallet, err := NewNEP6Wallet("testMulti.json", &helper.DefaultProtocolSettings, nil, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []AccountAddress{
+		{Address: "NRNHsLqbKYBQeVQ1TRyZrLbudi6HACjUaT", Label: ""},
+		{Address: "NeGMBsEZ44B52dEvnm73ZU2QqLoRmTzMfr", Label: "savings"},
+		{Address: "NeU4hLo5Lgkp4RkZnMbQjbNSLtYYxJ6eZG", Label: "watch only"},
+	}, wallet.Addresses())
+}
+
+func TestNEP6Wallet_Validate(t *testing.T) {
+	wallet, err := NewNEP6Wallet("test.json", &helper.DefaultProtocolSettings, nil, nil)
+	assert.Nil(t, err)
+	assert.Nil(t, wallet.Validate())
+
+	wallet.Version = "1.0"
+	assert.NotNil(t, wallet.Validate())
+	wallet.Version = "3"
+	assert.NotNil(t, wallet.Validate())
+
+	wallet.Version = "3.0"
+	wallet.Scrypt = NewScryptParameters(1000, 8, 8)
+	assert.NotNil(t, wallet.Validate())
+	wallet.Scrypt = NewScryptParameters(16384, 0, 8)
+	assert.NotNil(t, wallet.Validate())
+	wallet.Scrypt = NewScryptParameters(16384, 1<<15, 1<<15)
+	assert.NotNil(t, wallet.Validate())
+	wallet.Scrypt = nil
+	assert.NotNil(t, wallet.Validate())
+}
+
 func TestGetPrivateKeyFromNEP2(t *testing.T) {
 	pk, err := GetPrivateKeyFromNEP2("3vQB7B6MrGQZaxCuFg4oh", "TestGetPrivateKeyFromNEP2", helper.DefaultAddressVersion,2, 1, 1)
 	assert.NotNil(t, err)
@@ -1,5 +1,7 @@
 package wallet
 
+import "fmt"
+
 // ScryptParameters is a json-serializable container for scrypt KDF parameters.
 type ScryptParameters struct {
 	N int `json:"n"`
@@ -16,3 +18,20 @@ func NewScryptParameters(n int, r int, p int) *ScryptParameters {
 		P: p,
 	}
 }
+
+// Validate checks the parameters are accepted by scrypt: N is a power of 2 greater than 1,
+// R and P are positive and R * P < 2^30
+func (p *ScryptParameters) Validate() error {
+	if p.N <= 1 || p.N&(p.N-1) != 0 {
+		return fmt.Errorf("scrypt N must be a power of 2 greater than 1: %d", p.N)
+	}
+	if p.R <= 0 || p.P <= 0 {
+		return fmt.Errorf("scrypt r and p must be positive: r = %d, p = %d", p.R, p.P)
+	}
+	if uint64(p.R)*uint64(p.P) >= 1<<30 || p.R > maxInt/128/p.P || p.N > maxInt/128/p.R {
+		return fmt.Errorf("scrypt parameters are too large: n = %d, r = %d, p = %d", p.N, p.R, p.P)
+	}
+	return nil
+}
+
+const maxInt = int(^uint(0) >> 1)
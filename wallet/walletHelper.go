@@ -181,26 +181,9 @@ func (w *WalletHelper) CalculateNetworkFee(trx *tx.Transaction) (uint64, error)
 				return 0, err
 			}
 			nf += uint64(gasConsumed)
-		} else if sc.IsSignatureContract(witness_script) {
-			size += 67 + sc.ByteSlice(witness_script).GetVarSize()
-			nf += uint64(exec_fee_factor * (sc.OpCodePrices[sc.PUSHDATA1] + sc.OpCodePrices[sc.PUSHDATA1] + sc.OpCodePrices[sc.SYSCALL] + tx.ECDsaVerifyPrice))
-		} else if b, m, n, _ := sc.IsMultiSigContract(witness_script); b {
-			size_inv := 66 * m
-			size += helper.GetVarSize(size_inv) + size_inv + sc.ByteSlice(witness_script).GetVarSize()
-
-			nf += uint64(exec_fee_factor * sc.OpCodePrices[sc.PUSHDATA1] * int64(m))
-			sb := sc.NewScriptBuilder()
-			sb.EmitPushInteger(m)
-			script, _ := sb.ToArray()
-			nf += uint64(exec_fee_factor * sc.OpCodePrices[sc.OpCode(script[0])])
-
-			nf += uint64(exec_fee_factor * sc.OpCodePrices[sc.PUSHDATA1] * int64(n))
-			sb = sc.NewScriptBuilder()
-			sb.EmitPushInteger(n)
-			script, _ = sb.ToArray()
-			nf += uint64(exec_fee_factor * sc.OpCodePrices[sc.OpCode(script[0])])
-
-			nf += uint64(exec_fee_factor * (sc.OpCodePrices[sc.SYSCALL] + int64(tx.ECDsaVerifyPrice*n)))
+		} else if witnessSize, witnessFee, err := tx.GetWitnessCost(witness_script, exec_fee_factor); err == nil {
+			size += witnessSize
+			nf += uint64(witnessFee)
 		} else {
 			// support more cotnract types in the future
 		}
@@ -344,14 +327,31 @@ func (w *WalletHelper) GetGasConsumed(script []byte, signers []models.RpcSigner)
 	if response.HasError() {
 		return 0, fmt.Errorf(response.GetErrorInfo())
 	}
-	if response.Result.State == "FAULT" {
+	if response.Result.GetState() == "FAULT" {
 		return 0, fmt.Errorf("engine faulted: %s", response.Result.Exception)
 	}
-	gasConsumed, err := strconv.ParseInt(response.Result.GasConsumed, 10, 64)
+	return response.Result.GetGasConsumed()
+}
+
+// EstimateDeployFee test runs the deployment of the nef and manifest by sender and returns
+// the system fee needed, it fails if the test deployment faults, e.g. the contract already exists
+func (w *WalletHelper) EstimateDeployFee(sender *helper.UInt160, nef []byte, manifest string, data interface{}) (int64, error) {
+	script, _, err := sc.DeployScript(sender, nef, manifest, data)
 	if err != nil {
 		return 0, err
 	}
-	return gasConsumed, nil
+	signers := []models.RpcSigner{{
+		Account: sender.String(),
+		Scopes:  tx.CalledByEntry.String(),
+	}}
+	response := w.Client.InvokeScript(crypto.Base64Encode(script), signers)
+	if response.HasError() {
+		return 0, fmt.Errorf(response.GetErrorInfo())
+	}
+	if response.Result.GetState() == "FAULT" {
+		return 0, fmt.Errorf("test deployment faulted: %s", response.Result.Exception)
+	}
+	return response.Result.GetGasConsumed()
 }
 
 // GetUnClaimedGas gets the amount of unclaimed gas in the wallet
@@ -1,9 +1,11 @@
 package wallet
 
 import (
+	"github.com/joeqian10/neo3-gogogo/crypto"
 	"github.com/joeqian10/neo3-gogogo/helper"
 	"github.com/joeqian10/neo3-gogogo/rpc"
 	"github.com/joeqian10/neo3-gogogo/rpc/models"
+	"github.com/joeqian10/neo3-gogogo/sc"
 	"github.com/stretchr/testify/mock"
 	"math/big"
 	"testing"
@@ -553,6 +555,45 @@ func TestWalletHelper_GetGasConsumed(t *testing.T) {
 	resetTestWallet()
 }
 
+func TestWalletHelper_EstimateDeployFee(t *testing.T) {
+	sender, _ := helper.UInt160FromString("0x2916eba24e652fa006f3e5eb8f9892d2c3b00399")
+	nef := []byte{0x4e, 0x45, 0x46, 0x33}
+	nef = append(nef, make([]byte, 69)...)
+	nef = append(nef, 0x02, byte(sc.PUSH1), byte(sc.RET))
+	nef = append(nef, helper.Hash256(nef).ToByteArray()[:4]...)
+	manifest := `{"name":"Test"}`
+	script, _, err := sc.DeployScript(sender, nef, manifest, nil)
+	assert.Nil(t, err)
+
+	var clientMock = new(rpc.RpcClientMock)
+	clientMock.On("InvokeScript", crypto.Base64Encode(script), mock.Anything).Return(rpc.InvokeResultResponse{
+		Result: models.InvokeResult{
+			Script:      crypto.Base64Encode(script),
+			State:       "HALT",
+			GasConsumed: "1001354530",
+			Stack:       []models.InvokeStack{{Type: "Array", Value: []interface{}{}}},
+		},
+	}).Once()
+	clientMock.On("InvokeScript", crypto.Base64Encode(script), mock.Anything).Return(rpc.InvokeResultResponse{
+		Result: models.InvokeResult{
+			Script:      crypto.Base64Encode(script),
+			State:       "FAULT",
+			GasConsumed: "1000516270",
+			Exception:   "Contract Already Exists: 0x8c23f196d8a1bfd103a9dcb1f9ccf0c611377d3b",
+			Stack:       []models.InvokeStack{},
+		},
+	}).Once()
+	wh := NewWalletHelperFromWallet(clientMock, testWallet)
+
+	fee, err := wh.EstimateDeployFee(sender, nef, manifest, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1001354530), fee)
+
+	_, err = wh.EstimateDeployFee(sender, nef, manifest, nil)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "Contract Already Exists")
+}
+
 func TestWalletHelper_GetUnClaimedGas(t *testing.T) {
 	var clientMock = new(rpc.RpcClientMock)
 	clientMock.On("GetUnclaimedGas", mock.Anything).Return(rpc.GetUnclaimedGasResponse{